@@ -0,0 +1,248 @@
+// Package ake provides a mutually authenticated key exchange built from key
+// encapsulation mechanisms only.
+//
+// Both parties own a static KEM key pair and know the static public key of
+// their peer beforehand. Authentication is implicit in the ability to
+// decapsulate: a party that doesn't hold the expected private key can't
+// derive the session key. Explicit key confirmation is then added by
+// exchanging MACs over the transcript, so each party knows its peer holds
+// the session key before using it. An ephemeral KEM key pair generated by
+// the initiator provides forward secrecy.
+//
+// This is the 3-KEM construction of Fujioka et al. [1], made explicitly
+// authenticated with key confirmation as in the KEMTLS handshake [2]. It
+// avoids signatures, which are large for post-quantum schemes.
+//
+// # Protocol Overview
+//
+//	Initiator(skI, pkR)                             Responder(skR, pkI)
+//	===================================================================
+//	(pkE, skE) = Ephemeral.GenerateKeyPair()
+//	(ctR, ssR) = Encapsulate(pkR)
+//
+//	                           pkE, ctR
+//	                         ---------->
+//
+//	                             ssR = Decapsulate(skR, ctR)
+//	                             (ctI, ssI) = Encapsulate(pkI)
+//	                             (ctE, ssE) = Ephemeral.Encapsulate(pkE)
+//	                             finR = MAC(kR, TH2)
+//
+//	                        ctI, ctE, finR
+//	                         <----------
+//
+//	ssI = Decapsulate(skI, ctI)
+//	ssE = Ephemeral.Decapsulate(skE, ctE)
+//	check finR
+//	finI = MAC(kI, TH3)
+//
+//	                            finI
+//	                         ---------->
+//
+//	                             check finI
+//
+// where TH2 and TH3 are hashes of the transcript up to ctE and finR
+// respectively, and kR, kI and the session key are derived with HKDF from
+// ssR, ssI, ssE and the transcript.
+//
+// Both parties are driven by state machines that consume an incoming
+// message and return the next outgoing one, so they can be used over any
+// transport.
+//
+// # References
+//
+// [1] Fujioka, Suzuki, Xagawa, Yoneyama. Strongly Secure Authenticated Key
+// Exchange from Factoring, Codes, and Lattices. https://doi.org/10.1007/s10623-013-9857-z
+//
+// [2] Schwabe, Stebila, Wiggers. Post-Quantum TLS Without Handshake
+// Signatures. https://ia.cr/2020/534
+package ake
+
+import (
+	"crypto"
+	"crypto/hmac"
+	cryptoRand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/cloudflare/circl/kem"
+)
+
+const (
+	versionLabel    = "CIRCL-AKE-v1"
+	initiatorLabel  = "initiator finished"
+	responderLabel  = "responder finished"
+	sessionKeyLabel = "session key"
+	defaultHash     = crypto.SHA256
+)
+
+var (
+	// ErrState is returned when a party is used out of order, or after it
+	// failed or finished.
+	ErrState = errors.New("ake: invalid state")
+
+	// ErrMessage is returned when a received message is malformed.
+	ErrMessage = errors.New("ake: malformed message")
+
+	// ErrAuthentication is returned when the key confirmation MAC of the
+	// peer is invalid: the peer doesn't hold the expected private key, or
+	// a message was modified.
+	ErrAuthentication = errors.New("ake: authentication failed")
+
+	// ErrConfig is returned when the configuration or the keys are invalid.
+	ErrConfig = errors.New("ake: invalid configuration")
+)
+
+// Config holds the parameters of the key exchange. Both parties must use
+// the same configuration.
+type Config struct {
+	// Scheme is the KEM of the static keys.
+	Scheme kem.Scheme
+
+	// Ephemeral is the KEM used for forward secrecy. If nil, Scheme is
+	// used.
+	Ephemeral kem.Scheme
+
+	// Hash is the hash function of the transcript, of HKDF and of HMAC.
+	// If zero, SHA-256 is used.
+	Hash crypto.Hash
+
+	// Context is bound to the session key, so that both parties must
+	// agree on it. It may identify the application or the parties.
+	Context []byte
+
+	// Rand is the source of randomness. If nil, crypto/rand.Reader is
+	// used.
+	Rand io.Reader
+}
+
+func (c *Config) ephemeral() kem.Scheme {
+	if c.Ephemeral == nil {
+		return c.Scheme
+	}
+	return c.Ephemeral
+}
+
+func (c *Config) hash() crypto.Hash {
+	if c.Hash == 0 {
+		return defaultHash
+	}
+	return c.Hash
+}
+
+func (c *Config) rand() io.Reader {
+	if c.Rand == nil {
+		return cryptoRand.Reader
+	}
+	return c.Rand
+}
+
+// check verifies that the configuration is usable, and that sk and peer
+// are keys of its static KEM.
+func (c *Config) check(sk kem.PrivateKey, peer kem.PublicKey) error {
+	if c == nil || c.Scheme == nil || sk == nil || peer == nil ||
+		!c.hash().Available() ||
+		sk.Scheme().Name() != c.Scheme.Name() ||
+		peer.Scheme().Name() != c.Scheme.Name() {
+		return ErrConfig
+	}
+	return nil
+}
+
+// Message sizes of the handshake.
+func (c *Config) messageOneSize() int {
+	return c.ephemeral().PublicKeySize() + c.Scheme.CiphertextSize()
+}
+
+func (c *Config) messageTwoSize() int {
+	return c.Scheme.CiphertextSize() + c.ephemeral().CiphertextSize() +
+		c.hash().Size()
+}
+
+func (c *Config) messageThreeSize() int { return c.hash().Size() }
+
+// transcript accumulates the messages of the handshake, together with the
+// parameters and identities both parties must agree on.
+type transcript struct {
+	h hash.Hash
+}
+
+func newTranscript(c *Config, pkI, pkR kem.PublicKey) (*transcript, error) {
+	t := &transcript{c.hash().New()}
+	t.write([]byte(versionLabel))
+	t.write([]byte(c.Scheme.Name()))
+	t.write([]byte(c.ephemeral().Name()))
+	t.write(c.Context)
+	for _, pk := range []kem.PublicKey{pkI, pkR} {
+		ppk, err := pk.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		t.write(ppk)
+	}
+	return t, nil
+}
+
+// write adds a length-prefixed string to the transcript.
+func (t *transcript) write(b []byte) {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+	t.h.Write(l[:])
+	t.h.Write(b)
+}
+
+// sum returns the hash of the transcript so far.
+func (t *transcript) sum() []byte { return t.h.Sum(nil) }
+
+// keySchedule derives the confirmation and session keys from the shared
+// secrets of the three encapsulations.
+type keySchedule struct {
+	c   *Config
+	prk []byte
+}
+
+func newKeySchedule(c *Config, th1, ssR, ssI, ssE []byte) *keySchedule {
+	ikm := make([]byte, 0, len(ssR)+len(ssI)+len(ssE))
+	ikm = append(append(append(ikm, ssR...), ssI...), ssE...)
+	return &keySchedule{c, hkdf.Extract(c.hash().New, ikm, th1)}
+}
+
+func (k *keySchedule) expand(label string, th []byte) []byte {
+	info := append([]byte(label), th...)
+	out := make([]byte, k.c.hash().Size())
+	_, err := io.ReadFull(hkdf.Expand(k.c.hash().New, k.prk, info), out)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func (k *keySchedule) mac(label string, th []byte) []byte {
+	m := hmac.New(k.c.hash().New, k.expand(label, nil))
+	m.Write(th)
+	return m.Sum(nil)
+}
+
+// encapsulate encapsulates to pk using a seed read from the configured
+// source of randomness.
+func encapsulate(c *Config, s kem.Scheme, pk kem.PublicKey) (ct, ss []byte, err error) {
+	seed := make([]byte, s.EncapsulationSeedSize())
+	if _, err = io.ReadFull(c.rand(), seed); err != nil {
+		return nil, nil, err
+	}
+	return s.EncapsulateDeterministically(pk, seed)
+}
+
+type state int
+
+const (
+	stateStart state = iota
+	stateWaitMessageTwo
+	stateWaitMessageThree
+	stateDone
+	stateFailed
+)
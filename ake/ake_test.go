@@ -0,0 +1,234 @@
+package ake_test
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/ake"
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/schemes"
+)
+
+type keyPair struct {
+	pk kem.PublicKey
+	sk kem.PrivateKey
+}
+
+func newKeyPair(t testing.TB, s kem.Scheme) keyPair {
+	pk, sk, err := s.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair")
+	return keyPair{pk, sk}
+}
+
+// handshake runs the key exchange, and returns the first error hit.
+func handshake(
+	t testing.TB, ci, cr *ake.Config, i, r keyPair, peerI, peerR kem.PublicKey,
+) (*ake.Initiator, *ake.Responder, error) {
+	ini, err := ake.NewInitiator(ci, i.sk, peerI)
+	test.CheckNoErr(t, err, "NewInitiator")
+	res, err := ake.NewResponder(cr, r.sk, peerR)
+	test.CheckNoErr(t, err, "NewResponder")
+
+	msg1, err := ini.Next(nil)
+	test.CheckNoErr(t, err, "Initiator.Next")
+	msg2, err := res.Next(msg1)
+	test.CheckNoErr(t, err, "Responder.Next")
+	msg3, err := ini.Next(msg2)
+	if err != nil {
+		return ini, res, err
+	}
+	out, err := res.Next(msg3)
+	test.CheckOk(out == nil, "unexpected message", t)
+	return ini, res, err
+}
+
+func checkSessionKeys(t *testing.T, ini *ake.Initiator, res *ake.Responder) {
+	t.Helper()
+	test.CheckOk(ini.Done() && res.Done(), "key exchange not done", t)
+	ki, err := ini.SessionKey()
+	test.CheckNoErr(t, err, "Initiator.SessionKey")
+	kr, err := res.SessionKey()
+	test.CheckNoErr(t, err, "Responder.SessionKey")
+	test.CheckOk(bytes.Equal(ki, kr), "session keys differ", t)
+}
+
+func TestAKE(t *testing.T) {
+	for _, c := range []ake.Config{
+		{Scheme: schemes.ByName("ML-KEM-768")},
+		{Scheme: schemes.ByName("X25519MLKEM768"), Hash: crypto.SHA384},
+		{Scheme: schemes.ByName("X-Wing"), Context: []byte("app")},
+		{
+			Scheme:    schemes.ByName("ML-KEM-1024"),
+			Ephemeral: schemes.ByName("ML-KEM-768"),
+			Hash:      crypto.SHA512,
+		},
+		{Scheme: schemes.ByName("HPKE_KEM_X25519_HKDF_SHA256")},
+	} {
+		name := c.Scheme.Name()
+		if c.Ephemeral != nil {
+			name += "/" + c.Ephemeral.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			i, r := newKeyPair(t, c.Scheme), newKeyPair(t, c.Scheme)
+			ini, res, err := handshake(t, &c, &c, i, r, r.pk, i.pk)
+			test.CheckNoErr(t, err, "handshake")
+			checkSessionKeys(t, ini, res)
+
+			// A fresh handshake gives a fresh session key.
+			ini2, _, err := handshake(t, &c, &c, i, r, r.pk, i.pk)
+			test.CheckNoErr(t, err, "handshake")
+			k1, _ := ini.SessionKey()
+			k2, _ := ini2.SessionKey()
+			test.CheckOk(!bytes.Equal(k1, k2), "session key reused", t)
+		})
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	c := &ake.Config{Scheme: schemes.ByName("ML-KEM-512")}
+	i, r, m := newKeyPair(t, c.Scheme), newKeyPair(t, c.Scheme), newKeyPair(t, c.Scheme)
+
+	for _, tc := range []struct {
+		name         string
+		ci, cr       *ake.Config
+		i, r         keyPair
+		peerI, peerR kem.PublicKey
+	}{
+		// The initiator expects another responder: the impostor can't
+		// decapsulate and its confirmation MAC is wrong.
+		{"impostor responder", c, c, i, m, r.pk, i.pk},
+		// The responder expects another initiator: the impostor can't
+		// decapsulate, which the responder notices on the last message.
+		{"impostor initiator", c, c, m, r, r.pk, i.pk},
+		// Both parties use the right keys, but disagree on the peer.
+		{"unexpected initiator", c, c, i, r, r.pk, m.pk},
+		{
+			"context mismatch",
+			c, &ake.Config{Scheme: c.Scheme, Context: []byte("other")},
+			i, r, r.pk, i.pk,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ini, res, err := handshake(t, tc.ci, tc.cr, tc.i, tc.r, tc.peerI, tc.peerR)
+			test.CheckOk(err == ake.ErrAuthentication,
+				fmt.Sprintf("expected ErrAuthentication, got %v", err), t)
+			test.CheckOk(!ini.Done() && !res.Done(), "key exchange done", t)
+			_, err = ini.SessionKey()
+			test.CheckOk(err == ake.ErrState, "session key available", t)
+			_, err = res.SessionKey()
+			test.CheckOk(err == ake.ErrState, "session key available", t)
+		})
+	}
+}
+
+func TestTampering(t *testing.T) {
+	c := &ake.Config{Scheme: schemes.ByName("ML-KEM-512")}
+	i, r := newKeyPair(t, c.Scheme), newKeyPair(t, c.Scheme)
+
+	for msg := 0; msg < 3; msg++ {
+		t.Run(fmt.Sprintf("message %v", msg+1), func(t *testing.T) {
+			ini, err := ake.NewInitiator(c, i.sk, r.pk)
+			test.CheckNoErr(t, err, "NewInitiator")
+			res, err := ake.NewResponder(c, r.sk, i.pk)
+			test.CheckNoErr(t, err, "NewResponder")
+
+			var in []byte
+			for step := 0; step <= msg; step++ {
+				if step%2 == 0 {
+					in, err = ini.Next(in)
+				} else {
+					in, err = res.Next(in)
+				}
+				test.CheckNoErr(t, err, "Next")
+			}
+
+			in[len(in)-1] ^= 1
+			var out []byte
+			if msg%2 == 0 {
+				out, err = res.Next(in)
+			} else {
+				out, err = ini.Next(in)
+			}
+			// A modified first message is only detected by the initiator,
+			// when checking the confirmation of the responder.
+			if msg == 0 && err == nil {
+				_, err = ini.Next(out)
+			}
+			test.CheckOk(err == ake.ErrAuthentication,
+				fmt.Sprintf("expected ErrAuthentication, got %v", err), t)
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	c := &ake.Config{Scheme: schemes.ByName("ML-KEM-512")}
+	i, r := newKeyPair(t, c.Scheme), newKeyPair(t, c.Scheme)
+
+	_, err := ake.NewInitiator(c, i.sk, nil)
+	test.CheckOk(err == ake.ErrConfig, "missing peer accepted", t)
+	_, err = ake.NewResponder(&ake.Config{}, r.sk, i.pk)
+	test.CheckOk(err == ake.ErrConfig, "missing scheme accepted", t)
+	x := newKeyPair(t, schemes.ByName("HPKE_KEM_X25519_HKDF_SHA256"))
+	_, err = ake.NewInitiator(c, i.sk, x.pk)
+	test.CheckOk(err == ake.ErrConfig, "key of another scheme accepted", t)
+
+	ini, _ := ake.NewInitiator(c, i.sk, r.pk)
+	_, err = ini.Next([]byte{1})
+	test.CheckOk(err == ake.ErrState, "initiator started with a message", t)
+	_, err = ini.Next(nil)
+	test.CheckOk(err == ake.ErrState, "failed initiator reused", t)
+
+	ini, _ = ake.NewInitiator(c, i.sk, r.pk)
+	res, _ := ake.NewResponder(c, r.sk, i.pk)
+	msg1, _ := ini.Next(nil)
+	_, err = res.Next(msg1[1:])
+	test.CheckOk(err == ake.ErrMessage, "truncated message accepted", t)
+	_, err = res.Next(msg1)
+	test.CheckOk(err == ake.ErrState, "failed responder reused", t)
+
+	ini, res, err = handshake(t, c, c, i, r, r.pk, i.pk)
+	test.CheckNoErr(t, err, "handshake")
+	_, err = ini.Next(nil)
+	test.CheckOk(err == ake.ErrState, "finished initiator reused", t)
+	_, err = res.Next(nil)
+	test.CheckOk(err == ake.ErrState, "finished responder reused", t)
+}
+
+func TestDeterministic(t *testing.T) {
+	// Both parties draw their randomness from a shared stream, so that the
+	// whole exchange is reproducible.
+	c := &ake.Config{Scheme: schemes.ByName("ML-KEM-768")}
+	seed := make([]byte, c.Scheme.SeedSize())
+	seed[0] = 1
+	pkI, skI := c.Scheme.DeriveKeyPair(seed)
+	seed[0] = 2
+	pkR, skR := c.Scheme.DeriveKeyPair(seed)
+
+	run := func() []byte {
+		rnd := sha3.NewShake128()
+		cfg := *c
+		cfg.Rand = &rnd
+		ini, res, err := handshake(t, &cfg, &cfg,
+			keyPair{pkI, skI}, keyPair{pkR, skR}, pkR, pkI)
+		test.CheckNoErr(t, err, "handshake")
+		checkSessionKeys(t, ini, res)
+		key, _ := ini.SessionKey()
+		return key
+	}
+	test.CheckOk(bytes.Equal(run(), run()), "session keys differ", t)
+}
+
+func BenchmarkHandshake(b *testing.B) {
+	c := &ake.Config{Scheme: schemes.ByName("ML-KEM-768")}
+	i, r := newKeyPair(b, c.Scheme), newKeyPair(b, c.Scheme)
+	for n := 0; n < b.N; n++ {
+		_, _, err := handshake(b, c, c, i, r, r.pk, i.pk)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
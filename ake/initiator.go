@@ -0,0 +1,145 @@
+package ake
+
+import (
+	"crypto/hmac"
+	"io"
+
+	"github.com/cloudflare/circl/kem"
+)
+
+// Initiator is the party that starts the key exchange.
+type Initiator struct {
+	c     Config
+	state state
+	sk    kem.PrivateKey // static private key of the initiator
+	peer  kem.PublicKey  // static public key of the responder
+	t     *transcript
+	skE   kem.PrivateKey // ephemeral private key
+	ssR   []byte         // shared secret encapsulated to the responder
+	key   []byte         // session key
+}
+
+// NewInitiator returns an initiator holding the static private key sk,
+// which will only complete the key exchange with the holder of the
+// private key of peer.
+func NewInitiator(c *Config, sk kem.PrivateKey, peer kem.PublicKey) (*Initiator, error) {
+	if err := c.check(sk, peer); err != nil {
+		return nil, err
+	}
+	t, err := newTranscript(c, sk.Public(), peer)
+	if err != nil {
+		return nil, err
+	}
+	return &Initiator{c: *c, sk: sk, peer: peer, t: t}, nil
+}
+
+// Next advances the key exchange. The first call takes a nil in and
+// returns the first message for the responder. The second call takes the
+// reply of the responder and returns the last message for it, after which
+// the key exchange is complete on the initiator side.
+//
+// Once Next returns an error, the initiator can't be used anymore.
+func (i *Initiator) Next(in []byte) (out []byte, err error) {
+	var next state
+	switch i.state {
+	case stateStart:
+		if in != nil {
+			err = ErrState
+			break
+		}
+		out, err = i.messageOne()
+		next = stateWaitMessageTwo
+	case stateWaitMessageTwo:
+		out, err = i.messageThree(in)
+		next = stateDone
+	default:
+		return nil, ErrState
+	}
+
+	if err != nil {
+		i.fail()
+		return nil, err
+	}
+	i.state = next
+	return out, nil
+}
+
+// messageOne generates the ephemeral key pair and encapsulates to the
+// static key of the responder.
+func (i *Initiator) messageOne() ([]byte, error) {
+	e := i.c.ephemeral()
+	seed := make([]byte, e.SeedSize())
+	if _, err := io.ReadFull(i.c.rand(), seed); err != nil {
+		return nil, err
+	}
+	pkE, skE := e.DeriveKeyPair(seed)
+	ppkE, err := pkE.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	ctR, ssR, err := encapsulate(&i.c, i.c.Scheme, i.peer)
+	if err != nil {
+		return nil, err
+	}
+
+	i.skE, i.ssR = skE, ssR
+	out := append(ppkE, ctR...)
+	i.t.write(out)
+	return out, nil
+}
+
+// messageThree decapsulates the ciphertexts of the responder, checks its
+// key confirmation and returns the one of the initiator.
+func (i *Initiator) messageThree(in []byte) ([]byte, error) {
+	if len(in) != i.c.messageTwoSize() {
+		return nil, ErrMessage
+	}
+	th1 := i.t.sum()
+	ctI := in[:i.c.Scheme.CiphertextSize()]
+	ctE := in[len(ctI) : len(ctI)+i.c.ephemeral().CiphertextSize()]
+	finR := in[len(ctI)+len(ctE):]
+
+	ssI, err := i.c.Scheme.Decapsulate(i.sk, ctI)
+	if err != nil {
+		return nil, ErrMessage
+	}
+	ssE, err := i.c.ephemeral().Decapsulate(i.skE, ctE)
+	if err != nil {
+		return nil, ErrMessage
+	}
+
+	ks := newKeySchedule(&i.c, th1, i.ssR, ssI, ssE)
+	i.t.write(in[:len(ctI)+len(ctE)])
+	if !hmac.Equal(finR, ks.mac(responderLabel, i.t.sum())) {
+		return nil, ErrAuthentication
+	}
+	i.t.write(finR)
+
+	finI := ks.mac(initiatorLabel, i.t.sum())
+	i.t.write(finI)
+	i.key = ks.expand(sessionKeyLabel, i.t.sum())
+	i.skE, i.ssR = nil, nil
+	return finI, nil
+}
+
+func (i *Initiator) fail() {
+	i.state = stateFailed
+	i.skE, i.ssR, i.key = nil, nil, nil
+}
+
+// Done reports whether the key exchange completed successfully.
+func (i *Initiator) Done() bool { return i.state == stateDone }
+
+// SessionKey returns the shared session key, of the size of the
+// configured hash. Returns ErrState if the key exchange is not complete.
+//
+// The initiator has authenticated the responder once the key exchange is
+// complete, but the responder authenticates the initiator only when it
+// receives the last message.
+func (i *Initiator) SessionKey() ([]byte, error) {
+	if i.state != stateDone {
+		return nil, ErrState
+	}
+	return append([]byte{}, i.key...), nil
+}
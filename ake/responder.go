@@ -0,0 +1,126 @@
+package ake
+
+import (
+	"crypto/hmac"
+
+	"github.com/cloudflare/circl/kem"
+)
+
+// Responder is the party that answers the key exchange.
+type Responder struct {
+	c     Config
+	state state
+	sk    kem.PrivateKey // static private key of the responder
+	peer  kem.PublicKey  // static public key of the initiator
+	t     *transcript
+	ks    *keySchedule
+	key   []byte // session key
+}
+
+// NewResponder returns a responder holding the static private key sk,
+// which will only complete the key exchange with the holder of the
+// private key of peer.
+func NewResponder(c *Config, sk kem.PrivateKey, peer kem.PublicKey) (*Responder, error) {
+	if err := c.check(sk, peer); err != nil {
+		return nil, err
+	}
+	t, err := newTranscript(c, peer, sk.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &Responder{c: *c, sk: sk, peer: peer, t: t}, nil
+}
+
+// Next advances the key exchange. The first call takes the first message
+// of the initiator and returns the reply. The second call takes the last
+// message of the initiator and returns nil, after which the key exchange
+// is complete on the responder side.
+//
+// Once Next returns an error, the responder can't be used anymore.
+func (r *Responder) Next(in []byte) (out []byte, err error) {
+	var next state
+	switch r.state {
+	case stateStart:
+		out, err = r.messageTwo(in)
+		next = stateWaitMessageThree
+	case stateWaitMessageThree:
+		err = r.finish(in)
+		next = stateDone
+	default:
+		return nil, ErrState
+	}
+
+	if err != nil {
+		r.fail()
+		return nil, err
+	}
+	r.state = next
+	return out, nil
+}
+
+// messageTwo decapsulates the ciphertext of the initiator, encapsulates
+// to its static and ephemeral keys, and returns the ciphertexts along with
+// the key confirmation of the responder.
+func (r *Responder) messageTwo(in []byte) ([]byte, error) {
+	if len(in) != r.c.messageOneSize() {
+		return nil, ErrMessage
+	}
+	e := r.c.ephemeral()
+	pkE, err := e.UnmarshalBinaryPublicKey(in[:e.PublicKeySize()])
+	if err != nil {
+		return nil, ErrMessage
+	}
+	ssR, err := r.c.Scheme.Decapsulate(r.sk, in[e.PublicKeySize():])
+	if err != nil {
+		return nil, ErrMessage
+	}
+	r.t.write(in)
+	th1 := r.t.sum()
+
+	ctI, ssI, err := encapsulate(&r.c, r.c.Scheme, r.peer)
+	if err != nil {
+		return nil, err
+	}
+	ctE, ssE, err := encapsulate(&r.c, e, pkE)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ks = newKeySchedule(&r.c, th1, ssR, ssI, ssE)
+	out := append(ctI, ctE...)
+	r.t.write(out)
+	finR := r.ks.mac(responderLabel, r.t.sum())
+	r.t.write(finR)
+	return append(out, finR...), nil
+}
+
+// finish checks the key confirmation of the initiator.
+func (r *Responder) finish(in []byte) error {
+	if len(in) != r.c.messageThreeSize() {
+		return ErrMessage
+	}
+	if !hmac.Equal(in, r.ks.mac(initiatorLabel, r.t.sum())) {
+		return ErrAuthentication
+	}
+	r.t.write(in)
+	r.key = r.ks.expand(sessionKeyLabel, r.t.sum())
+	r.ks = nil
+	return nil
+}
+
+func (r *Responder) fail() {
+	r.state = stateFailed
+	r.ks, r.key = nil, nil
+}
+
+// Done reports whether the key exchange completed successfully.
+func (r *Responder) Done() bool { return r.state == stateDone }
+
+// SessionKey returns the shared session key, of the size of the
+// configured hash. Returns ErrState if the key exchange is not complete.
+func (r *Responder) SessionKey() ([]byte, error) {
+	if r.state != stateDone {
+		return nil, ErrState
+	}
+	return append([]byte{}, r.key...), nil
+}
@@ -82,3 +82,22 @@ func (e Curve) CombinedMult(m, n *Scalar, P *Point) *Point {
 	n4.divBy4(n)
 	return e.pull(twistCurve{}.CombinedMult(m4, n4, twistCurve{}.pull(P)))
 }
+
+// MultiScalarMult returns mG+n[0]P[0]+...+n[k-1]P[k-1], where G is the
+// generator point. This function is non-constant time.
+// Panics if n and P have different lengths.
+func (e Curve) MultiScalarMult(m *Scalar, n []Scalar, P []Point) *Point {
+	if len(n) != len(P) {
+		panic("goldilocks: mismatched lengths")
+	}
+
+	m4 := &Scalar{}
+	m4.divBy4(m)
+	n4 := make([]Scalar, len(n))
+	Q := make([]twistPoint, len(P))
+	for i := range n {
+		n4[i].divBy4(&n[i])
+		Q[i] = *twistCurve{}.pull(&P[i])
+	}
+	return e.pull(twistCurve{}.multiScalarMult(m4, n4, Q))
+}
@@ -86,6 +86,25 @@ func TestScalarMult(t *testing.T) {
 			}
 		}
 	})
+	t.Run("kG+sum(lP)", func(t *testing.T) {
+		const n = 5
+		l := make([]goldilocks.Scalar, n)
+		P := make([]goldilocks.Point, n)
+		for i := 0; i < testTimes/8; i++ {
+			_, _ = rand.Read(k[:])
+			want := e.ScalarBaseMult(k)
+			for j := range n {
+				_, _ = rand.Read(l[j][:])
+				P[j] = *randomPoint()
+				want = e.Add(want, e.ScalarMult(&l[j], &P[j]))
+			}
+
+			got := e.MultiScalarMult(k, l, P)
+			if !e.IsOnCurve(got) || !got.IsEqual(want) {
+				test.ReportError(t, got, want, P, k, l)
+			}
+		}
+	})
 }
 
 func BenchmarkCurve(b *testing.B) {
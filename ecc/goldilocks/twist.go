@@ -131,6 +131,49 @@ func (e twistCurve) CombinedMult(m, n *Scalar, P *twistPoint) *twistPoint {
 	return Q
 }
 
+// multiScalarMult returns mG+n[0]P[0]+...+n[k-1]P[k-1] using Straus'
+// method. It overwrites the points P[i].
+func (e twistCurve) multiScalarMult(m *Scalar, n []Scalar, P []twistPoint) *twistPoint {
+	nafFix := math.OmegaNAF(conv.BytesLe2BigInt(m[:]), omegaFix)
+	nafVar := make([][]int32, len(n))
+	l := len(nafFix)
+	for i := range n {
+		nafVar[i] = math.OmegaNAF(conv.BytesLe2BigInt(n[i][:]), omegaVar)
+		l = max(l, len(nafVar[i]))
+	}
+
+	TabQ := make([][1 << (omegaVar - 2)]preTwistPointProy, len(P))
+	for i := range P {
+		P[i].oddMultiples(TabQ[i][:])
+	}
+
+	Q := e.Identity()
+	for i := l - 1; i >= 0; i-- {
+		Q.Double()
+		// Generator point
+		if i < len(nafFix) && nafFix[i] != 0 {
+			idxM := absolute(nafFix[i]) >> 1
+			R := tabVerif[idxM]
+			if nafFix[i] < 0 {
+				R.neg()
+			}
+			Q.mixAddZ1(&R)
+		}
+		// Variable input points
+		for j, naf := range nafVar {
+			if i < len(naf) && naf[i] != 0 {
+				idxN := absolute(naf[i]) >> 1
+				S := TabQ[j][idxN]
+				if naf[i] < 0 {
+					S.neg()
+				}
+				Q.mixAdd(&S)
+			}
+		}
+	}
+	return Q
+}
+
 // absolute returns always a positive value.
 func absolute(x int32) int32 {
 	mask := x >> 31
@@ -3,6 +3,7 @@ package hpke
 import (
 	"crypto/cipher"
 	"fmt"
+	"math"
 )
 
 type encdecContext struct {
@@ -28,14 +29,22 @@ type (
 
 // Export takes a context string exporterContext and a desired length (in
 // bytes), and produces a secret derived from the internal exporter secret
-// using the corresponding KDF Expand function. It panics if length is
-// greater than 255*N bytes, where N is the size (in bytes) of the KDF's
-// output.
+// using the corresponding KDF Expand function, or Derive function for
+// one-stage KDFs. It panics if length is greater than 255*N bytes, where N
+// is the size (in bytes) of the KDF's output, or greater than 65535 bytes
+// for one-stage KDFs.
 func (c *encdecContext) Export(exporterContext []byte, length uint) []byte {
 	maxLength := uint(255 * c.suite.kdfID.ExtractSize())
+	if c.suite.kdfID.IsOneStage() {
+		maxLength = math.MaxUint16
+	}
 	if length > maxLength {
 		panic(fmt.Errorf("output length must be lesser than %v bytes", maxLength))
 	}
+	if c.suite.kdfID.IsOneStage() {
+		return c.suite.labeledDerive(c.exporterSecret, []byte("sec"),
+			exporterContext, uint16(length))
+	}
 	return c.suite.labeledExpand(c.exporterSecret, []byte("sec"),
 		exporterContext, uint16(length))
 }
@@ -76,6 +85,9 @@ func (c *encdecContext) increment() error {
 }
 
 func (c *sealContext) Seal(pt, aad []byte) ([]byte, error) {
+	if c.AEAD == nil {
+		return nil, ErrAEADExportOnly
+	}
 	ct := c.AEAD.Seal(nil, c.calcNonce(), pt, aad)
 	err := c.increment()
 	if err != nil {
@@ -88,6 +100,9 @@ func (c *sealContext) Seal(pt, aad []byte) ([]byte, error) {
 }
 
 func (c *openContext) Open(ct, aad []byte) ([]byte, error) {
+	if c.AEAD == nil {
+		return nil, ErrAEADExportOnly
+	}
 	pt, err := c.AEAD.Open(nil, c.calcNonce(), ct, aad)
 	if err != nil {
 		return nil, err
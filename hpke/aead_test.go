@@ -18,6 +18,16 @@ func TestAeadExporter(t *testing.T) {
 		exporter.Export([]byte("exporter"), maxLength+1)
 	})
 	test.CheckNoErr(t, err, "exporter max size")
+
+	suite.kdfID = KDF_SHAKE128
+	exporter = &encdecContext{suite: suite, exporterSecret: make([]byte, 32)}
+	maxLength = 1<<16 - 1
+	out := exporter.Export([]byte("exporter"), maxLength)
+	test.CheckOk(len(out) == int(maxLength), "wrong export size", t)
+	err = test.CheckPanic(func() {
+		exporter.Export([]byte("exporter"), maxLength+1)
+	})
+	test.CheckNoErr(t, err, "one-stage exporter max size")
 }
 
 func setupAeadTest() (*sealContext, *openContext, error) {
@@ -184,3 +194,43 @@ func TestAeadSeqOverflow(t *testing.T) {
 		test.ReportError(t, gotIncorrect, wantIncorrect)
 	}
 }
+
+func TestExportOnly(t *testing.T) {
+	s := NewSuite(KEM_X25519_HKDF_SHA256, KDF_HKDF_SHA256, AEAD_EXPORT_ONLY)
+	pk, sk, err := s.kemID.Scheme().GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair")
+	sender, err := s.NewSender(pk, nil)
+	test.CheckNoErr(t, err, "NewSender")
+	receiver, err := s.NewReceiver(sk, nil)
+	test.CheckNoErr(t, err, "NewReceiver")
+	enc, sealer, err := sender.Setup(rand.Reader)
+	test.CheckNoErr(t, err, "Setup")
+	opener, err := receiver.Setup(enc)
+	test.CheckNoErr(t, err, "Setup")
+
+	rawSealer, err := sealer.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary")
+	parsedSealer, err := UnmarshalSealer(rawSealer)
+	test.CheckNoErr(t, err, "UnmarshalSealer")
+	rawOpener, err := opener.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary")
+	parsedOpener, err := UnmarshalOpener(rawOpener)
+	test.CheckNoErr(t, err, "UnmarshalOpener")
+
+	want := sealer.Export([]byte("exporter"), 32)
+	for _, ctx := range []Context{opener, parsedSealer, parsedOpener} {
+		got := ctx.Export([]byte("exporter"), 32)
+		if !bytes.Equal(got, want) {
+			test.ReportError(t, got, want)
+		}
+	}
+
+	for _, se := range []Sealer{sealer, parsedSealer} {
+		_, err = se.Seal([]byte("message"), nil)
+		test.CheckOk(err == ErrAEADExportOnly, "Seal must fail", t)
+	}
+	for _, op := range []Opener{opener, parsedOpener} {
+		_, err = op.Open([]byte("ciphertext"), nil)
+		test.CheckOk(err == ErrAEADExportOnly, "Open must fail", t)
+	}
+}
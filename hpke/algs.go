@@ -10,12 +10,17 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"math"
 
 	"github.com/cloudflare/circl/dh/x25519"
 	"github.com/cloudflare/circl/dh/x448"
 	"github.com/cloudflare/circl/kem"
 	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem1024"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem512"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
 	"github.com/cloudflare/circl/kem/xwing"
+	"github.com/cloudflare/circl/xof"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
 )
@@ -39,7 +44,20 @@ const (
 	// KEM_X25519_KYBER768_DRAFT00 is a hybrid KEM built on DHKEM(X25519, HKDF-SHA256)
 	// and Kyber768Draft00
 	KEM_X25519_KYBER768_DRAFT00 KEM = 0x30
-	// KEM_XWING is a hybrid KEM using X25519 and ML-KEM-768.
+	// KEM_ML_KEM_512 is ML-KEM-512 as specified in draft-ietf-hpke-pq.
+	KEM_ML_KEM_512 KEM = 0x40
+	// KEM_ML_KEM_768 is ML-KEM-768 as specified in draft-ietf-hpke-pq.
+	KEM_ML_KEM_768 KEM = 0x41
+	// KEM_ML_KEM_1024 is ML-KEM-1024 as specified in draft-ietf-hpke-pq.
+	KEM_ML_KEM_1024 KEM = 0x42
+	// KEM_MLKEM768_P256 is a hybrid KEM using P-256 and ML-KEM-768, as
+	// specified in draft-ietf-hpke-pq.
+	KEM_MLKEM768_P256 KEM = 0x50
+	// KEM_MLKEM1024_P384 is a hybrid KEM using P-384 and ML-KEM-1024, as
+	// specified in draft-ietf-hpke-pq.
+	KEM_MLKEM1024_P384 KEM = 0x51
+	// KEM_XWING is a hybrid KEM using X25519 and ML-KEM-768, named
+	// MLKEM768-X25519 in draft-ietf-hpke-pq.
 	KEM_XWING KEM = 0x647a
 )
 
@@ -52,6 +70,11 @@ func (k KEM) IsValid() bool {
 		KEM_X25519_HKDF_SHA256,
 		KEM_X448_HKDF_SHA512,
 		KEM_X25519_KYBER768_DRAFT00,
+		KEM_ML_KEM_512,
+		KEM_ML_KEM_768,
+		KEM_ML_KEM_1024,
+		KEM_MLKEM768_P256,
+		KEM_MLKEM1024_P384,
 		KEM_XWING:
 		return true
 	default:
@@ -59,8 +82,8 @@ func (k KEM) IsValid() bool {
 	}
 }
 
-// Scheme returns an instance of a KEM that supports authentication. Panics if
-// the KEM identifier is invalid.
+// Scheme returns an instance of a KEM. Panics if the KEM identifier is
+// invalid. Only the DHKEMs support authentication.
 func (k KEM) Scheme() kem.Scheme {
 	switch k {
 	case KEM_P256_HKDF_SHA256:
@@ -75,6 +98,16 @@ func (k KEM) Scheme() kem.Scheme {
 		return dhkemx448hkdfsha512
 	case KEM_X25519_KYBER768_DRAFT00:
 		return hybridkemX25519Kyber768
+	case KEM_ML_KEM_512:
+		return kemMLKEM512
+	case KEM_ML_KEM_768:
+		return kemMLKEM768
+	case KEM_ML_KEM_1024:
+		return kemMLKEM1024
+	case KEM_MLKEM768_P256:
+		return kemMLKEM768P256
+	case KEM_MLKEM1024_P384:
+		return kemMLKEM1024P384
 	case KEM_XWING:
 		return kemXwing
 	default:
@@ -92,13 +125,40 @@ const (
 	KDF_HKDF_SHA384 KDF = 0x02
 	// KDF_HKDF_SHA512 is a KDF using HKDF with SHA-512.
 	KDF_HKDF_SHA512 KDF = 0x03
+	// KDF_SHAKE128 is a one-stage KDF using SHAKE128.
+	KDF_SHAKE128 KDF = 0x10
+	// KDF_SHAKE256 is a one-stage KDF using SHAKE256.
+	KDF_SHAKE256 KDF = 0x11
+	// KDF_TurboSHAKE128 is a one-stage KDF using TurboSHAKE128.
+	KDF_TurboSHAKE128 KDF = 0x12
+	// KDF_TurboSHAKE256 is a one-stage KDF using TurboSHAKE256.
+	KDF_TurboSHAKE256 KDF = 0x13
 )
 
 func (k KDF) IsValid() bool {
 	switch k {
 	case KDF_HKDF_SHA256,
 		KDF_HKDF_SHA384,
-		KDF_HKDF_SHA512:
+		KDF_HKDF_SHA512,
+		KDF_SHAKE128,
+		KDF_SHAKE256,
+		KDF_TurboSHAKE128,
+		KDF_TurboSHAKE256:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsOneStage returns true if the KDF derives keys in a single call to
+// KDF.Derive, as specified in draft-ietf-hpke-pq, instead of using the
+// two-stage KDF.Extract and KDF.Expand functions.
+func (k KDF) IsOneStage() bool {
+	switch k {
+	case KDF_SHAKE128,
+		KDF_SHAKE256,
+		KDF_TurboSHAKE128,
+		KDF_TurboSHAKE256:
 		return true
 	default:
 		return false
@@ -106,7 +166,8 @@ func (k KDF) IsValid() bool {
 }
 
 // ExtractSize returns the size (in bytes) of the pseudorandom key produced
-// by KDF.Extract.
+// by KDF.Extract. For one-stage KDFs, it returns the size of the exporter
+// secret, which is their security level in bytes.
 func (k KDF) ExtractSize() int {
 	switch k {
 	case KDF_HKDF_SHA256:
@@ -115,13 +176,18 @@ func (k KDF) ExtractSize() int {
 		return crypto.SHA384.Size()
 	case KDF_HKDF_SHA512:
 		return crypto.SHA512.Size()
+	case KDF_SHAKE128, KDF_TurboSHAKE128:
+		return 32
+	case KDF_SHAKE256, KDF_TurboSHAKE256:
+		return 64
 	default:
 		panic(ErrInvalidKDF)
 	}
 }
 
 // Extract derives a pseudorandom key from a high-entropy, secret input and a
-// salt. The size of the output is determined by KDF.ExtractSize.
+// salt. The size of the output is determined by KDF.ExtractSize. Panics if
+// the KDF is one-stage.
 func (k KDF) Extract(secret, salt []byte) (pseudorandomKey []byte) {
 	return hkdf.Extract(k.hash(), secret, salt)
 }
@@ -129,7 +195,8 @@ func (k KDF) Extract(secret, salt []byte) (pseudorandomKey []byte) {
 // Expand derives a variable length pseudorandom string from a pseudorandom key
 // and an information string. Panics if the pseudorandom key is less
 // than N bytes, or if the output length is greater than 255*N bytes,
-// where N is the size returned by KDF.Extract function.
+// where N is the size returned by KDF.Extract function. Panics if the KDF is
+// one-stage.
 func (k KDF) Expand(pseudorandomKey, info []byte, outputLen uint) []byte {
 	extractSize := k.ExtractSize()
 	if len(pseudorandomKey) < extractSize {
@@ -148,6 +215,38 @@ func (k KDF) Expand(pseudorandomKey, info []byte, outputLen uint) []byte {
 	return output
 }
 
+// Derive derives a variable length pseudorandom string from a high-entropy,
+// secret input. Panics if the KDF is not one-stage, or if the output length
+// is greater than 65535 bytes.
+func (k KDF) Derive(secret []byte, outputLen uint) []byte {
+	if outputLen > math.MaxUint16 {
+		panic(fmt.Errorf("output length must be less than %v bytes", math.MaxUint16+1))
+	}
+	x := k.xof().New()
+	_, _ = x.Write(secret)
+	output := make([]byte, outputLen)
+	_, err := io.ReadFull(x, output)
+	if err != nil {
+		panic(err)
+	}
+	return output
+}
+
+func (k KDF) xof() xof.ID {
+	switch k {
+	case KDF_SHAKE128:
+		return xof.SHAKE128
+	case KDF_SHAKE256:
+		return xof.SHAKE256
+	case KDF_TurboSHAKE128:
+		return xof.TURBOSHAKE128
+	case KDF_TurboSHAKE256:
+		return xof.TURBOSHAKE256
+	default:
+		panic(ErrInvalidKDF)
+	}
+}
+
 func (k KDF) hash() func() hash.Hash {
 	switch k {
 	case KDF_HKDF_SHA256:
@@ -171,10 +270,13 @@ const (
 	AEAD_AES256GCM AEAD = 0x02
 	// AEAD_ChaCha20Poly1305 is ChaCha20 stream cipher and Poly1305 MAC.
 	AEAD_ChaCha20Poly1305 AEAD = 0x03
+	// AEAD_EXPORT_ONLY denotes that no AEAD cipher is used, so that HPKE
+	// contexts can only export secrets.
+	AEAD_EXPORT_ONLY AEAD = 0xFFFF
 )
 
 // New instantiates an AEAD cipher from the identifier, returns an error if the
-// identifier is not known.
+// identifier is not known, or ErrAEADExportOnly for AEAD_EXPORT_ONLY.
 func (a AEAD) New(key []byte) (cipher.AEAD, error) {
 	switch a {
 	case AEAD_AES128GCM, AEAD_AES256GCM:
@@ -185,6 +287,8 @@ func (a AEAD) New(key []byte) (cipher.AEAD, error) {
 		return cipher.NewGCM(block)
 	case AEAD_ChaCha20Poly1305:
 		return chacha20poly1305.New(key)
+	case AEAD_EXPORT_ONLY:
+		return nil, ErrAEADExportOnly
 	default:
 		panic(ErrInvalidAEAD)
 	}
@@ -194,7 +298,8 @@ func (a AEAD) IsValid() bool {
 	switch a {
 	case AEAD_AES128GCM,
 		AEAD_AES256GCM,
-		AEAD_ChaCha20Poly1305:
+		AEAD_ChaCha20Poly1305,
+		AEAD_EXPORT_ONLY:
 		return true
 	default:
 		return false
@@ -210,6 +315,8 @@ func (a AEAD) KeySize() uint {
 		return 32
 	case AEAD_ChaCha20Poly1305:
 		return chacha20poly1305.KeySize
+	case AEAD_EXPORT_ONLY:
+		return 0
 	default:
 		panic(ErrInvalidAEAD)
 	}
@@ -222,13 +329,15 @@ func (a AEAD) NonceSize() uint {
 		AEAD_AES256GCM,
 		AEAD_ChaCha20Poly1305:
 		return 12
+	case AEAD_EXPORT_ONLY:
+		return 0
 	default:
 		panic(ErrInvalidAEAD)
 	}
 }
 
 // CipherLen returns the length of a ciphertext corresponding to a message of
-// length mLen.
+// length mLen. Panics for AEAD_EXPORT_ONLY, which doesn't encrypt.
 func (a AEAD) CipherLen(mLen uint) uint {
 	switch a {
 	case AEAD_AES128GCM, AEAD_AES256GCM, AEAD_ChaCha20Poly1305:
@@ -242,7 +351,8 @@ var (
 	dhkemp256hkdfsha256, dhkemp384hkdfsha384, dhkemp521hkdfsha512 shortKEM
 	dhkemx25519hkdfsha256, dhkemx448hkdfsha512                    xKEM
 	hybridkemX25519Kyber768                                       hybridKEM
-	kemXwing                                                      genericNoAuthKEM
+	kemMLKEM512, kemMLKEM768, kemMLKEM1024                        pqKEM
+	kemMLKEM768P256, kemMLKEM1024P384, kemXwing                   pqKEM
 )
 
 func init() {
@@ -282,6 +392,28 @@ func init() {
 	hybridkemX25519Kyber768.kemA = dhkemx25519hkdfsha256
 	hybridkemX25519Kyber768.kemB = kyber768.Scheme()
 
-	kemXwing.Scheme = xwing.Scheme()
-	kemXwing.name = "HPKE_KEM_XWING"
+	kemMLKEM512 = pqKEM{mlkem512.Scheme(), KEM_ML_KEM_512, "HPKE_KEM_ML_KEM_512"}
+	kemMLKEM768 = pqKEM{mlkem768.Scheme(), KEM_ML_KEM_768, "HPKE_KEM_ML_KEM_768"}
+	kemMLKEM1024 = pqKEM{mlkem1024.Scheme(), KEM_ML_KEM_1024, "HPKE_KEM_ML_KEM_1024"}
+
+	kemMLKEM768P256 = pqKEM{&qsfKEM{
+		name:       "MLKEM768-P256",
+		label:      "MLKEM768-P256",
+		pq:         mlkem768.Scheme(),
+		curve:      ecdh.P256(),
+		scalarSize: 32,
+		pointSize:  65,
+		candidates: 3,
+	}, KEM_MLKEM768_P256, "HPKE_KEM_MLKEM768_P256"}
+	kemMLKEM1024P384 = pqKEM{&qsfKEM{
+		name:       "MLKEM1024-P384",
+		label:      "MLKEM1024-P384",
+		pq:         mlkem1024.Scheme(),
+		curve:      ecdh.P384(),
+		scalarSize: 48,
+		pointSize:  97,
+		candidates: 1,
+	}, KEM_MLKEM1024_P384, "HPKE_KEM_MLKEM1024_P384"}
+
+	kemXwing = pqKEM{xwing.Scheme(), KEM_XWING, "HPKE_KEM_XWING"}
 }
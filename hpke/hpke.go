@@ -8,9 +8,13 @@
 // Specification in
 // https://datatracker.ietf.org/doc/draft-irtf-cfrg-hpke
 //
-// BUG(cjpatton): This package does not implement the "Export-Only" mode of the
-// HPKE context. In particular, it does not recognize the AEAD codepoint
-// reserved for this purpose (0xFFFF).
+// Suites using AEAD_EXPORT_ONLY produce contexts that can only export
+// secrets: their Seal and Open functions return ErrAEADExportOnly.
+//
+// Payloads too large to be held in memory can be encrypted in chunks with
+// NewStreamWriter, and decrypted with NewStreamReader. Export-only contexts
+// can stream with a key they export, using NewStreamWriterWithKey and
+// NewStreamReaderWithKey.
 package hpke
 
 import (
@@ -29,9 +33,10 @@ type Context interface {
 	encoding.BinaryMarshaler
 	// Export takes a context string exporterContext and a desired length (in
 	// bytes), and produces a secret derived from the internal exporter secret
-	// using the corresponding KDF Expand function. It panics if length is
-	// greater than 255*N bytes, where N is the size (in bytes) of the KDF's
-	// output.
+	// using the corresponding KDF Expand function, or Derive function for
+	// one-stage KDFs. It panics if length is greater than 255*N bytes, where
+	// N is the size (in bytes) of the KDF's output, or greater than 65535
+	// bytes for one-stage KDFs.
 	Export(exporterContext []byte, length uint) []byte
 	// Suite returns the cipher suite corresponding to this context.
 	Suite() Suite
@@ -42,6 +47,7 @@ type Sealer interface {
 	Context
 	// Seal takes a plaintext and associated data to produce a ciphertext.
 	// The nonce is handled by the Sealer and incremented after each call.
+	// Returns ErrAEADExportOnly if the suite uses AEAD_EXPORT_ONLY.
 	Seal(pt, aad []byte) (ct []byte, err error)
 }
 
@@ -50,7 +56,7 @@ type Opener interface {
 	Context
 	// Open takes a ciphertext and associated data to recover, if successful,
 	// the plaintext. The nonce is handled by the Opener and incremented after
-	// each call.
+	// each call. Returns ErrAEADExportOnly if the suite uses AEAD_EXPORT_ONLY.
 	Open(ct, aad []byte) (pt []byte, err error)
 }
 
@@ -280,4 +286,5 @@ var (
 	ErrInvalidKEMSharedSecret = errors.New("hpke: invalid KEM shared secret")
 	ErrInvalidKEMDeriveKey    = errors.New("hpke: too many tries to derive KEM key")
 	ErrAEADSeqOverflows       = errors.New("hpke: AEAD sequence number overflows")
+	ErrAEADExportOnly         = errors.New("hpke: AEAD is export-only")
 )
@@ -63,6 +63,36 @@ func Example() {
 	// Output: true
 }
 
+func ExampleSuite_SealBase() {
+	// import "github.com/cloudflare/circl/hpke"
+
+	suite := hpke.NewSuite(hpke.KEM_X25519_HKDF_SHA256,
+		hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305)
+	info := []byte("public info string, known to both Alice and Bob")
+
+	// Bob announces his public key.
+	pkBob, skBob, err := hpke.KEM_X25519_HKDF_SHA256.Scheme().GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+
+	// Alice encrypts a single message to Bob's public key.
+	ptAlice := []byte("text encrypted to Bob's public key")
+	enc, ct, err := suite.SealBase(nil, pkBob, info, nil, ptAlice)
+	if err != nil {
+		panic(err)
+	}
+
+	// Bob decrypts it with his private key.
+	ptBob, err := suite.OpenBase(enc, skBob, info, nil, ct)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(bytes.Equal(ptAlice, ptBob))
+	// Output: true
+}
+
 func runHpkeBenchmark(b *testing.B, kem hpke.KEM, kdf hpke.KDF, aead hpke.AEAD) {
 	suite := hpke.NewSuite(kem, kdf, aead)
 
@@ -164,6 +194,11 @@ func BenchmarkHpkeRoundTrip(b *testing.B) {
 		{hpke.KEM_X25519_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
 		{hpke.KEM_X25519_KYBER768_DRAFT00, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
 		{hpke.KEM_XWING, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_ML_KEM_768, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_MLKEM768_P256, hpke.KDF_HKDF_SHA256, hpke.AEAD_AES128GCM},
+		{hpke.KEM_MLKEM1024_P384, hpke.KDF_HKDF_SHA384, hpke.AEAD_AES256GCM},
+		{hpke.KEM_XWING, hpke.KDF_SHAKE256, hpke.AEAD_ChaCha20Poly1305},
+		{hpke.KEM_ML_KEM_768, hpke.KDF_TurboSHAKE128, hpke.AEAD_AES128GCM},
 	}
 	for _, test := range tests {
 		runHpkeBenchmark(b, test.kem, test.kdf, test.aead)
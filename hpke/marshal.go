@@ -64,9 +64,11 @@ func unmarshalContext(raw []byte) (*encdecContext, error) {
 		return nil, errors.New("invalid key length")
 	}
 
-	c.AEAD, err = c.suite.aeadID.New(c.key)
-	if err != nil {
-		return nil, err
+	if c.suite.aeadID != AEAD_EXPORT_ONLY {
+		c.AEAD, err = c.suite.aeadID.New(c.key)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	Nn := int(c.suite.aeadID.NonceSize())
@@ -0,0 +1,292 @@
+package hpke
+
+// KEMs from draft-ietf-hpke-pq: ML-KEM, and its hybrids with X25519 (X-Wing),
+// P-256 and P-384.
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/kem"
+)
+
+// pqKEM wraps a generic KEM (kem.Scheme) to be used as a HPKE KEM as in
+// draft-ietf-hpke-pq. Its private keys are serialized as the seed from
+// which they are expanded, of size SeedSize() of the underlying KEM.
+type pqKEM struct {
+	kem.Scheme
+	id   KEM
+	name string
+}
+
+// seedPrivateKey is a private key of a pqKEM, which retains its seed.
+type seedPrivateKey struct {
+	kem.PrivateKey
+	scheme pqKEM
+	seed   []byte
+}
+
+func (h pqKEM) Name() string        { return h.name }
+func (h pqKEM) PrivateKeySize() int { return h.Scheme.SeedSize() }
+
+// DeriveKeyPair derives a key pair from a seed of any length, as specified
+// by draft-ietf-hpke-pq.
+func (h pqKEM) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	var suiteID [5]byte
+	copy(suiteID[:], "KEM")
+	binary.BigEndian.PutUint16(suiteID[3:], uint16(h.id))
+	sk := labeledDerive(KDF_SHAKE256, suiteID[:], seed,
+		[]byte("DeriveKeyPair"), nil, uint16(h.Scheme.SeedSize()))
+	return h.expand(sk)
+}
+
+func (h pqKEM) expand(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	pk, sk := h.Scheme.DeriveKeyPair(seed)
+	return pk, &seedPrivateKey{sk, h, bytes.Clone(seed)}
+}
+
+func (h pqKEM) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, h.Scheme.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := h.expand(seed)
+	return pk, sk, nil
+}
+
+func (h pqKEM) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != h.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	_, sk := h.expand(data)
+	return sk, nil
+}
+
+// Decapsulate accepts private keys of both h and the underlying KEM.
+func (h pqKEM) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	if s, ok := sk.(*seedPrivateKey); ok {
+		sk = s.PrivateKey
+	}
+	return h.Scheme.Decapsulate(sk, ct)
+}
+
+func (k *seedPrivateKey) Scheme() kem.Scheme { return k.scheme }
+
+func (k *seedPrivateKey) MarshalBinary() ([]byte, error) {
+	return bytes.Clone(k.seed), nil
+}
+
+func (k *seedPrivateKey) Equal(other kem.PrivateKey) bool {
+	oth, ok := other.(*seedPrivateKey)
+	return ok && k.scheme.id == oth.scheme.id && k.PrivateKey.Equal(oth.PrivateKey)
+}
+
+// qsfKEM is the hybrid of ML-KEM and a NIST curve of draft-ietf-hpke-pq,
+// following the QSF construction of X-Wing:
+//
+//	ss = SHA3-256(ss_PQ || ss_T || ct_T || pk_T || label)
+//
+// Public keys and ciphertexts are the concatenation of those of ML-KEM and
+// of the curve. Private keys are 32-byte seeds expanded with SHAKE256.
+type qsfKEM struct {
+	name  string
+	label string
+	pq    kem.Scheme
+	curve ecdh.Curve
+
+	scalarSize int // size of a candidate scalar
+	pointSize  int // size of an uncompressed point
+	candidates int // number of candidate scalars in an encapsulation seed
+}
+
+type qsfPublicKey struct {
+	scheme *qsfKEM
+	pq     kem.PublicKey
+	t      *ecdh.PublicKey
+}
+
+type qsfPrivateKey struct {
+	scheme *qsfKEM
+	seed   []byte
+	pq     kem.PrivateKey
+	t      *ecdh.PrivateKey
+}
+
+func (q *qsfKEM) Name() string               { return q.name }
+func (q *qsfKEM) SeedSize() int              { return 32 }
+func (q *qsfKEM) PrivateKeySize() int        { return 32 }
+func (q *qsfKEM) SharedKeySize() int         { return 32 }
+func (q *qsfKEM) PublicKeySize() int         { return q.pq.PublicKeySize() + q.pointSize }
+func (q *qsfKEM) CiphertextSize() int        { return q.pq.CiphertextSize() + q.pointSize }
+func (q *qsfKEM) EncapsulationSeedSize() int { return 32 + q.candidates*q.scalarSize }
+
+func (q *qsfKEM) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
+	if len(seed) != q.SeedSize() {
+		panic(kem.ErrSeedSize)
+	}
+
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+	seedPQ := make([]byte, q.pq.SeedSize())
+	_, _ = h.Read(seedPQ)
+	_, skPQ := q.pq.DeriveKeyPair(seedPQ)
+
+	// Candidate scalars are drawn until one is in range.
+	seedT := make([]byte, q.scalarSize)
+	for {
+		_, _ = h.Read(seedT)
+		skT, err := q.curve.NewPrivateKey(seedT)
+		if err == nil {
+			sk := &qsfPrivateKey{q, bytes.Clone(seed), skPQ, skT}
+			return sk.Public(), sk
+		}
+	}
+}
+
+func (q *qsfKEM) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	seed := make([]byte, q.SeedSize())
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	pk, sk := q.DeriveKeyPair(seed)
+	return pk, sk, nil
+}
+
+func (q *qsfKEM) combine(ssPQ, ssT, ctT, pkT []byte) []byte {
+	h := sha3.New256()
+	_, _ = h.Write(ssPQ)
+	_, _ = h.Write(ssT)
+	_, _ = h.Write(ctT)
+	_, _ = h.Write(pkT)
+	_, _ = h.Write([]byte(q.label))
+	return h.Sum(nil)
+}
+
+func (q *qsfKEM) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
+	seed := make([]byte, q.EncapsulationSeedSize())
+	if _, err = io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	return q.EncapsulateDeterministically(pk, seed)
+}
+
+// EncapsulateDeterministically uses the first 32 bytes of seed for ML-KEM,
+// and the first of the following candidate scalars that is in range for
+// the ephemeral key of the curve.
+func (q *qsfKEM) EncapsulateDeterministically(
+	pk kem.PublicKey, seed []byte,
+) (ct, ss []byte, err error) {
+	pub, ok := pk.(*qsfPublicKey)
+	if !ok || pub.scheme != q {
+		return nil, nil, kem.ErrTypeMismatch
+	}
+	if len(seed) != q.EncapsulationSeedSize() {
+		return nil, nil, kem.ErrSeedSize
+	}
+
+	ctPQ, ssPQ, err := q.pq.EncapsulateDeterministically(pub.pq, seed[:32])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var skE *ecdh.PrivateKey
+	for seedT := seed[32:]; skE == nil && len(seedT) > 0; seedT = seedT[q.scalarSize:] {
+		skE, _ = q.curve.NewPrivateKey(seedT[:q.scalarSize])
+	}
+	if skE == nil {
+		return nil, nil, ErrInvalidKEMDeriveKey
+	}
+	ssT, err := skE.ECDH(pub.t)
+	if err != nil {
+		return nil, nil, ErrInvalidKEMSharedSecret
+	}
+
+	ctT := skE.PublicKey().Bytes()
+	ss = q.combine(ssPQ, ssT, ctT, pub.t.Bytes())
+	return append(ctPQ, ctT...), ss, nil
+}
+
+func (q *qsfKEM) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
+	priv, ok := sk.(*qsfPrivateKey)
+	if !ok || priv.scheme != q {
+		return nil, kem.ErrTypeMismatch
+	}
+	if len(ct) != q.CiphertextSize() {
+		return nil, kem.ErrCiphertextSize
+	}
+
+	ctPQ, ctT := ct[:q.pq.CiphertextSize()], ct[q.pq.CiphertextSize():]
+	ssPQ, err := q.pq.Decapsulate(priv.pq, ctPQ)
+	if err != nil {
+		return nil, err
+	}
+	pkE, err := q.curve.NewPublicKey(ctT)
+	if err != nil {
+		return nil, kem.ErrCipherText
+	}
+	ssT, err := priv.t.ECDH(pkE)
+	if err != nil {
+		return nil, ErrInvalidKEMSharedSecret
+	}
+	return q.combine(ssPQ, ssT, ctT, priv.t.PublicKey().Bytes()), nil
+}
+
+func (q *qsfKEM) UnmarshalBinaryPublicKey(data []byte) (kem.PublicKey, error) {
+	if len(data) != q.PublicKeySize() {
+		return nil, kem.ErrPubKeySize
+	}
+	pkPQ, err := q.pq.UnmarshalBinaryPublicKey(data[:q.pq.PublicKeySize()])
+	if err != nil {
+		return nil, err
+	}
+	pkT, err := q.curve.NewPublicKey(data[q.pq.PublicKeySize():])
+	if err != nil {
+		return nil, ErrInvalidKEMPublicKey
+	}
+	return &qsfPublicKey{q, pkPQ, pkT}, nil
+}
+
+func (q *qsfKEM) UnmarshalBinaryPrivateKey(data []byte) (kem.PrivateKey, error) {
+	if len(data) != q.PrivateKeySize() {
+		return nil, kem.ErrPrivKeySize
+	}
+	_, sk := q.DeriveKeyPair(data)
+	return sk, nil
+}
+
+func (k *qsfPrivateKey) Scheme() kem.Scheme { return k.scheme }
+
+func (k *qsfPrivateKey) MarshalBinary() ([]byte, error) {
+	return bytes.Clone(k.seed), nil
+}
+
+func (k *qsfPrivateKey) Equal(other kem.PrivateKey) bool {
+	oth, ok := other.(*qsfPrivateKey)
+	return ok && k.scheme == oth.scheme &&
+		subtle.ConstantTimeCompare(k.seed, oth.seed) == 1
+}
+
+func (k *qsfPrivateKey) Public() kem.PublicKey {
+	return &qsfPublicKey{k.scheme, k.pq.Public(), k.t.PublicKey()}
+}
+
+func (k *qsfPublicKey) Scheme() kem.Scheme { return k.scheme }
+
+func (k *qsfPublicKey) MarshalBinary() ([]byte, error) {
+	ppk, err := k.pq.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(ppk, k.t.Bytes()...), nil
+}
+
+func (k *qsfPublicKey) Equal(other kem.PublicKey) bool {
+	oth, ok := other.(*qsfPublicKey)
+	return ok && k.scheme == oth.scheme &&
+		k.pq.Equal(oth.pq) && k.t.Equal(oth.t)
+}
@@ -0,0 +1,79 @@
+package hpke
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+)
+
+func TestPQKEM(t *testing.T) {
+	for _, k := range []KEM{
+		KEM_ML_KEM_512,
+		KEM_ML_KEM_768,
+		KEM_ML_KEM_1024,
+		KEM_MLKEM768_P256,
+		KEM_MLKEM1024_P384,
+		KEM_XWING,
+	} {
+		s := k.Scheme()
+		t.Run(s.Name(), func(t *testing.T) {
+			pk, sk, err := s.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair")
+
+			// Private keys are serialized as their seed.
+			psk, err := sk.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary")
+			test.CheckOk(len(psk) == s.PrivateKeySize(), "wrong size", t)
+			sk2, err := s.UnmarshalBinaryPrivateKey(psk)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+			test.CheckOk(sk.Equal(sk2), "private keys differ", t)
+			test.CheckOk(sk2.Public().Equal(pk), "public keys differ", t)
+			_, err = s.UnmarshalBinaryPrivateKey(psk[1:])
+			test.CheckIsErr(t, err, "short private key accepted")
+
+			ppk, err := pk.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary")
+			pk2, err := s.UnmarshalBinaryPublicKey(ppk)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPublicKey")
+			test.CheckOk(pk.Equal(pk2), "public keys differ", t)
+
+			ct, ss, err := s.Encapsulate(pk)
+			test.CheckNoErr(t, err, "Encapsulate")
+			test.CheckOk(len(ct) == s.CiphertextSize(), "wrong size", t)
+			ss2, err := s.Decapsulate(sk2, ct)
+			test.CheckNoErr(t, err, "Decapsulate")
+			test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+
+			// Keys of the underlying KEM are accepted for decapsulation.
+			if sk, ok := sk.(*seedPrivateKey); ok {
+				ss2, err = s.Decapsulate(sk.PrivateKey, ct)
+				test.CheckNoErr(t, err, "Decapsulate")
+				test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+			}
+		})
+	}
+}
+
+func TestQSFCandidates(t *testing.T) {
+	// A candidate scalar that is out of range is skipped, and an error is
+	// returned when there is none left.
+	q := kemMLKEM768P256.Scheme.(*qsfKEM)
+	pk, sk, err := q.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair")
+
+	seed := make([]byte, q.EncapsulationSeedSize())
+	for i := 32; i < len(seed); i++ {
+		seed[i] = 0xff
+	}
+	_, _, err = q.EncapsulateDeterministically(pk, seed)
+	test.CheckOk(err == ErrInvalidKEMDeriveKey, "no valid candidate", t)
+
+	seed[len(seed)-1] = 1
+	seed[len(seed)-q.scalarSize] = 0
+	ct, ss, err := q.EncapsulateDeterministically(pk, seed)
+	test.CheckNoErr(t, err, "EncapsulateDeterministically")
+	ss2, err := q.Decapsulate(sk, ct)
+	test.CheckNoErr(t, err, "Decapsulate")
+	test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+}
@@ -0,0 +1,157 @@
+package hpke
+
+import (
+	"io"
+
+	"github.com/cloudflare/circl/kem"
+)
+
+// The single-shot APIs of RFC 9180 (Section 6) set up an HPKE context, and
+// use it once to encrypt, decrypt or export a secret. As for Sender.Setup,
+// if rnd is nil, crypto/rand.Reader is used.
+
+// SealBase encrypts a plaintext to the receiver's public key using Base mode.
+// Returns the encapsulated key and the ciphertext.
+func (suite Suite) SealBase(
+	rnd io.Reader, pkR kem.PublicKey, info, aad, pt []byte,
+) (enc, ct []byte, err error) {
+	return suite.seal(pkR, info, aad, pt, func(s *Sender) ([]byte, Sealer, error) {
+		return s.Setup(rnd)
+	})
+}
+
+// OpenBase decrypts a ciphertext produced by SealBase.
+func (suite Suite) OpenBase(
+	enc []byte, skR kem.PrivateKey, info, aad, ct []byte,
+) (pt []byte, err error) {
+	return suite.open(skR, info, aad, ct, func(r *Receiver) (Opener, error) {
+		return r.Setup(enc)
+	})
+}
+
+// SealPSK encrypts a plaintext to the receiver's public key using PSK mode.
+// Returns the encapsulated key and the ciphertext.
+func (suite Suite) SealPSK(
+	rnd io.Reader, pkR kem.PublicKey, info, aad, pt, psk, pskID []byte,
+) (enc, ct []byte, err error) {
+	return suite.seal(pkR, info, aad, pt, func(s *Sender) ([]byte, Sealer, error) {
+		return s.SetupPSK(rnd, psk, pskID)
+	})
+}
+
+// OpenPSK decrypts a ciphertext produced by SealPSK.
+func (suite Suite) OpenPSK(
+	enc []byte, skR kem.PrivateKey, info, aad, ct, psk, pskID []byte,
+) (pt []byte, err error) {
+	return suite.open(skR, info, aad, ct, func(r *Receiver) (Opener, error) {
+		return r.SetupPSK(enc, psk, pskID)
+	})
+}
+
+// SealAuth encrypts a plaintext to the receiver's public key using Auth
+// mode, authenticating the sender's private key. Returns the encapsulated
+// key and the ciphertext.
+func (suite Suite) SealAuth(
+	rnd io.Reader, pkR kem.PublicKey, info, aad, pt []byte, skS kem.PrivateKey,
+) (enc, ct []byte, err error) {
+	return suite.seal(pkR, info, aad, pt, func(s *Sender) ([]byte, Sealer, error) {
+		return s.SetupAuth(rnd, skS)
+	})
+}
+
+// OpenAuth decrypts a ciphertext produced by SealAuth, and authenticates
+// the sender's public key.
+func (suite Suite) OpenAuth(
+	enc []byte, skR kem.PrivateKey, info, aad, ct []byte, pkS kem.PublicKey,
+) (pt []byte, err error) {
+	return suite.open(skR, info, aad, ct, func(r *Receiver) (Opener, error) {
+		return r.SetupAuth(enc, pkS)
+	})
+}
+
+// SealAuthPSK encrypts a plaintext to the receiver's public key using
+// Auth-PSK mode. Returns the encapsulated key and the ciphertext.
+func (suite Suite) SealAuthPSK(
+	rnd io.Reader, pkR kem.PublicKey, info, aad, pt, psk, pskID []byte,
+	skS kem.PrivateKey,
+) (enc, ct []byte, err error) {
+	return suite.seal(pkR, info, aad, pt, func(s *Sender) ([]byte, Sealer, error) {
+		return s.SetupAuthPSK(rnd, skS, psk, pskID)
+	})
+}
+
+// OpenAuthPSK decrypts a ciphertext produced by SealAuthPSK.
+func (suite Suite) OpenAuthPSK(
+	enc []byte, skR kem.PrivateKey, info, aad, ct, psk, pskID []byte,
+	pkS kem.PublicKey,
+) (pt []byte, err error) {
+	return suite.open(skR, info, aad, ct, func(r *Receiver) (Opener, error) {
+		return r.SetupAuthPSK(enc, psk, pskID, pkS)
+	})
+}
+
+// SendExport derives a secret of the given length shared with the holder of
+// the receiver's private key, using Base mode. Returns the encapsulated key
+// and the exported secret.
+func (suite Suite) SendExport(
+	rnd io.Reader, pkR kem.PublicKey, info, exporterContext []byte, length uint,
+) (enc, secret []byte, err error) {
+	s, err := suite.NewSender(pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, sealer, err := s.Setup(rnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, sealer.Export(exporterContext, length), nil
+}
+
+// ReceiveExport derives the secret exported by SendExport.
+func (suite Suite) ReceiveExport(
+	enc []byte, skR kem.PrivateKey, info, exporterContext []byte, length uint,
+) (secret []byte, err error) {
+	r, err := suite.NewReceiver(skR, info)
+	if err != nil {
+		return nil, err
+	}
+	opener, err := r.Setup(enc)
+	if err != nil {
+		return nil, err
+	}
+	return opener.Export(exporterContext, length), nil
+}
+
+func (suite Suite) seal(
+	pkR kem.PublicKey, info, aad, pt []byte,
+	setup func(*Sender) ([]byte, Sealer, error),
+) (enc, ct []byte, err error) {
+	s, err := suite.NewSender(pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, sealer, err := setup(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct, err = sealer.Seal(pt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ct, nil
+}
+
+func (suite Suite) open(
+	skR kem.PrivateKey, info, aad, ct []byte,
+	setup func(*Receiver) (Opener, error),
+) (pt []byte, err error) {
+	r, err := suite.NewReceiver(skR, info)
+	if err != nil {
+		return nil, err
+	}
+	opener, err := setup(r)
+	if err != nil {
+		return nil, err
+	}
+	return opener.Open(ct, aad)
+}
@@ -0,0 +1,348 @@
+package hpke
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Streaming encryption of large payloads, using the STREAM construction of
+// Hoang, Reyhanitabar, Rogaway and Vizár (https://ia.cr/2015/189).
+//
+// The plaintext is split into chunks of a fixed size, which are encrypted
+// with the AEAD of the suite. The key and a nonce prefix are exported from
+// an HPKE context, so both the sender and the receiver can derive them. The
+// nonce of each chunk is
+//
+//	nonce_prefix || I2OSP(chunk_index, 4) || last_chunk_flag
+//
+// so chunks can't be reordered, dropped or duplicated, and the stream can't
+// be truncated at a chunk boundary without being detected.
+//
+// A stream starts with a random salt, which is bound to the exported key so
+// that several streams can be derived from the same context.
+//
+// Streams can also be keyed directly, for instance with a secret exported
+// from a context whose suite is export-only, with NewStreamWriterWithKey
+// and NewStreamReaderWithKey. Such streams have no salt.
+
+const (
+	streamLabel    = "HPKE-stream-v1"
+	streamSaltSize = 32
+)
+
+var (
+	// ErrStreamTruncated is returned when a stream ends before its last
+	// chunk.
+	ErrStreamTruncated = errors.New("hpke: truncated stream")
+	// ErrStreamClosed is returned when writing to a closed stream.
+	ErrStreamClosed = errors.New("hpke: write to closed stream")
+	// ErrStreamChunkSize is returned for chunk sizes that are not positive,
+	// or don't fit in 32 bits.
+	ErrStreamChunkSize = errors.New("hpke: invalid stream chunk size")
+	// ErrStreamKey is returned when the key or the nonce prefix of a stream
+	// don't have the sizes required by its AEAD.
+	ErrStreamKey = errors.New("hpke: invalid stream key or nonce prefix")
+)
+
+type streamCipher struct {
+	cipher.AEAD
+	aad     []byte
+	nonce   []byte
+	counter uint64
+}
+
+func validChunkSize(chunkSize int) bool {
+	return chunkSize > 0 && uint64(chunkSize) <= math.MaxUint32
+}
+
+// streamKey exports the key and nonce prefix of a stream from ctx.
+func streamKey(ctx Context, salt []byte, chunkSize int) (aeadID AEAD, key, noncePrefix []byte, err error) {
+	if !validChunkSize(chunkSize) {
+		return 0, nil, nil, ErrStreamChunkSize
+	}
+	aeadID = ctx.Suite().aeadID
+	if aeadID == AEAD_EXPORT_ONLY {
+		return 0, nil, nil, ErrAEADExportOnly
+	}
+
+	Nk, Nn := aeadID.KeySize(), aeadID.NonceSize()
+	exporterContext := make([]byte, 0, len(streamLabel)+4+len(salt))
+	exporterContext = append(exporterContext, streamLabel...)
+	exporterContext = binary.BigEndian.AppendUint32(exporterContext, uint32(chunkSize))
+	exporterContext = append(exporterContext, salt...)
+	keyAndPrefix := ctx.Export(exporterContext, Nk+Nn-5)
+	return aeadID, keyAndPrefix[:Nk], keyAndPrefix[Nk:], nil
+}
+
+func newStreamCipher(aeadID AEAD, key, noncePrefix, aad []byte, chunkSize int) (*streamCipher, error) {
+	if !validChunkSize(chunkSize) {
+		return nil, ErrStreamChunkSize
+	}
+	if !aeadID.IsValid() {
+		return nil, ErrInvalidAEAD
+	}
+	if aeadID == AEAD_EXPORT_ONLY {
+		return nil, ErrAEADExportOnly
+	}
+	Nn := aeadID.NonceSize()
+	if uint(len(key)) != aeadID.KeySize() || uint(len(noncePrefix)) != Nn-5 {
+		return nil, ErrStreamKey
+	}
+
+	aead, err := aeadID.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, Nn)
+	copy(nonce, noncePrefix)
+	return &streamCipher{aead, aad, nonce, 0}, nil
+}
+
+// next returns the nonce of the next chunk.
+func (s *streamCipher) next(last bool) ([]byte, error) {
+	if s.counter > math.MaxUint32 {
+		return nil, ErrAEADSeqOverflows
+	}
+	n := len(s.nonce)
+	binary.BigEndian.PutUint32(s.nonce[n-5:n-1], uint32(s.counter))
+	s.nonce[n-1] = 0
+	if last {
+		s.nonce[n-1] = 1
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+type streamWriter struct {
+	w         io.Writer
+	s         *streamCipher
+	chunkSize int
+	buf, ct   []byte
+	err       error
+}
+
+// NewStreamWriter returns a writer that encrypts the data written to it in
+// chunks of chunkSize bytes, and writes the ciphertext to w. The key is
+// exported from ctx, which is usually a Sealer, and aad is authenticated
+// along with each chunk.
+//
+// The stream must be closed to write its last chunk; Close doesn't close w.
+// It can be decrypted with NewStreamReader, using a context set up with the
+// same secret, and the same aad and chunkSize.
+//
+// Returns ErrAEADExportOnly if the suite of ctx is export-only. Such
+// contexts can export a key for NewStreamWriterWithKey instead.
+func NewStreamWriter(ctx Context, w io.Writer, aad []byte, chunkSize int) (io.WriteCloser, error) {
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aeadID, key, noncePrefix, err := streamKey(ctx, salt, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newStreamCipher(aeadID, key, noncePrefix, aad, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(salt); err != nil {
+		return nil, err
+	}
+	return newStreamWriter(s, w, chunkSize), nil
+}
+
+// NewStreamWriterWithKey is like NewStreamWriter, but encrypts with the
+// given AEAD, key and nonce prefix instead of exporting them from a
+// context. The key must have aead.KeySize() bytes, and the nonce prefix
+// aead.NonceSize()-5 bytes.
+//
+// The key and nonce prefix must not be used for more than one stream. The
+// stream can be decrypted with NewStreamReaderWithKey.
+func NewStreamWriterWithKey(aead AEAD, key, noncePrefix []byte, w io.Writer, aad []byte, chunkSize int) (io.WriteCloser, error) {
+	s, err := newStreamCipher(aead, key, noncePrefix, aad, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamWriter(s, w, chunkSize), nil
+}
+
+func newStreamWriter(s *streamCipher, w io.Writer, chunkSize int) *streamWriter {
+	return &streamWriter{
+		w:         w,
+		s:         s,
+		chunkSize: chunkSize,
+		buf:       make([]byte, 0, chunkSize),
+		ct:        make([]byte, 0, chunkSize+s.Overhead()),
+	}
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+	n := 0
+	for len(p) > 0 {
+		// A full chunk is only flushed once more data follows, so the last
+		// chunk is always written by Close.
+		if len(sw.buf) == sw.chunkSize {
+			if err := sw.flush(false); err != nil {
+				return n, err
+			}
+		}
+		m := min(sw.chunkSize-len(sw.buf), len(p))
+		sw.buf = append(sw.buf, p[:m]...)
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Close encrypts and writes the last chunk of the stream.
+func (sw *streamWriter) Close() error {
+	if sw.err != nil {
+		if sw.err == ErrStreamClosed {
+			return nil
+		}
+		return sw.err
+	}
+	if err := sw.flush(true); err != nil {
+		return err
+	}
+	sw.err = ErrStreamClosed
+	return nil
+}
+
+func (sw *streamWriter) flush(last bool) error {
+	nonce, err := sw.s.next(last)
+	if err != nil {
+		sw.err = err
+		return err
+	}
+	sw.ct = sw.s.Seal(sw.ct[:0], nonce, sw.buf, sw.s.aad)
+	sw.buf = sw.buf[:0]
+	if _, err := sw.w.Write(sw.ct); err != nil {
+		sw.err = err
+		return err
+	}
+	return nil
+}
+
+type streamReader struct {
+	r       *bufio.Reader
+	s       *streamCipher
+	ct, pt  []byte
+	pending []byte
+	done    bool
+	err     error
+}
+
+// NewStreamReader returns a reader that decrypts the stream written by
+// NewStreamWriter, which is read from r. The key is exported from ctx,
+// which is usually an Opener.
+//
+// Plaintext is only returned once its chunk is authenticated. The reader
+// returns io.EOF after the last chunk, and ErrStreamTruncated if r ends
+// before it.
+func NewStreamReader(ctx Context, r io.Reader, aad []byte, chunkSize int) (io.Reader, error) {
+	if !validChunkSize(chunkSize) {
+		return nil, ErrStreamChunkSize
+	}
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrStreamTruncated
+		}
+		return nil, err
+	}
+	aeadID, key, noncePrefix, err := streamKey(ctx, salt, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newStreamCipher(aeadID, key, noncePrefix, aad, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamReader(s, r, chunkSize), nil
+}
+
+// NewStreamReaderWithKey returns a reader that decrypts the stream written
+// by NewStreamWriterWithKey with the same AEAD, key, nonce prefix, aad and
+// chunkSize, which is read from r.
+func NewStreamReaderWithKey(aead AEAD, key, noncePrefix []byte, r io.Reader, aad []byte, chunkSize int) (io.Reader, error) {
+	s, err := newStreamCipher(aead, key, noncePrefix, aad, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamReader(s, r, chunkSize), nil
+}
+
+func newStreamReader(s *streamCipher, r io.Reader, chunkSize int) *streamReader {
+	return &streamReader{
+		r:  bufio.NewReader(r),
+		s:  s,
+		ct: make([]byte, chunkSize+s.Overhead()),
+		pt: make([]byte, 0, chunkSize),
+	}
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	for len(sr.pending) == 0 {
+		if sr.err != nil {
+			return 0, sr.err
+		}
+		if sr.done {
+			return 0, io.EOF
+		}
+		if err := sr.readChunk(); err != nil {
+			sr.err = err
+		}
+	}
+	n := copy(p, sr.pending)
+	sr.pending = sr.pending[n:]
+	return n, nil
+}
+
+// readChunk decrypts the next chunk. A chunk is the last one if the stream
+// ends right after it. If such a chunk was sealed as an intermediate one,
+// the stream was cut at a chunk boundary and is reported as truncated.
+func (sr *streamReader) readChunk() error {
+	n, err := io.ReadFull(sr.r, sr.ct)
+	var last bool
+	switch err {
+	case nil:
+		_, err = sr.r.Peek(1)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		last = err == io.EOF
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return ErrStreamTruncated
+	default:
+		return err
+	}
+
+	nonce, err := sr.s.next(last)
+	if err != nil {
+		return err
+	}
+	sr.pending, err = sr.s.Open(sr.pt[:0], nonce, sr.ct[:n], sr.s.aad)
+	if err != nil {
+		if last {
+			nonce[len(nonce)-1] = 0
+			if _, e := sr.s.Open(sr.pt[:0], nonce, sr.ct[:n], sr.s.aad); e == nil {
+				sr.pending = nil
+				return ErrStreamTruncated
+			}
+		}
+		return err
+	}
+	sr.done = last
+	return nil
+}
@@ -0,0 +1,224 @@
+package hpke
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/cloudflare/circl/internal/test"
+)
+
+const testChunkSize = 64
+
+func streamContexts(t testing.TB, aead AEAD) (Sealer, Opener) {
+	s := NewSuite(KEM_X25519_HKDF_SHA256, KDF_HKDF_SHA256, aead)
+	pk, sk, err := s.kemID.Scheme().GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair")
+	sender, err := s.NewSender(pk, nil)
+	test.CheckNoErr(t, err, "NewSender")
+	receiver, err := s.NewReceiver(sk, nil)
+	test.CheckNoErr(t, err, "NewReceiver")
+	enc, sealer, err := sender.Setup(rand.Reader)
+	test.CheckNoErr(t, err, "Setup")
+	opener, err := receiver.Setup(enc)
+	test.CheckNoErr(t, err, "Setup")
+	return sealer, opener
+}
+
+func sealStream(t testing.TB, ctx Context, aad, msg []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewStreamWriter(ctx, &buf, aad, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamWriter")
+	// Write in pieces that don't align with chunks.
+	for p := msg; len(p) > 0; {
+		n := min(len(p), 23)
+		_, err = w.Write(p[:n])
+		test.CheckNoErr(t, err, "Write")
+		p = p[n:]
+	}
+	test.CheckNoErr(t, w.Close(), "Close")
+	return buf.Bytes()
+}
+
+func openStream(ctx Context, aad, ct []byte) ([]byte, error) {
+	r, err := NewStreamReader(ctx, iotest.OneByteReader(bytes.NewReader(ct)),
+		aad, testChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestStream(t *testing.T) {
+	aad := []byte("backup")
+	for _, aead := range []AEAD{AEAD_AES128GCM, AEAD_ChaCha20Poly1305} {
+		sealer, opener := streamContexts(t, aead)
+		for _, size := range []int{
+			0, 1, testChunkSize - 1, testChunkSize, testChunkSize + 1,
+			3 * testChunkSize, 5*testChunkSize + 7,
+		} {
+			t.Run(fmt.Sprintf("%v/%v", aead, size), func(t *testing.T) {
+				msg := make([]byte, size)
+				_, _ = rand.Read(msg)
+				ct := sealStream(t, sealer, aad, msg)
+				chunks := size/testChunkSize + 1
+				if size > 0 && size%testChunkSize == 0 {
+					chunks--
+				}
+				wantLen := streamSaltSize + size + chunks*int(aead.CipherLen(0))
+				test.CheckOk(len(ct) == wantLen, "wrong ciphertext size", t)
+
+				got, err := openStream(opener, aad, ct)
+				test.CheckNoErr(t, err, "open")
+				test.CheckOk(bytes.Equal(got, msg), "wrong plaintext", t)
+
+				// Streams from the same context use different keys.
+				ct2 := sealStream(t, sealer, aad, msg)
+				test.CheckOk(!bytes.Equal(ct[streamSaltSize:], ct2[streamSaltSize:]),
+					"stream key reused", t)
+			})
+		}
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	aad := []byte("backup")
+	sealer, opener := streamContexts(t, AEAD_AES128GCM)
+	msg := make([]byte, 3*testChunkSize+10)
+	ct := sealStream(t, sealer, aad, msg)
+	chunk := testChunkSize + int(AEAD_AES128GCM.CipherLen(0))
+	first := ct[streamSaltSize : streamSaltSize+chunk]
+	second := ct[streamSaltSize+chunk : streamSaltSize+2*chunk]
+
+	swapped := append([]byte{}, ct...)
+	copy(swapped[streamSaltSize:], second)
+	copy(swapped[streamSaltSize+chunk:], first)
+
+	flipped := append([]byte{}, ct...)
+	flipped[len(flipped)-1] ^= 1
+
+	dropped := append(append([]byte{}, ct[:streamSaltSize]...),
+		ct[streamSaltSize+chunk:]...)
+
+	for _, tc := range []struct {
+		name string
+		ct   []byte
+		aad  []byte
+		err  error
+	}{
+		{"truncated at chunk", ct[:streamSaltSize+3*chunk], aad, ErrStreamTruncated},
+		{"truncated in chunk", ct[:len(ct)-1], aad, nil},
+		{"truncated salt", ct[:streamSaltSize-1], aad, ErrStreamTruncated},
+		{"empty", nil, aad, ErrStreamTruncated},
+		{"reordered", swapped, aad, nil},
+		{"dropped", dropped, aad, nil},
+		{"modified", flipped, aad, nil},
+		{"wrong aad", ct, []byte("other"), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := openStream(opener, tc.aad, tc.ct)
+			test.CheckIsErr(t, err, "tampered stream accepted")
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				test.ReportError(t, err, tc.err)
+			}
+			// Nothing past the last authentic chunk is released.
+			test.CheckOk(len(got) <= len(msg), "unexpected plaintext", t)
+		})
+	}
+
+	// A stream ending right after a chunk that isn't the last one is
+	// reported as truncated.
+	r, err := NewStreamReader(opener, bytes.NewReader(ct[:streamSaltSize+chunk]),
+		aad, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamReader")
+	got, err := io.ReadAll(r)
+	if !errors.Is(err, ErrStreamTruncated) {
+		test.ReportError(t, err, ErrStreamTruncated)
+	}
+	test.CheckOk(len(got) == 0, "unexpected plaintext", t)
+}
+
+func TestStreamErrors(t *testing.T) {
+	sealer, _ := streamContexts(t, AEAD_AES128GCM)
+	var buf bytes.Buffer
+	_, err := NewStreamWriter(sealer, &buf, nil, 0)
+	test.CheckOk(err == ErrStreamChunkSize, "zero chunk size accepted", t)
+	_, err = NewStreamReader(sealer, &buf, nil, -1)
+	test.CheckOk(err == ErrStreamChunkSize, "negative chunk size accepted", t)
+
+	w, err := NewStreamWriter(sealer, &buf, nil, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamWriter")
+	test.CheckNoErr(t, w.Close(), "Close")
+	test.CheckNoErr(t, w.Close(), "Close")
+	_, err = w.Write([]byte{1})
+	test.CheckOk(err == ErrStreamClosed, "write after close accepted", t)
+
+	exportOnly, _ := streamContexts(t, AEAD_EXPORT_ONLY)
+	_, err = NewStreamWriter(exportOnly, &buf, nil, testChunkSize)
+	test.CheckOk(err == ErrAEADExportOnly, "export-only suite accepted", t)
+}
+
+func TestStreamWithKey(t *testing.T) {
+	// An export-only context can't stream by itself, but can export a key
+	// for a stream encrypted with another AEAD.
+	sealer, opener := streamContexts(t, AEAD_EXPORT_ONLY)
+	aead := AEAD_ChaCha20Poly1305
+	Nk, Nn := aead.KeySize(), aead.NonceSize()
+	exporterContext := []byte("stream key")
+	sKey := sealer.Export(exporterContext, Nk+Nn-5)
+	oKey := opener.Export(exporterContext, Nk+Nn-5)
+
+	aad := []byte("backup")
+	msg := make([]byte, 5*testChunkSize+7)
+	_, _ = rand.Read(msg)
+
+	var buf bytes.Buffer
+	w, err := NewStreamWriterWithKey(aead, sKey[:Nk], sKey[Nk:], &buf, aad, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamWriterWithKey")
+	_, err = w.Write(msg)
+	test.CheckNoErr(t, err, "Write")
+	test.CheckNoErr(t, w.Close(), "Close")
+	ct := buf.Bytes()
+	test.CheckOk(len(ct) == int(aead.CipherLen(testChunkSize))*5+int(aead.CipherLen(7)),
+		"unexpected stream length", t)
+
+	r, err := NewStreamReaderWithKey(aead, oKey[:Nk], oKey[Nk:], bytes.NewReader(ct), aad, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamReaderWithKey")
+	got, err := io.ReadAll(r)
+	test.CheckNoErr(t, err, "ReadAll")
+	test.CheckOk(bytes.Equal(got, msg), "plaintext mismatch", t)
+
+	r, err = NewStreamReaderWithKey(aead, oKey[:Nk], oKey[Nk:], bytes.NewReader(ct[:len(ct)-int(aead.CipherLen(7))]), aad, testChunkSize)
+	test.CheckNoErr(t, err, "NewStreamReaderWithKey")
+	_, err = io.ReadAll(r)
+	test.CheckOk(err == ErrStreamTruncated, "truncated stream accepted", t)
+
+	_, err = NewStreamWriterWithKey(aead, sKey[:Nk-1], sKey[Nk:], &buf, aad, testChunkSize)
+	test.CheckOk(err == ErrStreamKey, "short key accepted", t)
+	_, err = NewStreamReaderWithKey(aead, oKey[:Nk], oKey[Nk+1:], &buf, aad, testChunkSize)
+	test.CheckOk(err == ErrStreamKey, "short nonce prefix accepted", t)
+	_, err = NewStreamWriterWithKey(AEAD_EXPORT_ONLY, nil, nil, &buf, aad, testChunkSize)
+	test.CheckOk(err == ErrAEADExportOnly, "export-only AEAD accepted", t)
+	_, err = NewStreamWriterWithKey(AEAD(0x42), sKey[:Nk], sKey[Nk:], &buf, aad, testChunkSize)
+	test.CheckOk(err == ErrInvalidAEAD, "invalid AEAD accepted", t)
+}
+
+func BenchmarkStream(b *testing.B) {
+	sealer, opener := streamContexts(b, AEAD_AES128GCM)
+	msg := make([]byte, 1<<20)
+	b.SetBytes(int64(len(msg)))
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		w, _ := NewStreamWriter(sealer, &buf, nil, 1<<16)
+		_, _ = w.Write(msg)
+		_ = w.Close()
+		r, _ := NewStreamReader(opener, &buf, nil, 1<<16)
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
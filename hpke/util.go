@@ -1,6 +1,7 @@
 package hpke
 
 import (
+	"crypto/cipher"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -11,31 +12,42 @@ func (st state) keySchedule(ss, info, psk, pskID []byte) (*encdecContext, error)
 		return nil, err
 	}
 
-	pskIDHash := st.labeledExtract(nil, []byte("psk_id_hash"), pskID)
-	infoHash := st.labeledExtract(nil, []byte("info_hash"), info)
-	keySchCtx := append(append(
-		[]byte{st.modeID},
-		pskIDHash...),
-		infoHash...)
-
-	secret := st.labeledExtract(ss, []byte("secret"), psk)
-
+	var secret, keySchCtx, key, baseNonce, exporterSecret []byte
 	Nk := uint16(st.aeadID.KeySize())
-	key := st.labeledExpand(secret, []byte("key"), keySchCtx, Nk)
+	Nn := uint16(st.aeadID.NonceSize())
+	Nh := uint16(st.kdfID.ExtractSize())
+	if st.kdfID.IsOneStage() {
+		// The PSK, the shared secret, the PSK ID and info are length-prefixed
+		// and absorbed at once, as in draft-ietf-hpke-pq.
+		secrets := appendLengthPrefixed(appendLengthPrefixed(nil, psk), ss)
+		keySchCtx = appendLengthPrefixed(appendLengthPrefixed(
+			[]byte{st.modeID},
+			pskID),
+			info)
+		secret = st.labeledDerive(secrets, []byte("secret"), keySchCtx, Nk+Nn+Nh)
+		key, baseNonce, exporterSecret = secret[:Nk], secret[Nk:Nk+Nn], secret[Nk+Nn:]
+	} else {
+		pskIDHash := st.labeledExtract(nil, []byte("psk_id_hash"), pskID)
+		infoHash := st.labeledExtract(nil, []byte("info_hash"), info)
+		keySchCtx = append(append(
+			[]byte{st.modeID},
+			pskIDHash...),
+			infoHash...)
 
-	aead, err := st.aeadID.New(key)
-	if err != nil {
-		return nil, err
+		secret = st.labeledExtract(ss, []byte("secret"), psk)
+		key = st.labeledExpand(secret, []byte("key"), keySchCtx, Nk)
+		baseNonce = st.labeledExpand(secret, []byte("base_nonce"), keySchCtx, Nn)
+		exporterSecret = st.labeledExpand(secret, []byte("exp"), keySchCtx, Nh)
 	}
 
-	Nn := uint16(aead.NonceSize())
-	baseNonce := st.labeledExpand(secret, []byte("base_nonce"), keySchCtx, Nn)
-	exporterSecret := st.labeledExpand(
-		secret,
-		[]byte("exp"),
-		keySchCtx,
-		uint16(st.kdfID.ExtractSize()),
-	)
+	var aead cipher.AEAD
+	if st.aeadID != AEAD_EXPORT_ONLY {
+		var err error
+		aead, err = st.aeadID.New(key)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &encdecContext{
 		st.Suite,
@@ -119,3 +131,29 @@ func (suite Suite) labeledExpand(prk, label, info []byte, l uint16) []byte {
 		info...)
 	return suite.kdfID.Expand(prk, labeledInfo, uint(l))
 }
+
+func (suite Suite) labeledDerive(ikm, label, context []byte, l uint16) []byte {
+	suiteID := suite.getSuiteID()
+	return labeledDerive(suite.kdfID, suiteID[:], ikm, label, context, l)
+}
+
+// labeledDerive is the LabeledDerive function of one-stage KDFs, specified
+// in draft-ietf-hpke-pq.
+func labeledDerive(kdf KDF, suiteID, ikm, label, context []byte, l uint16) []byte {
+	labeledIKM := make([]byte, 0,
+		len(ikm)+len(versionLabel)+len(suiteID)+2+len(label)+2+len(context))
+	labeledIKM = append(append(append(labeledIKM,
+		ikm...),
+		versionLabel...),
+		suiteID...)
+	labeledIKM = appendLengthPrefixed(labeledIKM, label)
+	labeledIKM = binary.BigEndian.AppendUint16(labeledIKM, l)
+	labeledIKM = append(labeledIKM, context...)
+	return kdf.Derive(labeledIKM, uint(l))
+}
+
+// appendLengthPrefixed appends b to dst, prefixed with its length as a
+// 16-bit big-endian integer.
+func appendLengthPrefixed(dst, b []byte) []byte {
+	return append(binary.BigEndian.AppendUint16(dst, uint16(len(b))), b...)
+}
@@ -29,6 +29,35 @@ func TestVectors(t *testing.T) {
 	}
 }
 
+func TestVectorsPQ(t *testing.T) {
+	// Test vectors from draft-ietf-hpke-pq.
+	vectors := readFile(t, "testdata/vectors_hpke_pq.json.gz")
+	for i, v := range vectors {
+		t.Run(fmt.Sprintf("v%v", i), func(t *testing.T) {
+			v.verify(t)
+			v.checkDeriveKeyPair(t)
+		})
+	}
+}
+
+func (v *vector) checkDeriveKeyPair(t *testing.T) {
+	k := KEM(v.KemID)
+	pk, sk := k.Scheme().DeriveKeyPair(v.IkmR)
+	if got := mustEncodePublicKey(pk); !bytes.Equal(got, v.PkRm) {
+		test.ReportError(t, got, v.PkRm, k)
+	}
+	got := mustEncodePrivateKey(sk)
+	if k == KEM_X448_HKDF_SHA512 {
+		// The vectors encode X448 private keys clamped, which denotes the
+		// same key.
+		got[0] &= 252
+		got[55] |= 128
+	}
+	if !bytes.Equal(got, v.SkRm) {
+		test.ReportError(t, got, v.SkRm, k)
+	}
+}
+
 func (v *vector) verify(t *testing.T) {
 	m := v.ModeID
 	kem, kdf, aead := KEM(v.KemID), KDF(v.KdfID), AEAD(v.AeadID)
@@ -51,6 +80,73 @@ func (v *vector) verify(t *testing.T) {
 	v.checkEncryptions(t, sealer, opener, m)
 	v.checkExports(t, sealer, m)
 	v.checkExports(t, opener, m)
+	v.checkSingleShot(t, kem.Scheme(), m, s)
+}
+
+func (v *vector) checkSingleShot(t *testing.T, k kem.Scheme, m modeID, s Suite) {
+	h := fmt.Sprintf("mode: %v %v\n", m, s)
+	pkR, err := k.UnmarshalBinaryPublicKey(v.PkRm)
+	test.CheckNoErr(t, err, h+"bad public key")
+	skR, err := k.UnmarshalBinaryPrivateKey(v.SkRm)
+	test.CheckNoErr(t, err, h+"bad private key")
+	var skS kem.PrivateKey
+	var pkS kem.PublicKey
+	if m == modeAuth || m == modeAuthPSK {
+		skS, err = k.UnmarshalBinaryPrivateKey(v.SkSm)
+		test.CheckNoErr(t, err, h+"bad private key")
+		pkS, err = k.UnmarshalBinaryPublicKey(v.PkSm)
+		test.CheckNoErr(t, err, h+"bad public key")
+	}
+
+	// Only the first encryption of a context can be checked.
+	if len(v.Encryptions) > 0 {
+		e := v.Encryptions[0]
+		rnd := bytes.NewReader(v.IkmE)
+		var enc, ct, pt []byte
+		var errS, errR error
+		switch m {
+		case modeBase:
+			enc, ct, errS = s.SealBase(rnd, pkR, v.Info, e.Aad, e.Plaintext)
+			pt, errR = s.OpenBase(enc, skR, v.Info, e.Aad, ct)
+		case modePSK:
+			enc, ct, errS = s.SealPSK(rnd, pkR, v.Info, e.Aad, e.Plaintext, v.Psk, v.PskID)
+			pt, errR = s.OpenPSK(enc, skR, v.Info, e.Aad, ct, v.Psk, v.PskID)
+		case modeAuth:
+			enc, ct, errS = s.SealAuth(rnd, pkR, v.Info, e.Aad, e.Plaintext, skS)
+			pt, errR = s.OpenAuth(enc, skR, v.Info, e.Aad, ct, pkS)
+		case modeAuthPSK:
+			enc, ct, errS = s.SealAuthPSK(rnd, pkR, v.Info, e.Aad, e.Plaintext, v.Psk, v.PskID, skS)
+			pt, errR = s.OpenAuthPSK(enc, skR, v.Info, e.Aad, ct, v.Psk, v.PskID, pkS)
+		}
+		test.CheckNoErr(t, errS, h+"error on single-shot seal")
+		test.CheckNoErr(t, errR, h+"error on single-shot open")
+		if !bytes.Equal(enc, v.Enc) {
+			test.ReportError(t, enc, v.Enc, m, s)
+		}
+		if got := hex.EncodeToString(ct); got != e.Ciphertext {
+			test.ReportError(t, got, e.Ciphertext, m, s)
+		}
+		if !bytes.Equal(pt, e.Plaintext) {
+			test.ReportError(t, pt, e.Plaintext, m, s)
+		}
+	}
+
+	// The single-shot exporter is only defined for Base mode.
+	if m == modeBase && len(v.Exports) > 0 {
+		x := v.Exports[0]
+		enc, got, err := s.SendExport(bytes.NewReader(v.IkmE), pkR, v.Info,
+			x.ExportContext, uint(x.ExportLength))
+		test.CheckNoErr(t, err, h+"error on SendExport")
+		if !bytes.Equal(enc, v.Enc) || !bytes.Equal(got, x.ExportValue) {
+			test.ReportError(t, got, x.ExportValue, m, s)
+		}
+		got, err = s.ReceiveExport(enc, skR, v.Info,
+			x.ExportContext, uint(x.ExportLength))
+		test.CheckNoErr(t, err, h+"error on ReceiveExport")
+		if !bytes.Equal(got, x.ExportValue) {
+			test.ReportError(t, got, x.ExportValue, m, s)
+		}
+	}
 }
 
 func (v *vector) getActors(
@@ -127,6 +223,9 @@ func (v *vector) setup(t *testing.T, k kem.Scheme,
 	test.CheckNoErr(t, errR, h+"error on receiver setup")
 	test.CheckNoErr(t, errSK, h+"bad private key")
 	test.CheckNoErr(t, errPK, h+"bad public key")
+	if !bytes.Equal(enc, v.Enc) {
+		test.ReportError(t, enc, v.Enc, m, s)
+	}
 
 	return sealer, opener
 }
@@ -431,3 +530,66 @@ func TestHybridKemRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestOneStageKDF(t *testing.T) {
+	// The vectors only cover the base mode, so check that both parties
+	// agree in all modes, and that the PSK inputs are bound to the keys.
+	psk, pskID := []byte("pre-shared key"), []byte("psk id")
+	info := []byte("info")
+	for _, kdf := range []KDF{
+		KDF_SHAKE128, KDF_SHAKE256, KDF_TurboSHAKE128, KDF_TurboSHAKE256,
+	} {
+		s := NewSuite(KEM_X25519_HKDF_SHA256, kdf, AEAD_AES128GCM)
+		pkR, skR, err := s.kemID.Scheme().GenerateKeyPair()
+		test.CheckNoErr(t, err, "GenerateKeyPair")
+		pkS, skS, err := s.kemID.Scheme().GenerateKeyPair()
+		test.CheckNoErr(t, err, "GenerateKeyPair")
+
+		contexts := map[string]bool{}
+		for _, m := range []modeID{modeBase, modePSK, modeAuth, modeAuthPSK} {
+			sender, err := s.NewSender(pkR, info)
+			test.CheckNoErr(t, err, "NewSender")
+			recv, err := s.NewReceiver(skR, info)
+			test.CheckNoErr(t, err, "NewReceiver")
+
+			var (
+				enc    []byte
+				sealer Sealer
+				opener Opener
+			)
+			switch m {
+			case modeBase:
+				enc, sealer, err = sender.Setup(nil)
+				test.CheckNoErr(t, err, "Setup")
+				opener, err = recv.Setup(enc)
+			case modePSK:
+				enc, sealer, err = sender.SetupPSK(nil, psk, pskID)
+				test.CheckNoErr(t, err, "SetupPSK")
+				opener, err = recv.SetupPSK(enc, psk, pskID)
+			case modeAuth:
+				enc, sealer, err = sender.SetupAuth(nil, skS)
+				test.CheckNoErr(t, err, "SetupAuth")
+				opener, err = recv.SetupAuth(enc, pkS)
+			case modeAuthPSK:
+				enc, sealer, err = sender.SetupAuthPSK(nil, skS, psk, pskID)
+				test.CheckNoErr(t, err, "SetupAuthPSK")
+				opener, err = recv.SetupAuthPSK(enc, psk, pskID, pkS)
+			}
+			test.CheckNoErr(t, err, "receiver setup")
+
+			msg := []byte("message")
+			ct, err := sealer.Seal(msg, nil)
+			test.CheckNoErr(t, err, "Seal")
+			pt, err := opener.Open(ct, nil)
+			test.CheckNoErr(t, err, "Open")
+			test.CheckOk(bytes.Equal(pt, msg), "wrong plaintext", t)
+			test.CheckOk(bytes.Equal(
+				sealer.Export([]byte("ctx"), 100),
+				opener.Export([]byte("ctx"), 100)), "exports differ", t)
+
+			ctx := sealer.(*sealContext).keyScheduleContext
+			contexts[string(ctx)] = true
+		}
+		test.CheckOk(len(contexts) == 4, "key schedule contexts collide", t)
+	}
+}
@@ -1,11 +1,29 @@
+//go:generate go run gen.go
+
 // Package frodo provides the key encapsulation mechanism FrodoKEM.
 //
-// Compatible with the implementation submitted to round 3 of the
-// NIST PQC competition [1]. This implementation draws heavily from the PQClean
-// implementation [2].
+// Two versions of FrodoKEM exist: the one submitted to round 3 of the NIST
+// PQC competition [1], and the proposal for standardization at ISO [2],
+// which adds a salt to the ciphertexts. The ISO proposal calls the round 3
+// version, without salt, eFrodoKEM; it is intended for keys used for a
+// small number of encapsulations only. As the ISO proposal reuses the names
+// of round 3 for the salted version, the names of the salted schemes have
+// the suffix -Salted:
+//
+//	FrodoKEM-640-SHAKE            round 3, no salt
+//	eFrodoKEM-976-*               round 3, no salt
+//	eFrodoKEM-1344-*              round 3, no salt
+//	FrodoKEM-976-*-Salted         ISO, salted
+//	FrodoKEM-1344-*-Salted        ISO, salted
+//
+// FrodoKEM-640-SHAKE predates the ISO proposal, which calls it
+// eFrodoKEM-640-SHAKE. The salted FrodoKEM-640 is not provided.
+//
+// This implementation draws heavily from the PQClean implementation [3].
 //
 // References:
 //
 //	[1] https://frodokem.org/files/FrodoKEM-specification-20210604.pdf
-//	[2] https://github.com/PQClean/PQClean/tree/master/crypto_kem/frodokem640shake/clean
+//	[2] https://frodokem.org/files/FrodoKEM-ISO-20230314.pdf
+//	[3] https://github.com/PQClean/PQClean/tree/master/crypto_kem/frodokem640shake/clean
 package frodo
@@ -0,0 +1,152 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates the FrodoKEM variants from templates to prevent too much
+// duplicated code between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type Instance struct {
+	Name string
+
+	// Dimension n of the matrices.
+	N int
+
+	// Logarithm of the modulus q.
+	LogQ int
+
+	// Number of bits encoded per matrix entry, denoted by B.
+	ExtractedBits int
+
+	// Length in bytes of the shared key and of s, denoted by len_sec/8.
+	Sec int
+
+	// Cumulative distribution table of the noise.
+	CDF []uint16
+
+	// Whether the matrix A is generated with AES-128 instead of SHAKE128.
+	AES bool
+
+	// Whether this is the ephemeral variant of the ISO proposal, which is
+	// the FrodoKEM of round 3 of the NIST PQC competition: no salt, and a
+	// shorter seedSE.
+	Ephemeral bool
+}
+
+func (m Instance) Pkg() string {
+	name := strings.ReplaceAll(m.Name, "KEM", "")
+	return strings.ToLower(strings.ReplaceAll(name, "-", ""))
+}
+
+func (m Instance) Variant() string {
+	if m.AES {
+		return "AES"
+	}
+	return "SHAKE"
+}
+
+// Shake returns the function of the XOF used for everything except the
+// expansion of A.
+func (m Instance) Shake() string {
+	if m.N == 640 {
+		return "NewShake128"
+	}
+	return "NewShake256"
+}
+
+func (m Instance) SeedSESize() int {
+	if m.Ephemeral {
+		return m.Sec
+	}
+	return 2 * m.Sec
+}
+
+func (m Instance) SaltSize() int {
+	if m.Ephemeral {
+		return 0
+	}
+	return 2 * m.Sec
+}
+
+func (m Instance) CDFGo() string {
+	s := make([]string, len(m.CDF))
+	for i, v := range m.CDF {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+var (
+	cdf640  = []uint16{4643, 13363, 20579, 25843, 29227, 31145, 32103, 32525, 32689, 32745, 32762, 32766, 32767}
+	cdf976  = []uint16{5638, 15915, 23689, 28571, 31116, 32217, 32613, 32731, 32760, 32766, 32767}
+	cdf1344 = []uint16{9142, 23462, 30338, 32361, 32725, 32765, 32767}
+
+	Instances = []Instance{
+		// FrodoKEM-640-SHAKE predates the ISO proposal, and is thus the
+		// variant of round 3, that is, without salt. The salted variants
+		// of the ISO proposal have the suffix -Salted so that they can't
+		// be mistaken for the ones of round 3 with the same name.
+		{Name: "FrodoKEM-640-SHAKE", N: 640, LogQ: 15, ExtractedBits: 2, Sec: 16, CDF: cdf640, Ephemeral: true},
+		{Name: "FrodoKEM-976-SHAKE-Salted", N: 976, LogQ: 16, ExtractedBits: 3, Sec: 24, CDF: cdf976},
+		{Name: "FrodoKEM-976-AES-Salted", N: 976, LogQ: 16, ExtractedBits: 3, Sec: 24, CDF: cdf976, AES: true},
+		{Name: "FrodoKEM-1344-SHAKE-Salted", N: 1344, LogQ: 16, ExtractedBits: 4, Sec: 32, CDF: cdf1344},
+		{Name: "FrodoKEM-1344-AES-Salted", N: 1344, LogQ: 16, ExtractedBits: 4, Sec: 32, CDF: cdf1344, AES: true},
+		{Name: "eFrodoKEM-976-SHAKE", N: 976, LogQ: 16, ExtractedBits: 3, Sec: 24, CDF: cdf976, Ephemeral: true},
+		{Name: "eFrodoKEM-976-AES", N: 976, LogQ: 16, ExtractedBits: 3, Sec: 24, CDF: cdf976, AES: true, Ephemeral: true},
+		{Name: "eFrodoKEM-1344-SHAKE", N: 1344, LogQ: 16, ExtractedBits: 4, Sec: 32, CDF: cdf1344, Ephemeral: true},
+		{Name: "eFrodoKEM-1344-AES", N: 1344, LogQ: 16, ExtractedBits: 4, Sec: 32, CDF: cdf1344, AES: true, Ephemeral: true},
+	}
+
+	Templates       = []string{"frodo", "matrix", "noise", "util"}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/<file>.go from templates/<file>.templ.go
+func generatePackageFiles() {
+	for _, name := range Templates {
+		tl, err := template.ParseFiles("templates/" + name + ".templ.go")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, mode := range Instances {
+			buf := new(bytes.Buffer)
+			err := tl.Execute(buf, mode)
+			if err != nil {
+				panic(err)
+			}
+
+			// Formating output code
+			code, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(fmt.Sprintf("error formating code: %v", err))
+			}
+
+			res := string(code)
+			offset := strings.Index(res, TemplateWarning)
+			if offset == -1 {
+				panic("Missing template warning in " + name + ".templ.go")
+			}
+			err = os.MkdirAll(mode.Pkg(), 0o755)
+			if err != nil {
+				panic(err)
+			}
+			err = os.WriteFile(mode.Pkg()+"/"+name+".go", []byte(res[offset:]), 0o644)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/cloudflare/circl/internal/nist"
@@ -23,6 +24,21 @@ func TestPQCgenKATKem(t *testing.T) {
 		// Computed from:
 		// https://github.com/microsoft/PQCrypto-LWEKE/blob/66fc7744c3aae6acfc5fcc587ec7f2cdec48d216/KAT/PQCkemKAT_19888_shake.rsp
 		{"FrodoKEM-640-SHAKE", "604a10cfc871dfaed9cb5b057c644ab03b16852cea7f39bc7f9831513b5b1cfa"},
+
+		// The remaining hashes were computed with this implementation, and
+		// not checked against the reference KATs: those of the salted
+		// variants of the ISO proposal, and PQCkemKAT_{31296,43088}*.rsp of
+		// round 3 for eFrodoKEM. FrodoKEM-640-SHAKE above is generated from
+		// the same templates as eFrodoKEM, without salt.
+		// https://frodokem.org/files/FrodoKEM-ISO-20230314.pdf
+		{"FrodoKEM-976-SHAKE-Salted", "e29858b32dbd88f926e2a45d3d464812642e1df7cd45fcf9c3db4b4c683f45f0"},
+		{"FrodoKEM-976-AES-Salted", "d1bc19050269a99bfa84038ad466688428ebc98417ba35b48a06f3c05aefc9bd"},
+		{"FrodoKEM-1344-SHAKE-Salted", "05cdb3dad681f448da3b86eaa8404e6555593199b4311b6738fcfabf79f288dd"},
+		{"FrodoKEM-1344-AES-Salted", "1c866df7985ef3e3ca1402d046778d49c643ec584b8bf25b30baf7a34bcdde34"},
+		{"eFrodoKEM-976-SHAKE", "a3f8c7c34d71f67a04581eef1a149151f168d4bcf5b3f782745c892b73e456d9"},
+		{"eFrodoKEM-976-AES", "3f10ed8d86279016fad4b17f61cbaa77bc034bbb41a2a2790ded44547ff47693"},
+		{"eFrodoKEM-1344-SHAKE", "9d621971f7543d537f6596a5a1c632543175df54cde2c6fb8670e5c3458a64ee"},
+		{"eFrodoKEM-1344-AES", "536aa63d40ca596c936b2fba3bcdc848002134a2eb9ff3d49add0bd582a40b02"},
 	}
 	for _, kat := range kats {
 		t.Run(kat.name, func(t *testing.T) {
@@ -45,7 +61,9 @@ func testPQCgenKATKem(t *testing.T, name, expected string) {
 	}
 	f := sha256.New()
 	g := nist.NewDRBG(&seed)
-	mustWrite(t, f, "# %s\n\n", name)
+	// The KAT files of the ISO proposal name the salted variants without
+	// suffix.
+	mustWrite(t, f, "# %s\n\n", strings.TrimSuffix(name, "-Salted"))
 	for i := 0; i < 100; i++ {
 		g.Fill(seed[:])
 		mustWrite(t, f, "count = %d\n", i)
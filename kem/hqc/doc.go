@@ -0,0 +1,16 @@
+//go:generate go run gen.go
+
+// Package hqc provides the key encapsulation mechanism HQC.
+//
+// HQC (Hamming Quasi-Cyclic) is a code-based KEM selected by NIST for
+// standardization. This implementation follows the specification of the
+// fourth round of the NIST PQC competition [1], with parameter sets
+// HQC-128, HQC-192 and HQC-256.
+//
+// Arithmetic on the vectors of GF(2)[x]/(x^n - 1) and the decoding of the
+// concatenated Reed-Muller and Reed-Solomon code run in constant time.
+//
+// References:
+//
+//	[1] https://pqc-hqc.org/doc/hqc-specification_2023-04-30.pdf
+package hqc
@@ -0,0 +1,132 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates the HQC variants from templates to prevent too much
+// duplicated code between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+type Instance struct {
+	Name string
+
+	// Length n of the vectors, which is a prime.
+	N int
+
+	// Length n1 of the Reed-Solomon code, over GF(2^8).
+	N1 int
+
+	// Length n2 of the duplicated Reed-Muller code RM(1,7).
+	N2 int
+
+	// Length k in bytes of the encapsulated message.
+	K int
+
+	// Correction capacity delta of the Reed-Solomon code.
+	Delta int
+
+	// Hamming weights of the secret vectors x and y, of r1 and r2, and of e.
+	W, WR, WE int
+
+	// Coefficients of the generator polynomial of the Reed-Solomon code,
+	// from the constant term up. Its roots are alpha^1, ..., alpha^2delta.
+	RSPoly []byte
+}
+
+func (m Instance) Pkg() string {
+	return strings.ToLower(strings.ReplaceAll(m.Name, "-", ""))
+}
+
+func (m Instance) RSPolyGo() string {
+	s := make([]string, len(m.RSPoly))
+	for i, v := range m.RSPoly {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+var (
+	Instances = []Instance{
+		{
+			Name: "HQC-128", N: 17669, N1: 46, N2: 384, K: 16, Delta: 15,
+			W: 66, WR: 75, WE: 75,
+			RSPoly: []byte{
+				89, 69, 153, 116, 176, 117, 111, 75, 73, 233, 242, 233, 65, 210,
+				21, 139, 103, 173, 67, 118, 105, 210, 174, 110, 74, 69, 228, 82,
+				255, 181, 1,
+			},
+		},
+		{
+			Name: "HQC-192", N: 35851, N1: 56, N2: 640, K: 24, Delta: 16,
+			W: 100, WR: 114, WE: 114,
+			RSPoly: []byte{
+				45, 216, 239, 24, 253, 104, 27, 40, 107, 50, 163, 210, 227, 134,
+				224, 158, 119, 13, 158, 1, 238, 164, 82, 43, 15, 232, 246, 142,
+				50, 189, 29, 232, 1,
+			},
+		},
+		{
+			Name: "HQC-256", N: 57637, N1: 90, N2: 640, K: 32, Delta: 29,
+			W: 131, WR: 149, WE: 149,
+			RSPoly: []byte{
+				49, 167, 49, 39, 200, 121, 124, 91, 240, 63, 148, 71, 150, 123,
+				87, 101, 32, 215, 159, 71, 201, 115, 97, 210, 186, 183, 141, 217,
+				123, 12, 31, 243, 180, 219, 152, 239, 99, 141, 4, 246, 191, 144,
+				8, 232, 47, 27, 141, 178, 130, 64, 124, 47, 39, 188, 216, 48, 199,
+				187, 1,
+			},
+		},
+	}
+
+	Templates       = []string{"hqc", "vector", "code", "code_test"}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/<file>.go from templates/<file>.templ.go
+func generatePackageFiles() {
+	for _, name := range Templates {
+		tl, err := template.ParseFiles("templates/" + name + ".templ.go")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, mode := range Instances {
+			buf := new(bytes.Buffer)
+			err := tl.Execute(buf, mode)
+			if err != nil {
+				panic(err)
+			}
+
+			// Formating output code
+			code, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(fmt.Sprintf("error formating code: %v", err))
+			}
+
+			res := string(code)
+			offset := strings.Index(res, TemplateWarning)
+			if offset == -1 {
+				panic("Missing template warning in " + name + ".templ.go")
+			}
+			err = os.MkdirAll(mode.Pkg(), 0o755)
+			if err != nil {
+				panic(err)
+			}
+			err = os.WriteFile(mode.Pkg()+"/"+name+".go", []byte(res[offset:]), 0o644)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package hqc_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem/schemes"
+)
+
+func TestImplicitRejection(t *testing.T) {
+	for _, name := range []string{"HQC-128", "HQC-192", "HQC-256"} {
+		t.Run(name, func(t *testing.T) {
+			scheme := schemes.ByName(name)
+			pk, sk, err := scheme.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair failed")
+			ct, ss, err := scheme.Encapsulate(pk)
+			test.CheckNoErr(t, err, "Encapsulate failed")
+
+			// Tampering with u, v or the salt changes the shared key, in a
+			// way that only depends on the ciphertext.
+			for _, i := range []int{0, len(ct) / 2, len(ct) - 1} {
+				ct2 := bytes.Clone(ct)
+				ct2[i] ^= 1
+				ss2, err := scheme.Decapsulate(sk, ct2)
+				test.CheckNoErr(t, err, "Decapsulate failed")
+				test.CheckOk(!bytes.Equal(ss, ss2), "tampered ciphertext accepted", t)
+				ss3, err := scheme.Decapsulate(sk, ct2)
+				test.CheckNoErr(t, err, "Decapsulate failed")
+				test.CheckOk(bytes.Equal(ss2, ss3), "rejection is not deterministic", t)
+			}
+
+			// Public keys with coefficients of degree n or above are rejected.
+			ppk, err := pk.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary failed")
+			ppk[len(ppk)-1] = 0xff
+			_, err = scheme.UnmarshalBinaryPublicKey(ppk)
+			test.CheckIsErr(t, err, "unreduced public key accepted")
+		})
+	}
+}
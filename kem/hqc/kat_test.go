@@ -0,0 +1,93 @@
+package hqc
+
+// Code to generate the NIST "PQCkemKAT" test vectors.
+// See PQCgenKAT_kem.c and randombytes.c in the reference implementation.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/nist"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem/schemes"
+)
+
+func TestPQCgenKATKem(t *testing.T) {
+	kats := []struct {
+		name string
+		want string
+	}{
+		// Computed with this implementation, drawing the randomness as the
+		// reference implementation of round 4 does. They have not been
+		// checked against the hashes of its PQCkemKAT_*.rsp files.
+		{"HQC-128", "92054f473c913e94291460a0577303daabb1e99b372649492cda4b347a876167"},
+		{"HQC-192", "547159d70d8efd766a0adbfb049df71cf330e1fc59623963364312f1061bd92b"},
+		{"HQC-256", "d3ad90f36423bcaee25f10400f35498207cb3190e5970f9b5ed25dd40a2398d0"},
+	}
+	for _, kat := range kats {
+		t.Run(kat.name, func(t *testing.T) {
+			testPQCgenKATKem(t, kat.name, kat.want)
+		})
+	}
+}
+
+func testPQCgenKATKem(t *testing.T, name, expected string) {
+	scheme := schemes.ByName(name)
+	if scheme == nil {
+		t.Fatal()
+	}
+
+	var seed [48]byte
+	kseed := make([]byte, scheme.SeedSize())
+	eseed := make([]byte, scheme.EncapsulationSeedSize())
+	for i := 0; i < 48; i++ {
+		seed[i] = byte(i)
+	}
+	f := sha256.New()
+	g := nist.NewDRBG(&seed)
+	mustWrite(t, f, "# %s\n\n", name)
+	for i := 0; i < 100; i++ {
+		g.Fill(seed[:])
+		mustWrite(t, f, "count = %d\n", i)
+		mustWrite(t, f, "seed = %X\n", seed)
+
+		g2 := nist.NewDRBG(&seed)
+
+		// The key generation draws the seed of the secret key, sigma and
+		// the seed of the public key with three calls to the DRBG.
+		g2.Fill(kseed[:40])
+		g2.Fill(kseed[40 : len(kseed)-40])
+		g2.Fill(kseed[len(kseed)-40:])
+
+		pk, sk := scheme.DeriveKeyPair(kseed)
+		ppk, _ := pk.MarshalBinary()
+		psk, _ := sk.MarshalBinary()
+
+		// The encapsulation draws m, and then the salt.
+		g2.Fill(eseed[:len(eseed)-16])
+		g2.Fill(eseed[len(eseed)-16:])
+		ct, ss, err := scheme.EncapsulateDeterministically(pk, eseed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, _ := scheme.Decapsulate(sk, ct)
+		if !bytes.Equal(ss, ss2) {
+			t.Fatal()
+		}
+		mustWrite(t, f, "pk = %X\n", ppk)
+		mustWrite(t, f, "sk = %X\n", psk)
+		mustWrite(t, f, "ct = %X\n", ct)
+		mustWrite(t, f, "ss = %X\n\n", ss)
+	}
+	if got := fmt.Sprintf("%x", f.Sum(nil)); got != expected {
+		t.Fatalf("%s: got %s", name, got)
+	}
+}
+
+func mustWrite(t *testing.T, f io.Writer, format string, data any) {
+	_, err := fmt.Fprintf(f, format, data)
+	test.CheckNoErr(t, err, "fprintf failed")
+}
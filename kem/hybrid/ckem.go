@@ -20,7 +20,10 @@ type cScheme struct {
 	curve ecdh.Curve
 }
 
-var p256Kem = &cScheme{ecdh.P256()}
+var (
+	p256Kem = &cScheme{ecdh.P256()}
+	p384Kem = &cScheme{ecdh.P384()}
+)
 
 func (sch cScheme) Name() string {
 	switch sch.curve {
@@ -131,9 +134,21 @@ func (sch cScheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
 	}
 	h := xof.SHAKE256.New()
 	_, _ = h.Write(seed)
-	privKey, err := sch.curve.GenerateKey(h)
-	if err != nil {
-		panic(err)
+
+	// The scalar is sampled by rejection, as GenerateKey does not read its
+	// randomness deterministically.
+	buf := make([]byte, sch.PrivateKeySize())
+	var privKey *ecdh.PrivateKey
+	for {
+		_, _ = h.Read(buf)
+		if sch.curve == ecdh.P521() {
+			buf[0] &= 1
+		}
+		var err error
+		privKey, err = sch.curve.NewPrivateKey(buf)
+		if err == nil {
+			break
+		}
 	}
 	pubKey := privKey.PublicKey()
 
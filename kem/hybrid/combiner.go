@@ -0,0 +1,129 @@
+package hybrid
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"hash"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/kem"
+)
+
+// A Combiner derives the shared key of a hybrid KEM from the shared keys,
+// the ciphertexts and the public keys of its components.
+type Combiner interface {
+	// SharedKeySize returns the size of the shared key of the hybrid of
+	// the given schemes.
+	SharedKeySize(schemes []kem.Scheme) int
+
+	// Combine returns the shared key given the shared keys ss, the
+	// ciphertexts ct and the packed public keys pk of the components, in
+	// order.
+	Combine(ss, ct, pk [][]byte) []byte
+}
+
+// Combiners that do not use the public keys, which are then not computed.
+type publicKeyFreeCombiner interface {
+	Combiner
+	publicKeyFree()
+}
+
+// Concatenation returns the combiner whose shared key is the concatenation
+// of the shared keys of the components, as used by the hybrid KEMs of TLS.
+func Concatenation() Combiner { return concatenation{} }
+
+type concatenation struct{}
+
+func (concatenation) publicKeyFree() {}
+
+func (concatenation) SharedKeySize(schemes []kem.Scheme) int {
+	ret := 0
+	for _, s := range schemes {
+		ret += s.SharedKeySize()
+	}
+	return ret
+}
+
+func (concatenation) Combine(ss, _, _ [][]byte) []byte {
+	var ret []byte
+	for _, k := range ss {
+		ret = append(ret, k...)
+	}
+	return ret
+}
+
+// SHA3Combiner returns the combiner of X-Wing, generalized to any number of
+// components, whose shared key is
+//
+//	SHA3-256(ss_1 || ... || ss_n || ct_2 || pk_2 || ... || ct_n || pk_n || label).
+//
+// The ciphertext and public key of the first component are not bound, which
+// is only secure if it is ciphertext-binding, such as ML-KEM. Combining
+// ML-KEM-768 and X25519, with the label `\.//^\`, gives the combiner of
+// X-Wing.
+// https://datatracker.ietf.org/doc/draft-connolly-cfrg-xwing-kem/
+func SHA3Combiner(label []byte) Combiner {
+	return &bindingCombiner{
+		hash: func() hash.Hash {
+			h := sha3.New256()
+			return &h
+		},
+		suffix: append([]byte(nil), label...),
+	}
+}
+
+// HMACCombiner returns the combiner of the composite ML-KEM of the LAMPS
+// working group, generalized to any number of components, whose shared key
+// is the first 32 bytes of
+//
+//	HMAC-H(0, ss_1 || ... || ss_n || ct_2 || pk_2 || ... || ct_n || pk_n || domain),
+//
+// where domain is the DER encoding of the object identifier of the
+// composite algorithm. The first component, which is ML-KEM in composite
+// ML-KEM, must be ciphertext-binding. Composite ML-KEM uses SHA3Combiner
+// for the combinations that use SHA3-256 as KDF.
+// https://datatracker.ietf.org/doc/draft-ietf-lamps-pq-composite-kem/
+func HMACCombiner(h func() hash.Hash, domain []byte) Combiner {
+	return &bindingCombiner{
+		hash:   func() hash.Hash { return hmac.New(h, nil) },
+		suffix: append([]byte(nil), domain...),
+	}
+}
+
+// Hashes the shared keys, and the ciphertexts and public keys of all but
+// the first component, followed by suffix.
+type bindingCombiner struct {
+	hash   func() hash.Hash
+	suffix []byte
+}
+
+func (*bindingCombiner) SharedKeySize([]kem.Scheme) int { return 32 }
+
+func (c *bindingCombiner) Combine(ss, ct, pk [][]byte) []byte {
+	h := c.hash()
+	for _, k := range ss {
+		_, _ = h.Write(k)
+	}
+	for i := 1; i < len(ct); i++ {
+		_, _ = h.Write(ct[i])
+		_, _ = h.Write(pk[i])
+	}
+	_, _ = h.Write(c.suffix)
+	return h.Sum(nil)[:32]
+}
+
+// The combiner of the OpenSSH key exchange sntrup761x25519-sha512, whose
+// shared key is SHA-512(ss_1 || ss_2).
+type sha512Combiner struct{}
+
+func (sha512Combiner) publicKeyFree() {}
+
+func (sha512Combiner) SharedKeySize([]kem.Scheme) int { return sha512.Size }
+
+func (sha512Combiner) Combine(ss, _, _ [][]byte) []byte {
+	h := sha512.New()
+	for _, k := range ss {
+		_, _ = h.Write(k)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package hybrid
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup761"
+	"github.com/cloudflare/circl/kem/xwing"
+)
+
+func TestXWingCombiner(t *testing.T) {
+	// ML-KEM-768 and X25519 combined with the combiner of X-Wing has the
+	// same public keys and ciphertexts as X-Wing, and shared keys.
+	scheme := New("X-Wing", SHA3Combiner([]byte(`\.//^\`)), mlkem768.Scheme(), x25519Kem)
+	test.CheckOk(scheme.PublicKeySize() == xwing.PublicKeySize, "wrong public key size", t)
+	test.CheckOk(scheme.CiphertextSize() == xwing.CiphertextSize, "wrong ciphertext size", t)
+	test.CheckOk(scheme.SharedKeySize() == xwing.SharedKeySize, "wrong shared key size", t)
+
+	sk, pk, err := xwing.GenerateKeyPair(nil)
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	ppk, _ := pk.MarshalBinary()
+	hpk, err := scheme.UnmarshalBinaryPublicKey(ppk)
+	test.CheckNoErr(t, err, "UnmarshalBinaryPublicKey failed")
+
+	ct, ss, err := scheme.Encapsulate(hpk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+	ss2, err := xwing.Scheme().Decapsulate(sk, ct)
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ from X-Wing", t)
+}
+
+func TestHMACCombiner(t *testing.T) {
+	domain := []byte("domain")
+	scheme := New("ML-KEM-768-P256-HMAC-SHA256", HMACCombiner(sha256.New, domain),
+		mlkem768.Scheme(), p256Kem)
+	test.CheckOk(scheme.SharedKeySize() == 32, "wrong shared key size", t)
+
+	pk, sk, err := scheme.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	ct, ss, err := scheme.Encapsulate(pk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+
+	// Unmarshalling the private key discards the public keys of the
+	// components, which are recomputed.
+	psk, _ := sk.MarshalBinary()
+	sk, err = scheme.UnmarshalBinaryPrivateKey(psk)
+	test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey failed")
+	ss2, err := scheme.Decapsulate(sk, ct)
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+
+	// HMAC-SHA256(0, ss_M || ss_T || ct_T || pk_T || domain)
+	priv := sk.(*privateKey)
+	ctM, ctT := ct[:mlkem768.CiphertextSize], ct[mlkem768.CiphertextSize:]
+	ssM, _ := mlkem768.Scheme().Decapsulate(priv.keys[0], ctM)
+	ssT, _ := p256Kem.Decapsulate(priv.keys[1], ctT)
+	pkT, _ := priv.keys[1].Public().MarshalBinary()
+	mac := hmac.New(sha256.New, nil)
+	for _, b := range [][]byte{ssM, ssT, ctT, pkT, domain} {
+		_, _ = mac.Write(b)
+	}
+	test.CheckOk(bytes.Equal(ss, mac.Sum(nil)), "wrong shared key", t)
+}
+
+func TestSHA3Combiner(t *testing.T) {
+	label := []byte("label")
+	scheme := New("ML-KEM-768-sntrup761-X25519", SHA3Combiner(label),
+		mlkem768.Scheme(), sntrup761.Scheme(), x25519Kem)
+	test.CheckOk(scheme.SharedKeySize() == 32, "wrong shared key size", t)
+
+	pk, sk, err := scheme.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	ct, ss, err := scheme.Encapsulate(pk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+
+	// SHA3-256(ss_1 || ss_2 || ss_3 || ct_2 || pk_2 || ct_3 || pk_3 || label)
+	priv := sk.(*privateKey)
+	ct1 := ct[:mlkem768.CiphertextSize]
+	ct2 := ct[mlkem768.CiphertextSize : mlkem768.CiphertextSize+sntrup761.CiphertextSize]
+	ct3 := ct[mlkem768.CiphertextSize+sntrup761.CiphertextSize:]
+	ss1, _ := mlkem768.Scheme().Decapsulate(priv.keys[0], ct1)
+	ss2, _ := sntrup761.Scheme().Decapsulate(priv.keys[1], ct2)
+	ss3, _ := x25519Kem.Decapsulate(priv.keys[2], ct3)
+	pk2, _ := priv.keys[1].Public().MarshalBinary()
+	pk3, _ := priv.keys[2].Public().MarshalBinary()
+	h := sha3.New256()
+	for _, b := range [][]byte{ss1, ss2, ss3, ct2, pk2, ct3, pk3, label} {
+		_, _ = h.Write(b)
+	}
+	test.CheckOk(bytes.Equal(ss, h.Sum(nil)), "wrong shared key", t)
+}
+
+func TestNew(t *testing.T) {
+	schemes := []kem.Scheme{mlkem768.Scheme(), sntrup761.Scheme(), x25519Kem}
+	for _, c := range []Combiner{Concatenation(), SHA3Combiner(nil)} {
+		scheme := New("ML-KEM-768-sntrup761-X25519", c, schemes...)
+
+		size := 0
+		for _, s := range schemes {
+			size += s.PublicKeySize()
+		}
+		test.CheckOk(scheme.PublicKeySize() == size, "wrong public key size", t)
+
+		pk, sk := scheme.DeriveKeyPair(make([]byte, scheme.SeedSize()))
+		ct, ss, err := scheme.EncapsulateDeterministically(pk,
+			make([]byte, scheme.EncapsulationSeedSize()))
+		test.CheckNoErr(t, err, "Encapsulate failed")
+		test.CheckOk(len(ss) == scheme.SharedKeySize(), "wrong shared key size", t)
+		ss2, err := scheme.Decapsulate(sk, ct)
+		test.CheckNoErr(t, err, "Decapsulate failed")
+		test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+
+		// A modified ciphertext of X25519 changes the shared key of
+		// SHA3Combiner, but not its own shared key.
+		ct[len(ct)-1] ^= 1
+		ss3, err := scheme.Decapsulate(sk, ct)
+		test.CheckNoErr(t, err, "Decapsulate failed")
+		if _, ok := c.(concatenation); !ok {
+			test.CheckOk(!bytes.Equal(ss, ss3), "ciphertext is not bound", t)
+		}
+	}
+
+	err := test.CheckPanic(func() { New("single", Concatenation(), x25519Kem) })
+	test.CheckNoErr(t, err, "New should panic with a single scheme")
+}
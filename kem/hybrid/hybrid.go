@@ -1,4 +1,5 @@
-// Package hybrid defines several hybrid classical/quantum KEMs for use in TLS.
+// Package hybrid defines several hybrid classical/quantum KEMs for use in TLS,
+// and allows to combine arbitrary KEMs with New.
 //
 // Hybrid KEMs in TLS are created by simple concatenation
 // of shared secrets, cipher texts, public keys, etc.
@@ -7,7 +8,9 @@
 //	https://datatracker.ietf.org/doc/draft-ietf-tls-hybrid-design/
 //	https://nvlpubs.nist.gov/nistpubs/SpecialPublications/NIST.SP.800-56Cr2.pdf
 //
-// Note that this approach is not proven secure in broader context.
+// Note that this approach is not proven secure in broader context, where a
+// Combiner that binds the ciphertexts and public keys, such as SHA3Combiner
+// or HMACCombiner, should be used instead.
 //
 // For deriving a KEM keypair deterministically and encapsulating
 // deterministically, we expand a single seed to both using SHAKE256,
@@ -32,17 +35,37 @@ package hybrid
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/cloudflare/circl/internal/sha3"
 	"github.com/cloudflare/circl/kem"
 	"github.com/cloudflare/circl/kem/kyber/kyber1024"
 	"github.com/cloudflare/circl/kem/kyber/kyber512"
 	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem1024"
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup761"
 )
 
 var ErrUninitialized = errors.New("public or private key not initialized")
 
+// New returns the hybrid KEM with the given name of the schemes, whose
+// shared key is derived from those of the schemes by the combiner. Its
+// public keys, private keys and ciphertexts are the concatenations of those
+// of the schemes, in order.
+//
+// Panics if less than two schemes are given.
+func New(name string, combiner Combiner, schemes ...kem.Scheme) kem.Scheme {
+	if len(schemes) < 2 {
+		panic("hybrid: at least two schemes must be combined")
+	}
+	return &scheme{
+		name:     name,
+		kems:     append([]kem.Scheme(nil), schemes...),
+		combiner: combiner,
+	}
+}
+
 // Returns the hybrid KEM of Kyber512Draft00 and X25519.
 func Kyber512X25519() kem.Scheme { return kyber512X }
 
@@ -62,96 +85,127 @@ func P256Kyber768Draft00() kem.Scheme { return p256Kyber768Draft00 }
 // https://www.ietf.org/archive/id/draft-kwiatkowski-tls-ecdhe-mlkem-01.html
 func X25519MLKEM768() kem.Scheme { return xmlkem768 }
 
-var p256Kyber768Draft00 kem.Scheme = &scheme{
-	"P256Kyber768Draft00",
-	p256Kem,
-	kyber768.Scheme(),
-}
+// Returns the hybrid KEM of P-256 and ML-KEM-768, SecP256r1MLKEM768 with TLS
+// codepoint 0x11EB, whose shares and shared key are those of P-256 followed
+// by those of ML-KEM-768.
+// https://datatracker.ietf.org/doc/draft-ietf-tls-ecdhe-mlkem/
+func SecP256r1MLKEM768() kem.Scheme { return p256mlkem768 }
+
+// Returns the hybrid KEM of P-384 and ML-KEM-1024, SecP384r1MLKEM1024 with
+// TLS codepoint 0x11ED, whose shares and shared key are those of P-384
+// followed by those of ML-KEM-1024.
+// https://datatracker.ietf.org/doc/draft-ietf-tls-ecdhe-mlkem/
+func SecP384r1MLKEM1024() kem.Scheme { return p384mlkem1024 }
+
+// Returns the hybrid KEM of sntrup761 and X25519 of the OpenSSH key exchange
+// sntrup761x25519-sha512, whose shared key is the SHA-512 hash of the
+// concatenation of the shared keys. In the SSH protocol, it is encoded as a
+// string, not as an mpint.
+// https://datatracker.ietf.org/doc/draft-josefsson-ntruprime-ssh/
+func Sntrup761X25519SHA512() kem.Scheme { return sntrup761X }
+
+var p256Kyber768Draft00 kem.Scheme = New(
+	"P256Kyber768Draft00", Concatenation(), p256Kem, kyber768.Scheme(),
+)
 
-var kyber512X kem.Scheme = &scheme{
-	"Kyber512-X25519",
-	x25519Kem,
-	kyber512.Scheme(),
-}
+var kyber512X kem.Scheme = New(
+	"Kyber512-X25519", Concatenation(), x25519Kem, kyber512.Scheme(),
+)
 
-var kyber768X kem.Scheme = &scheme{
-	"Kyber768-X25519",
-	x25519Kem,
-	kyber768.Scheme(),
-}
+var kyber768X kem.Scheme = New(
+	"Kyber768-X25519", Concatenation(), x25519Kem, kyber768.Scheme(),
+)
 
-var kyber768X4 kem.Scheme = &scheme{
-	"Kyber768-X448",
-	x448Kem,
-	kyber768.Scheme(),
-}
+var kyber768X4 kem.Scheme = New(
+	"Kyber768-X448", Concatenation(), x448Kem, kyber768.Scheme(),
+)
 
-var kyber1024X kem.Scheme = &scheme{
-	"Kyber1024-X448",
-	x448Kem,
-	kyber1024.Scheme(),
-}
+var kyber1024X kem.Scheme = New(
+	"Kyber1024-X448", Concatenation(), x448Kem, kyber1024.Scheme(),
+)
 
-var xmlkem768 kem.Scheme = &scheme{
-	"X25519MLKEM768",
-	mlkem768.Scheme(),
-	x25519Kem,
-}
+var xmlkem768 kem.Scheme = New(
+	"X25519MLKEM768", Concatenation(), mlkem768.Scheme(), x25519Kem,
+)
+
+var p256mlkem768 kem.Scheme = New(
+	"SecP256r1MLKEM768", Concatenation(), p256Kem, mlkem768.Scheme(),
+)
+
+var p384mlkem1024 kem.Scheme = New(
+	"SecP384r1MLKEM1024", Concatenation(), p384Kem, mlkem1024.Scheme(),
+)
+
+var sntrup761X kem.Scheme = New(
+	"sntrup761x25519-sha512", sha512Combiner{}, sntrup761.Scheme(), x25519Kem,
+)
 
 // Public key of a hybrid KEM.
 type publicKey struct {
 	scheme *scheme
-	first  kem.PublicKey
-	second kem.PublicKey
+	keys   []kem.PublicKey
 }
 
 // Private key of a hybrid KEM.
 type privateKey struct {
 	scheme *scheme
-	first  kem.PrivateKey
-	second kem.PrivateKey
+	keys   []kem.PrivateKey
+
+	// Packed public keys of the components, which are computed lazily if
+	// the combiner uses them.
+	pkOnce sync.Once
+	pk     [][]byte
+	pkErr  error
 }
 
 // Scheme for a hybrid KEM.
 type scheme struct {
-	name   string
-	first  kem.Scheme
-	second kem.Scheme
+	name     string
+	kems     []kem.Scheme
+	combiner Combiner
 }
 
 func (sch *scheme) Name() string { return sch.name }
 func (sch *scheme) PublicKeySize() int {
-	return sch.first.PublicKeySize() + sch.second.PublicKeySize()
+	ret := 0
+	for _, k := range sch.kems {
+		ret += k.PublicKeySize()
+	}
+	return ret
 }
 
 func (sch *scheme) PrivateKeySize() int {
-	return sch.first.PrivateKeySize() + sch.second.PrivateKeySize()
+	ret := 0
+	for _, k := range sch.kems {
+		ret += k.PrivateKeySize()
+	}
+	return ret
 }
 
 func (sch *scheme) SeedSize() int {
-	first := sch.first.SeedSize()
-	second := sch.second.SeedSize()
-	ret := second
-	if first > second {
-		ret = first
+	ret := 0
+	for _, k := range sch.kems {
+		ret = max(ret, k.SeedSize())
 	}
 	return ret
 }
 
 func (sch *scheme) SharedKeySize() int {
-	return sch.first.SharedKeySize() + sch.second.SharedKeySize()
+	return sch.combiner.SharedKeySize(sch.kems)
 }
 
 func (sch *scheme) CiphertextSize() int {
-	return sch.first.CiphertextSize() + sch.second.CiphertextSize()
+	ret := 0
+	for _, k := range sch.kems {
+		ret += k.CiphertextSize()
+	}
+	return ret
 }
 
 func (sch *scheme) EncapsulationSeedSize() int {
-	first := sch.first.EncapsulationSeedSize()
-	second := sch.second.EncapsulationSeedSize()
-	ret := second
-	if first > second {
-		ret = first
+	ret := 0
+	for _, k := range sch.kems {
+		ret = max(ret, k.EncapsulationSeedSize())
 	}
 	return ret
 }
@@ -159,19 +213,43 @@ func (sch *scheme) EncapsulationSeedSize() int {
 func (sk *privateKey) Scheme() kem.Scheme { return sk.scheme }
 func (pk *publicKey) Scheme() kem.Scheme  { return pk.scheme }
 
+func (sk *privateKey) initialized() bool {
+	if len(sk.keys) == 0 {
+		return false
+	}
+	for _, k := range sk.keys {
+		if k == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (pk *publicKey) initialized() bool {
+	if len(pk.keys) == 0 {
+		return false
+	}
+	for _, k := range pk.keys {
+		if k == nil {
+			return false
+		}
+	}
+	return true
+}
+
 func (sk *privateKey) MarshalBinary() ([]byte, error) {
-	if sk.first == nil || sk.second == nil {
+	if !sk.initialized() {
 		return nil, ErrUninitialized
 	}
-	first, err := sk.first.MarshalBinary()
-	if err != nil {
-		return nil, err
+	var ret []byte
+	for _, k := range sk.keys {
+		buf, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, buf...)
 	}
-	second, err := sk.second.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	return append(first, second...), nil
+	return ret, nil
 }
 
 func (sk *privateKey) Equal(other kem.PrivateKey) bool {
@@ -179,17 +257,34 @@ func (sk *privateKey) Equal(other kem.PrivateKey) bool {
 	if !ok {
 		return false
 	}
-	if sk.first == nil && sk.second == nil && oth.first == nil && oth.second == nil {
+	if len(sk.keys) == 0 && len(oth.keys) == 0 {
 		return true
 	}
-	if sk.first == nil || sk.second == nil || oth.first == nil || oth.second == nil {
+	if !sk.initialized() || !oth.initialized() || len(sk.keys) != len(oth.keys) {
 		return false
 	}
-	return sk.first.Equal(oth.first) && sk.second.Equal(oth.second)
+	for i := range sk.keys {
+		if !sk.keys[i].Equal(oth.keys[i]) {
+			return false
+		}
+	}
+	return true
 }
 
 func (sk *privateKey) Public() kem.PublicKey {
-	return &publicKey{sk.scheme, sk.first.Public(), sk.second.Public()}
+	pk := &publicKey{sk.scheme, make([]kem.PublicKey, len(sk.keys))}
+	for i, k := range sk.keys {
+		pk.keys[i] = k.Public()
+	}
+	return pk
+}
+
+// Returns the packed public keys of the components.
+func (sk *privateKey) packedPublicKeys() ([][]byte, error) {
+	sk.pkOnce.Do(func() {
+		sk.pk, sk.pkErr = sk.Public().(*publicKey).packedKeys()
+	})
+	return sk.pk, sk.pkErr
 }
 
 func (pk *publicKey) Equal(other kem.PublicKey) bool {
@@ -197,41 +292,59 @@ func (pk *publicKey) Equal(other kem.PublicKey) bool {
 	if !ok {
 		return false
 	}
-	if pk.first == nil && pk.second == nil && oth.first == nil && oth.second == nil {
+	if len(pk.keys) == 0 && len(oth.keys) == 0 {
 		return true
 	}
-	if pk.first == nil || pk.second == nil || oth.first == nil || oth.second == nil {
+	if !pk.initialized() || !oth.initialized() || len(pk.keys) != len(oth.keys) {
 		return false
 	}
-	return pk.first.Equal(oth.first) && pk.second.Equal(oth.second)
+	for i := range pk.keys {
+		if !pk.keys[i].Equal(oth.keys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the packed public keys of the components.
+func (pk *publicKey) packedKeys() ([][]byte, error) {
+	ret := make([][]byte, len(pk.keys))
+	for i, k := range pk.keys {
+		buf, err := k.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = buf
+	}
+	return ret, nil
 }
 
 func (pk *publicKey) MarshalBinary() ([]byte, error) {
-	if pk.first == nil || pk.second == nil {
+	if !pk.initialized() {
 		return nil, ErrUninitialized
 	}
-	first, err := pk.first.MarshalBinary()
+	keys, err := pk.packedKeys()
 	if err != nil {
 		return nil, err
 	}
-	second, err := pk.second.MarshalBinary()
-	if err != nil {
-		return nil, err
+	var ret []byte
+	for _, buf := range keys {
+		ret = append(ret, buf...)
 	}
-	return append(first, second...), nil
+	return ret, nil
 }
 
 func (sch *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
-	pk1, sk1, err := sch.first.GenerateKeyPair()
-	if err != nil {
-		return nil, nil, err
-	}
-	pk2, sk2, err := sch.second.GenerateKeyPair()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return &publicKey{sch, pk1, pk2}, &privateKey{sch, sk1, sk2}, nil
+	pk := &publicKey{sch, make([]kem.PublicKey, len(sch.kems))}
+	sk := &privateKey{scheme: sch, keys: make([]kem.PrivateKey, len(sch.kems))}
+	for i, k := range sch.kems {
+		var err error
+		pk.keys[i], sk.keys[i], err = k.GenerateKeyPair()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return pk, sk, nil
 }
 
 func (sch *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
@@ -240,34 +353,54 @@ func (sch *scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
 	}
 	h := sha3.NewShake256()
 	_, _ = h.Write(seed)
-	first := make([]byte, sch.first.SeedSize())
-	second := make([]byte, sch.second.SeedSize())
-	_, _ = h.Read(first)
-	_, _ = h.Read(second)
 
-	pk1, sk1 := sch.first.DeriveKeyPair(first)
-	pk2, sk2 := sch.second.DeriveKeyPair(second)
+	pk := &publicKey{sch, make([]kem.PublicKey, len(sch.kems))}
+	sk := &privateKey{scheme: sch, keys: make([]kem.PrivateKey, len(sch.kems))}
+	for i, k := range sch.kems {
+		seed := make([]byte, k.SeedSize())
+		_, _ = h.Read(seed)
+		pk.keys[i], sk.keys[i] = k.DeriveKeyPair(seed)
+	}
+	return pk, sk
+}
 
-	return &publicKey{sch, pk1, pk2}, &privateKey{sch, sk1, sk2}
+// Returns the shared key from the shared keys and the ciphertexts of the
+// components, and their public keys, which are only computed if needed.
+func (sch *scheme) combine(
+	ss, ct [][]byte, pk func() ([][]byte, error),
+) ([]byte, error) {
+	var keys [][]byte
+	if _, ok := sch.combiner.(publicKeyFreeCombiner); !ok {
+		var err error
+		keys, err = pk()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sch.combiner.Combine(ss, ct, keys), nil
 }
 
 func (sch *scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
 	pub, ok := pk.(*publicKey)
-	if !ok {
+	if !ok || len(pub.keys) != len(sch.kems) {
 		return nil, nil, kem.ErrTypeMismatch
 	}
 
-	ct1, ss1, err := sch.first.Encapsulate(pub.first)
-	if err != nil {
-		return nil, nil, err
+	cts := make([][]byte, len(sch.kems))
+	sss := make([][]byte, len(sch.kems))
+	for i, k := range sch.kems {
+		cts[i], sss[i], err = k.Encapsulate(pub.keys[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		ct = append(ct, cts[i]...)
 	}
 
-	ct2, ss2, err := sch.second.Encapsulate(pub.second)
+	ss, err = sch.combine(sss, cts, pub.packedKeys)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	return append(ct1, ct2...), append(ss1, ss2...), nil
+	return ct, ss, nil
 }
 
 func (sch *scheme) EncapsulateDeterministically(
@@ -277,27 +410,31 @@ func (sch *scheme) EncapsulateDeterministically(
 		return nil, nil, kem.ErrSeedSize
 	}
 
-	h := sha3.NewShake256()
-	_, _ = h.Write(seed)
-	first := make([]byte, sch.first.EncapsulationSeedSize())
-	second := make([]byte, sch.second.EncapsulationSeedSize())
-	_, _ = h.Read(first)
-	_, _ = h.Read(second)
-
 	pub, ok := pk.(*publicKey)
-	if !ok {
+	if !ok || len(pub.keys) != len(sch.kems) {
 		return nil, nil, kem.ErrTypeMismatch
 	}
 
-	ct1, ss1, err := sch.first.EncapsulateDeterministically(pub.first, first)
-	if err != nil {
-		return nil, nil, err
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed)
+
+	cts := make([][]byte, len(sch.kems))
+	sss := make([][]byte, len(sch.kems))
+	for i, k := range sch.kems {
+		seed := make([]byte, k.EncapsulationSeedSize())
+		_, _ = h.Read(seed)
+		cts[i], sss[i], err = k.EncapsulateDeterministically(pub.keys[i], seed)
+		if err != nil {
+			return nil, nil, err
+		}
+		ct = append(ct, cts[i]...)
 	}
-	ct2, ss2, err := sch.second.EncapsulateDeterministically(pub.second, second)
+
+	ss, err = sch.combine(sss, cts, pub.packedKeys)
 	if err != nil {
 		return nil, nil, err
 	}
-	return append(ct1, ct2...), append(ss1, ss2...), nil
+	return ct, ss, nil
 }
 
 func (sch *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
@@ -306,50 +443,52 @@ func (sch *scheme) Decapsulate(sk kem.PrivateKey, ct []byte) ([]byte, error) {
 	}
 
 	priv, ok := sk.(*privateKey)
-	if !ok {
+	if !ok || len(priv.keys) != len(sch.kems) {
 		return nil, kem.ErrTypeMismatch
 	}
 
-	firstSize := sch.first.CiphertextSize()
-	ss1, err := sch.first.Decapsulate(priv.first, ct[:firstSize])
-	if err != nil {
-		return nil, err
-	}
-	ss2, err := sch.second.Decapsulate(priv.second, ct[firstSize:])
-	if err != nil {
-		return nil, err
+	cts := make([][]byte, len(sch.kems))
+	sss := make([][]byte, len(sch.kems))
+	for i, k := range sch.kems {
+		cts[i], ct = ct[:k.CiphertextSize()], ct[k.CiphertextSize():]
+		var err error
+		sss[i], err = k.Decapsulate(priv.keys[i], cts[i])
+		if err != nil {
+			return nil, err
+		}
 	}
-	return append(ss1, ss2...), nil
+
+	return sch.combine(sss, cts, priv.packedPublicKeys)
 }
 
 func (sch *scheme) UnmarshalBinaryPublicKey(buf []byte) (kem.PublicKey, error) {
 	if len(buf) != sch.PublicKeySize() {
 		return nil, kem.ErrPubKeySize
 	}
-	firstSize := sch.first.PublicKeySize()
-	pk1, err := sch.first.UnmarshalBinaryPublicKey(buf[:firstSize])
-	if err != nil {
-		return nil, err
-	}
-	pk2, err := sch.second.UnmarshalBinaryPublicKey(buf[firstSize:])
-	if err != nil {
-		return nil, err
+	pk := &publicKey{sch, make([]kem.PublicKey, len(sch.kems))}
+	for i, k := range sch.kems {
+		var err error
+		pk.keys[i], err = k.UnmarshalBinaryPublicKey(buf[:k.PublicKeySize()])
+		if err != nil {
+			return nil, err
+		}
+		buf = buf[k.PublicKeySize():]
 	}
-	return &publicKey{sch, pk1, pk2}, nil
+	return pk, nil
 }
 
 func (sch *scheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
 	if len(buf) != sch.PrivateKeySize() {
 		return nil, kem.ErrPrivKeySize
 	}
-	firstSize := sch.first.PrivateKeySize()
-	sk1, err := sch.first.UnmarshalBinaryPrivateKey(buf[:firstSize])
-	if err != nil {
-		return nil, err
-	}
-	sk2, err := sch.second.UnmarshalBinaryPrivateKey(buf[firstSize:])
-	if err != nil {
-		return nil, err
+	sk := &privateKey{scheme: sch, keys: make([]kem.PrivateKey, len(sch.kems))}
+	for i, k := range sch.kems {
+		var err error
+		sk.keys[i], err = k.UnmarshalBinaryPrivateKey(buf[:k.PrivateKeySize()])
+		if err != nil {
+			return nil, err
+		}
+		buf = buf[k.PrivateKeySize():]
 	}
-	return &privateKey{sch, sk1, sk2}, nil
+	return sk, nil
 }
@@ -0,0 +1,153 @@
+package hybrid
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem1024"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup761"
+)
+
+func TestSntrup761X25519SHA512(t *testing.T) {
+	scheme := Sntrup761X25519SHA512()
+	test.CheckOk(scheme.PublicKeySize() == sntrup761.PublicKeySize+32, "wrong public key size", t)
+	test.CheckOk(scheme.CiphertextSize() == sntrup761.CiphertextSize+32, "wrong ciphertext size", t)
+	test.CheckOk(scheme.SharedKeySize() == sha512.Size, "wrong shared key size", t)
+
+	pk, sk, err := scheme.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	ct, ss, err := scheme.Encapsulate(pk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+
+	// The shared key is SHA-512(K_sntrup761 || K_X25519), and the
+	// ciphertext is the concatenation of the ciphertext of sntrup761 and of
+	// the ephemeral X25519 public key.
+	priv := sk.(*privateKey)
+	ss1, err := sntrup761.Scheme().Decapsulate(priv.keys[0], ct[:sntrup761.CiphertextSize])
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	ss2, err := x25519Kem.Decapsulate(priv.keys[1], ct[sntrup761.CiphertextSize:])
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	want := sha512.Sum512(append(ss1, ss2...))
+	test.CheckOk(bytes.Equal(ss, want[:]), "wrong shared key", t)
+
+	ss3, err := scheme.Decapsulate(sk, ct)
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	test.CheckOk(bytes.Equal(ss, ss3), "shared keys differ", t)
+}
+
+// TestSntrup761X25519SHA512OpenSSH checks the transcript of a key exchange
+// with the OpenSSH 9.2 client: the client share it sent, the reply of the
+// server, and the shared key it accepted.
+func TestSntrup761X25519SHA512OpenSSH(t *testing.T) {
+	f, err := os.Open("testdata/sntrup761x25519-sha512-openssh.txt")
+	test.CheckNoErr(t, err, "Open failed")
+	defer f.Close()
+	v := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<16)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, " = ")
+		test.CheckOk(ok, "malformed line", t)
+		v[key] = value
+	}
+	test.CheckNoErr(t, sc.Err(), "Scan failed")
+	qc, err := hex.DecodeString(v["Q_C"])
+	test.CheckNoErr(t, err, "bad Q_C")
+	qs, err := hex.DecodeString(v["Q_S"])
+	test.CheckNoErr(t, err, "bad Q_S")
+	k, err := hex.DecodeString(v["K"])
+	test.CheckNoErr(t, err, "bad K")
+	scheme := Sntrup761X25519SHA512()
+
+	// The client generates the sntrup761 key pair, then the X25519 one.
+	rnd := sha3.NewShake256()
+	_, _ = rnd.Write([]byte(v["label"]))
+	_, ntruSk, err := sntrup761.GenerateKeyPair(&rnd)
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	psk, _ := ntruSk.MarshalBinary()
+	psk = append(psk, make([]byte, 32)...)
+	_, _ = rnd.Read(psk[sntrup761.PrivateKeySize:])
+	sk, err := scheme.UnmarshalBinaryPrivateKey(psk)
+	test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey failed")
+	pqc, _ := sk.Public().MarshalBinary()
+	test.CheckOk(bytes.Equal(pqc, qc), "public key differs from Q_C", t)
+
+	ss, err := scheme.Decapsulate(sk, qs)
+	test.CheckNoErr(t, err, "Decapsulate failed")
+	test.CheckOk(bytes.Equal(ss, k), "Decapsulate: wrong shared key", t)
+
+	pk, err := scheme.UnmarshalBinaryPublicKey(qc)
+	test.CheckNoErr(t, err, "UnmarshalBinaryPublicKey failed")
+	seed := make([]byte, scheme.EncapsulationSeedSize())
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	ct, ss, err := scheme.EncapsulateDeterministically(pk, seed)
+	test.CheckNoErr(t, err, "EncapsulateDeterministically failed")
+	test.CheckOk(bytes.Equal(ct, qs), "ciphertext differs from Q_S", t)
+	test.CheckOk(bytes.Equal(ss, k), "Encapsulate: wrong shared key", t)
+}
+
+func TestSecPMLKEM(t *testing.T) {
+	for _, tc := range []struct {
+		scheme       kem.Scheme
+		ec, mlkem    kem.Scheme
+		ecPubKeySize int
+	}{
+		{SecP256r1MLKEM768(), p256Kem, mlkem768.Scheme(), 65},
+		{SecP384r1MLKEM1024(), p384Kem, mlkem1024.Scheme(), 97},
+	} {
+		t.Run(tc.scheme.Name(), func(t *testing.T) {
+			scheme := tc.scheme
+			test.CheckOk(scheme.PublicKeySize() == tc.ecPubKeySize+tc.mlkem.PublicKeySize(), "wrong public key size", t)
+			test.CheckOk(scheme.CiphertextSize() == tc.ecPubKeySize+tc.mlkem.CiphertextSize(), "wrong ciphertext size", t)
+
+			pk, sk, err := scheme.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair failed")
+			ct, ss, err := scheme.Encapsulate(pk)
+			test.CheckNoErr(t, err, "Encapsulate failed")
+
+			// The ECDH share comes first, as an uncompressed point, and
+			// so does its shared secret.
+			ppk, _ := pk.MarshalBinary()
+			test.CheckOk(ppk[0] == 4 && ct[0] == 4, "ECDH share is not first", t)
+
+			priv := sk.(*privateKey)
+			ss1, err := tc.ec.Decapsulate(priv.keys[0], ct[:tc.ecPubKeySize])
+			test.CheckNoErr(t, err, "Decapsulate failed")
+			ss2, err := tc.mlkem.Decapsulate(priv.keys[1], ct[tc.ecPubKeySize:])
+			test.CheckNoErr(t, err, "Decapsulate failed")
+			test.CheckOk(bytes.Equal(ss, append(ss1, ss2...)), "wrong shared key", t)
+		})
+	}
+}
+
+func TestDeriveKeyPairDeterministic(t *testing.T) {
+	for _, scheme := range []kem.Scheme{
+		P256Kyber768Draft00(),
+		SecP256r1MLKEM768(),
+		SecP384r1MLKEM1024(),
+	} {
+		t.Run(scheme.Name(), func(t *testing.T) {
+			seed := make([]byte, scheme.SeedSize())
+			pk, _ := scheme.DeriveKeyPair(seed)
+			for i := 0; i < 16; i++ {
+				pk2, _ := scheme.DeriveKeyPair(seed)
+				test.CheckOk(pk.Equal(pk2), "DeriveKeyPair is not deterministic", t)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/asn1"
 	"fmt"
 	"go/format"
 	"io/ioutil"
@@ -17,6 +18,7 @@ import (
 
 type Instance struct {
 	Name string
+	Oid  asn1.ObjectIdentifier
 }
 
 func (m Instance) KemName() string {
@@ -37,6 +39,18 @@ func (m Instance) PkePkg() string {
 	return strings.ReplaceAll(m.Pkg(), "mlkem", "kyber")
 }
 
+// https://csrc.nist.gov/Projects/computer-security-objects-register/algorithm-registration
+func (m Instance) OidGo() string {
+	ret := "asn1.ObjectIdentifier{"
+	for i, b := range m.Oid {
+		if i > 0 {
+			ret += ", "
+		}
+		ret += fmt.Sprintf("%d", b)
+	}
+	return ret + "}"
+}
+
 func (m Instance) Pkg() string {
 	return strings.ToLower(strings.ReplaceAll(m.Name, "-", ""))
 }
@@ -53,9 +67,18 @@ var (
 		{Name: "Kyber512"},
 		{Name: "Kyber768"},
 		{Name: "Kyber1024"},
-		{Name: "ML-KEM-512"},
-		{Name: "ML-KEM-768"},
-		{Name: "ML-KEM-1024"},
+		{
+			Name: "ML-KEM-512",
+			Oid:  asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1},
+		},
+		{
+			Name: "ML-KEM-768",
+			Oid:  asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2},
+		},
+		{
+			Name: "ML-KEM-1024",
+			Oid:  asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3},
+		},
 	}
 	TemplateWarning = "// Code generated from"
 )
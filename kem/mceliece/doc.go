@@ -0,0 +1,20 @@
+//go:generate go run gen.go
+
+// Package mceliece provides the key encapsulation mechanism Classic McEliece.
+//
+// This implements the parameter sets mceliece348864, mceliece460896,
+// mceliece6688128 and mceliece6960119 of the fourth round of the NIST PQC
+// competition [1], and their "f" variants, whose key generation is faster.
+// Ciphertexts do not include the plaintext confirmation of earlier rounds.
+//
+// Public keys are very large, from 255 KiB to 1 MiB: they are kept in their
+// packed form, and the public key of a private key is computed lazily, as it
+// is not part of the private key.
+//
+// This implementation follows the reference implementation [2].
+//
+// References:
+//
+//	[1] https://classic.mceliece.org/mceliece-spec-20221023.pdf
+//	[2] https://classic.mceliece.org/
+package mceliece
@@ -0,0 +1,150 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates the Classic McEliece variants from templates to prevent too
+// much duplicated code between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// A term c y^d of the polynomial F(y) that defines GF((2^m)^t).
+type Term struct {
+	Deg  int
+	Coef int
+}
+
+type Instance struct {
+	Name string
+
+	// Degree of the field GF(2^m).
+	M int
+
+	// Length n of the code.
+	N int
+
+	// Number t of errors, and degree of the Goppa polynomial.
+	T int
+
+	// Exponents of the terms of degree below m of f(z), where
+	// GF(2^m) = GF(2)[z]/f(z).
+	GFTerms []int
+
+	// Terms of degree below t of F(y), where GF((2^m)^t) = GF(2^m)[y]/F(y).
+	FTerms []Term
+
+	// Whether the public key is in semi-systematic form, which is the case
+	// for the "f" variants.
+	Semi bool
+}
+
+func (m Instance) Pkg() string {
+	return strings.ToLower(m.Name)
+}
+
+// GFReduce returns the expression of f(z) - z^m applied to t.
+func (m Instance) GFReduce() string {
+	s := make([]string, len(m.GFTerms))
+	for i, e := range m.GFTerms {
+		if e == 0 {
+			s[i] = "t"
+		} else {
+			s[i] = fmt.Sprintf("t<<%d", e)
+		}
+	}
+	return strings.Join(s, " ^ ")
+}
+
+func (m Instance) FPoly() string {
+	s := []string{fmt.Sprintf("y^%d", m.T)}
+	for _, t := range m.FTerms {
+		var c string
+		if t.Coef != 1 {
+			c = "z"
+		}
+		switch t.Deg {
+		case 0:
+			if c == "" {
+				c = "1"
+			}
+			s = append(s, c)
+		case 1:
+			s = append(s, c+"y")
+		default:
+			s = append(s, fmt.Sprintf("%sy^%d", c, t.Deg))
+		}
+	}
+	return strings.Join(s, " + ")
+}
+
+var (
+	gf12 = []int{3, 0}
+	gf13 = []int{4, 3, 1, 0}
+
+	f64  = []Term{{3, 1}, {1, 1}, {0, 2}}
+	f96  = []Term{{10, 1}, {9, 1}, {6, 1}, {0, 1}}
+	f119 = []Term{{8, 1}, {0, 1}}
+	f128 = []Term{{7, 1}, {2, 1}, {1, 1}, {0, 1}}
+
+	Instances = []Instance{
+		{Name: "mceliece348864", M: 12, N: 3488, T: 64, GFTerms: gf12, FTerms: f64},
+		{Name: "mceliece348864f", M: 12, N: 3488, T: 64, GFTerms: gf12, FTerms: f64, Semi: true},
+		{Name: "mceliece460896", M: 13, N: 4608, T: 96, GFTerms: gf13, FTerms: f96},
+		{Name: "mceliece460896f", M: 13, N: 4608, T: 96, GFTerms: gf13, FTerms: f96, Semi: true},
+		{Name: "mceliece6688128", M: 13, N: 6688, T: 128, GFTerms: gf13, FTerms: f128},
+		{Name: "mceliece6688128f", M: 13, N: 6688, T: 128, GFTerms: gf13, FTerms: f128, Semi: true},
+		{Name: "mceliece6960119", M: 13, N: 6960, T: 119, GFTerms: gf13, FTerms: f119},
+		{Name: "mceliece6960119f", M: 13, N: 6960, T: 119, GFTerms: gf13, FTerms: f119, Semi: true},
+	}
+
+	Templates       = []string{"mceliece", "gf", "pk", "decode"}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/<file>.go from templates/<file>.templ.go
+func generatePackageFiles() {
+	for _, name := range Templates {
+		tl, err := template.ParseFiles("templates/" + name + ".templ.go")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, mode := range Instances {
+			buf := new(bytes.Buffer)
+			err := tl.Execute(buf, mode)
+			if err != nil {
+				panic(err)
+			}
+
+			// Formating output code
+			code, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(fmt.Sprintf("error formating code: %v", err))
+			}
+
+			res := string(code)
+			offset := strings.Index(res, TemplateWarning)
+			if offset == -1 {
+				panic("Missing template warning in " + name + ".templ.go")
+			}
+			err = os.MkdirAll(mode.Pkg(), 0o755)
+			if err != nil {
+				panic(err)
+			}
+			err = os.WriteFile(mode.Pkg()+"/"+name+".go", []byte(res[offset:]), 0o644)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package internal
+
+// Returns min(a, b) in constant time.
+func min32(a, b int32) int32 {
+	c := int32((int64(b) - int64(a)) >> 63)
+	return a ^ ((a ^ b) & c)
+}
+
+// cbRecursion computes the (2w-1)n/2 control bits of a Beneš network that
+// applies the permutation pi of {0, ..., n-1}, with n = 2^w, and writes them
+// at the bit positions pos, pos+step, ... of out, which must be zero.
+//
+// temp must have space for 2n elements. This follows the algorithm of
+// "Verified fast formulas for control bits for permutation networks" by
+// Daniel J. Bernstein, and runs in constant time.
+func cbRecursion(out []byte, pos, step int, pi []int16, w, n int, temp []int32) {
+	if w == 1 {
+		out[pos>>3] ^= byte(pi[0] << (pos & 7))
+		return
+	}
+
+	A := temp[:n]
+	B := temp[n : 2*n]
+
+	for x := 0; x < n; x++ {
+		A[x] = (int32(pi[x]^1) << 16) | int32(pi[x^1])
+	}
+	Int32Sort(A) // A = (id<<16)+pibar
+
+	for x := 0; x < n; x++ {
+		px := A[x] & 0xffff
+		cx := min32(px, int32(x))
+		B[x] = (px << 16) | cx
+	}
+	// B = (p<<16)+c
+
+	for x := 0; x < n; x++ {
+		A[x] = (A[x] << 16) | int32(x) // A = (pibar<<16)+id
+	}
+	Int32Sort(A) // A = (id<<16)+pibar^-1
+
+	for x := 0; x < n; x++ {
+		A[x] = (A[x] << 16) + (B[x] >> 16) // A = (pibar^-1<<16)+pibar
+	}
+	Int32Sort(A) // A = (id<<16)+pibar^2
+
+	if w <= 10 {
+		for x := 0; x < n; x++ {
+			B[x] = ((A[x] & 0xffff) << 10) | (B[x] & 0x3ff)
+		}
+
+		for i := 1; i < w-1; i++ {
+			// B = (p<<10)+c
+
+			for x := 0; x < n; x++ {
+				A[x] = ((B[x] &^ 0x3ff) << 6) | int32(x) // A = (p<<16)+id
+			}
+			Int32Sort(A) // A = (id<<16)+p^-1
+
+			for x := 0; x < n; x++ {
+				A[x] = (A[x] << 20) | B[x] // A = (p^-1<<20)+(p<<10)+c
+			}
+			Int32Sort(A) // A = (id<<20)+(pp<<10)+cp
+
+			for x := 0; x < n; x++ {
+				ppcpx := A[x] & 0xfffff
+				ppcx := (A[x] & 0xffc00) | (B[x] & 0x3ff)
+				B[x] = min32(ppcx, ppcpx)
+			}
+		}
+		for x := 0; x < n; x++ {
+			B[x] &= 0x3ff
+		}
+	} else {
+		for x := 0; x < n; x++ {
+			B[x] = (A[x] << 16) | (B[x] & 0xffff)
+		}
+
+		for i := 1; i < w-1; i++ {
+			// B = (p<<16)+c
+
+			for x := 0; x < n; x++ {
+				A[x] = (B[x] &^ 0xffff) | int32(x)
+			}
+			Int32Sort(A) // A = (id<<16)+p^-1
+
+			for x := 0; x < n; x++ {
+				A[x] = (A[x] << 16) | (B[x] & 0xffff) // A = (p^-1<<16)+c
+			}
+
+			if i < w-2 {
+				for x := 0; x < n; x++ {
+					B[x] = (A[x] &^ 0xffff) | (B[x] >> 16) // B = (p^-1<<16)+p
+				}
+				Int32Sort(B) // B = (id<<16)+p^-2
+				for x := 0; x < n; x++ {
+					B[x] = (B[x] << 16) | (A[x] & 0xffff) // B = (p^-2<<16)+c
+				}
+			}
+
+			Int32Sort(A) // A = (id<<16)+cp
+			for x := 0; x < n; x++ {
+				cpx := (B[x] &^ 0xffff) | (A[x] & 0xffff)
+				B[x] = min32(B[x], cpx)
+			}
+		}
+		for x := 0; x < n; x++ {
+			B[x] &= 0xffff
+		}
+	}
+
+	for x := 0; x < n; x++ {
+		A[x] = (int32(pi[x]) << 16) + int32(x)
+	}
+	Int32Sort(A) // A = (id<<16)+pi^-1
+
+	for j := 0; j < n/2; j++ {
+		x := 2 * j
+		fj := B[x] & 1      // f[j]
+		Fx := int32(x) + fj // F[x]
+		Fx1 := Fx ^ 1       // F[x+1]
+
+		out[pos>>3] ^= byte(fj << (pos & 7))
+		pos += step
+
+		B[x] = (A[x] << 16) | Fx
+		B[x+1] = (A[x+1] << 16) | Fx1
+	}
+	// B = (pi^-1<<16)+F
+
+	Int32Sort(B) // B = (id<<16)+F(pi)
+
+	pos += (2*w - 3) * step * (n / 2)
+
+	for k := 0; k < n/2; k++ {
+		y := 2 * k
+		lk := B[y] & 1      // l[k]
+		Ly := int32(y) + lk // L[y]
+		Ly1 := Ly ^ 1       // L[y+1]
+
+		out[pos>>3] ^= byte(lk << (pos & 7))
+		pos += step
+
+		A[y] = (Ly << 16) | (B[y] & 0xffff)
+		A[y+1] = (Ly1 << 16) | (B[y+1] & 0xffff)
+	}
+	// A = (L<<16)+F(pi)
+
+	Int32Sort(A) // A = (id<<16)+F(pi(L)) = (id<<16)+M
+
+	pos -= (2*w - 2) * step * (n / 2)
+
+	q := make([]int16, n)
+	for j := 0; j < n/2; j++ {
+		q[j] = int16((A[2*j] & 0xffff) >> 1)
+		q[j+n/2] = int16((A[2*j+1] & 0xffff) >> 1)
+	}
+
+	cbRecursion(out, pos, step*2, q[:n/2], w-1, n/2, temp)
+	cbRecursion(out, pos+step, step*2, q[n/2:], w-1, n/2, temp)
+}
+
+// ControlBitsFromPermutation returns the (2w-1)n/2 control bits of a Beneš
+// network that applies the permutation pi of {0, ..., n-1}, with n = 2^w, in
+// the layout expected by ApplyNetwork.
+func ControlBitsFromPermutation(pi []int16, w int) []byte {
+	n := 1 << w
+	out := make([]byte, ((2*w-1)*n/2+7)/8)
+	temp := make([]int32, 2*n)
+	cbRecursion(out, 0, 1, pi, w, n, temp)
+	return out
+}
+
+// layer applies the swaps of one layer of the network, between the
+// positions at distance 2^s, to p.
+func layer(p []uint16, cb []byte, s int) {
+	stride := 1 << s
+	index := 0
+	for i := 0; i < len(p); i += stride * 2 {
+		for j := 0; j < stride; j++ {
+			d := p[i+j] ^ p[i+j+stride]
+			m := -uint16((cb[index>>3] >> (index & 7)) & 1)
+			d &= m
+			p[i+j] ^= d
+			p[i+j+stride] ^= d
+			index++
+		}
+	}
+}
+
+// ApplyNetwork permutes p, of length n = 2^w, with the Beneš network given
+// by the control bits cb: if p is the identity on input, it is pi on output,
+// where cb = ControlBitsFromPermutation(pi, w).
+func ApplyNetwork(p []uint16, cb []byte, w int) {
+	n := 1 << w
+	for i := 0; i < w; i++ {
+		layer(p, cb, i)
+		cb = cb[n>>4:]
+	}
+	for i := w - 2; i >= 0; i-- {
+		layer(p, cb, i)
+		cb = cb[n>>4:]
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+	for _, n := range []int{0, 1, 2, 3, 7, 64, 1000, 4096} {
+		x := make([]int32, n)
+		y := make([]uint64, n)
+		for i := range x {
+			x[i] = r.Int31() - (1 << 30)
+			y[i] = r.Uint64()
+		}
+		Int32Sort(x)
+		Uint64Sort(y)
+		if !sort.SliceIsSorted(x, func(i, j int) bool { return x[i] < x[j] }) ||
+			!sort.SliceIsSorted(y, func(i, j int) bool { return y[i] < y[j] }) {
+			t.Fatalf("not sorted: %d", n)
+		}
+	}
+}
+
+func TestControlBits(t *testing.T) {
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+	for w := 4; w <= 13; w++ {
+		n := 1 << w
+		pi := make([]int16, n)
+		for i, v := range r.Perm(n) {
+			pi[i] = int16(v)
+		}
+		cb := ControlBitsFromPermutation(pi, w)
+
+		p := make([]uint16, n)
+		for i := range p {
+			p[i] = uint16(i)
+		}
+		ApplyNetwork(p, cb, w)
+		for i := range p {
+			if p[i] != uint16(pi[i]) {
+				t.Fatalf("w=%d: wrong permutation at %d", w, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package internal contains the parts of Classic McEliece that do not depend
+// on the parameter set: constant-time sorting, and the computation of the
+// control bits of a Beneš network.
+package internal
+
+import "math/bits"
+
+// Int32Sort sorts x in increasing order in constant time, with the sorting
+// network of djbsort.
+func Int32Sort(x []int32) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				int32MinMax(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						int32MinMax(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
+
+// Sets a, b to min(a, b), max(a, b) in constant time.
+func int32MinMax(a, b *int32) {
+	ab := *a ^ *b
+	c := int32((int64(*b) - int64(*a)) >> 63)
+	c &= ab
+	*a ^= c
+	*b ^= c
+}
+
+// Sets a, b to min(a, b), max(a, b) in constant time.
+func uint64MinMax(a, b *uint64) {
+	_, borrow := bits.Sub64(*b, *a, 0)
+	c := -borrow & (*a ^ *b)
+	*a ^= c
+	*b ^= c
+}
+
+// Uint64Sort sorts x in increasing order in constant time, with the sorting
+// network of djbsort.
+func Uint64Sort(x []uint64) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				uint64MinMax(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						uint64MinMax(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package mceliece
+
+// Code to generate the NIST "PQCkemKAT" test vectors.
+// See PQCgenKAT_kem.c and randombytes.c in the reference implementation.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/nist"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem/schemes"
+)
+
+func TestPQCgenKATKem(t *testing.T) {
+	kats := []struct {
+		name  string
+		first string // hash of the first entry, checked in short mode
+		want  string // hash of the first three entries
+	}{
+		// Computed with this implementation, drawing the error vectors from
+		// the DRBG as the reference implementation does. They have not been
+		// checked against the official KAT files.
+		{
+			"mceliece348864",
+			"0df9937fe7e25c6848b1170517ff48e0c2c4bbec487bb1e7c6b9c0baf19a6435",
+			"31eee9be66be09dfc24666311e7a8a98503c29da521ea8572c90dd448e001ad2",
+		},
+		{
+			"mceliece348864f",
+			"a55a0321f54a17a06b2841b7f969305e4f45263d3ba1f60ddbdd0bf078651d12",
+			"cd332ae64d1f8b36d23d585e0ab81381cc6995ff1ef06ceb78264218266b20aa",
+		},
+		{
+			"mceliece460896",
+			"efafcf1482052e6469c4903b5c4ea559d4e3e34fd742de3b1069218183d37622",
+			"da1d99098dae11baf666f94e0e13b3041ed352a98f64d9f1059617c8f0bbb3c7",
+		},
+		{
+			"mceliece460896f",
+			"443ac499e734b508e247508e0afe4827e4bd8d9fe1797941f1c8624540b42b21",
+			"c88d3fb885e9ea3d98c4bc8dd67d5f0c25fcef4da2ed6b39848dee162c1b3b36",
+		},
+		{
+			"mceliece6688128",
+			"3e90ca7f79c284362aa5d624641f8b5a275b8f49cd579f2d9970b0dc19f1dc0e",
+			"18d4d8c176da9f55854e1b769b8c7557f5165ea3eed84495ca9a9176dbf22be6",
+		},
+		{
+			"mceliece6688128f",
+			"96a8982dd15f7f506b10b0472c1e4b827c91be50e7908f6344e3f52b19f69d1a",
+			"8071b8d411bf4d17b5092e0e945f445f5e4b238fd77fd56457d070882785b693",
+		},
+		{
+			"mceliece6960119",
+			"7ce61f179ab668199e348bc07ef9ae8821356c695861a9195bfdb79aafe202bf",
+			"d2bf3cdde003492b0e7e5f106d0dac84cf6e9130f090f7daef2cb0151d5e6506",
+		},
+		{
+			"mceliece6960119f",
+			"f6eb21d3fe217eea7dabf8ce72086b6bbb4bbfbdbe294ac8519589ae8db1fec7",
+			"e0cb210d1893289f97483a96ee8efd962c746bff33128a4aebadc1d2101a3534",
+		},
+	}
+	for _, kat := range kats {
+		t.Run(kat.name, func(t *testing.T) {
+			testPQCgenKATKem(t, kat.name, kat.first, kat.want)
+		})
+	}
+}
+
+func testPQCgenKATKem(t *testing.T, name, first, expected string) {
+	scheme := schemes.ByName(name)
+	if scheme == nil {
+		t.Fatal()
+	}
+
+	// The key generation is slow, so only the first few entries are
+	// computed.
+	n := 3
+	if testing.Short() {
+		n = 1
+	}
+
+	var seed [48]byte
+	kseed := make([]byte, scheme.SeedSize())
+	for i := 0; i < 48; i++ {
+		seed[i] = byte(i)
+	}
+	f := sha256.New()
+	g := nist.NewDRBG(&seed)
+	mustWrite(t, f, "# kem/%s\n\n", name)
+	for i := 0; i < n; i++ {
+		g.Fill(seed[:])
+		mustWrite(t, f, "count = %d\n", i)
+		mustWrite(t, f, "seed = %X\n", seed)
+
+		g2 := nist.NewDRBG(&seed)
+		g2.Fill(kseed)
+		pk, sk := scheme.DeriveKeyPair(kseed)
+		ppk, _ := pk.MarshalBinary()
+		psk, _ := sk.MarshalBinary()
+
+		// Encapsulation reads the DRBG until a valid error vector is found.
+		ct, ss, err := pk.(encapsulator).Encapsulate(drbgReader{&g2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss2, _ := scheme.Decapsulate(sk, ct)
+		if !bytes.Equal(ss, ss2) {
+			t.Fatal()
+		}
+		mustWrite(t, f, "pk = %X\n", ppk)
+		mustWrite(t, f, "sk = %X\n", psk)
+		mustWrite(t, f, "ct = %X\n", ct)
+		mustWrite(t, f, "ss = %X\n\n", ss)
+		if i == 0 {
+			if got := fmt.Sprintf("%x", f.Sum(nil)); got != first {
+				t.Fatalf("%s: first entry: got %s", name, got)
+			}
+		}
+	}
+	if testing.Short() {
+		return
+	}
+	if got := fmt.Sprintf("%x", f.Sum(nil)); got != expected {
+		t.Fatalf("%s: got %s", name, got)
+	}
+}
+
+type encapsulator interface {
+	Encapsulate(rand io.Reader) (ct, ss []byte, err error)
+}
+
+// drbgReader reads random bytes from a DRBG.
+type drbgReader struct{ *nist.DRBG }
+
+func (r drbgReader) Read(b []byte) (int, error) {
+	r.Fill(b)
+	return len(b), nil
+}
+
+func mustWrite(t *testing.T, f io.Writer, format string, data any) {
+	_, err := fmt.Fprintf(f, format, data)
+	test.CheckNoErr(t, err, "fprintf failed")
+}
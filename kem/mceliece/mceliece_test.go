@@ -0,0 +1,98 @@
+package mceliece_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece348864"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece348864f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece460896"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece460896f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6688128"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6688128f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6960119"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6960119f"
+)
+
+var allSchemes = []kem.Scheme{
+	mceliece348864.Scheme(),
+	mceliece348864f.Scheme(),
+	mceliece460896.Scheme(),
+	mceliece460896f.Scheme(),
+	mceliece6688128.Scheme(),
+	mceliece6688128f.Scheme(),
+	mceliece6960119.Scheme(),
+	mceliece6960119f.Scheme(),
+}
+
+func TestKEM(t *testing.T) {
+	for _, scheme := range allSchemes {
+		t.Run(scheme.Name(), func(t *testing.T) {
+			seed := make([]byte, scheme.SeedSize())
+			pk, sk := scheme.DeriveKeyPair(seed)
+
+			// The public key recomputed from the private key is the same.
+			packedSk, err := sk.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary failed")
+			sk2, err := scheme.UnmarshalBinaryPrivateKey(packedSk)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey failed")
+			test.CheckOk(pk.Equal(sk2.Public()), "wrong public key", t)
+
+			ct, ss, err := scheme.Encapsulate(pk)
+			test.CheckNoErr(t, err, "Encapsulate failed")
+			ss2, err := scheme.Decapsulate(sk2, ct)
+			test.CheckNoErr(t, err, "Decapsulate failed")
+			test.CheckOk(bytes.Equal(ss, ss2), "shared keys differ", t)
+
+			// A different syndrome is rejected implicitly, in a way that only
+			// depends on the ciphertext.
+			ct[0] ^= 1
+			ss2, err = scheme.Decapsulate(sk2, ct)
+			test.CheckNoErr(t, err, "Decapsulate failed")
+			test.CheckOk(!bytes.Equal(ss, ss2), "tampered ciphertext accepted", t)
+			ss3, err := scheme.Decapsulate(sk, ct)
+			test.CheckNoErr(t, err, "Decapsulate failed")
+			test.CheckOk(bytes.Equal(ss2, ss3), "rejection is not deterministic", t)
+		})
+	}
+}
+
+func TestReadPublicKey(t *testing.T) {
+	seed := make([]byte, mceliece6960119.KeySeedSize)
+	pk, _ := mceliece6960119.NewKeyFromSeed(seed)
+
+	var buf bytes.Buffer
+	n, err := pk.WriteTo(&buf)
+	test.CheckNoErr(t, err, "WriteTo failed")
+	test.CheckOk(n == mceliece6960119.PublicKeySize, "wrong size", t)
+
+	packed := bytes.Clone(buf.Bytes())
+	pk2, err := mceliece6960119.ReadPublicKey(&buf)
+	test.CheckNoErr(t, err, "ReadPublicKey failed")
+	test.CheckOk(pk.Equal(pk2), "public keys differ", t)
+
+	_, err = mceliece6960119.ReadPublicKey(bytes.NewReader(packed[1:]))
+	test.CheckOk(err != nil, "truncated public key accepted", t)
+
+	// The rows of the public key are not a whole number of bytes, and the
+	// unused bits must be zero.
+	packed[mceliece6960119.PublicKeySize-1] |= 0x80
+	_, err = mceliece6960119.ReadPublicKey(bytes.NewReader(packed))
+	test.CheckIsErr(t, err, "public key with padding accepted")
+	_, err = mceliece6960119.Scheme().UnmarshalBinaryPublicKey(packed)
+	test.CheckIsErr(t, err, "public key with padding accepted")
+}
+
+func TestCiphertextPadding(t *testing.T) {
+	scheme := mceliece6960119.Scheme()
+	seed := make([]byte, scheme.SeedSize())
+	pk, sk := scheme.DeriveKeyPair(seed)
+
+	ct, _, err := scheme.Encapsulate(pk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+	ct[len(ct)-1] |= 0x80
+	_, err = scheme.Decapsulate(sk, ct)
+	test.CheckOk(err == kem.ErrCipherText, "ciphertext with padding accepted", t)
+}
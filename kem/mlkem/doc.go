@@ -1,6 +1,10 @@
 // Package mlkem implements  IND-CCA2 secure ML-KEM key encapsulation
 // mechanism (KEM) as defined in FIPS 203.
 //
+// Private keys derived from a seed retain it, so they can be stored as the
+// 64-byte seed (d, z) instead of the expanded decapsulation key. See the
+// PrivateKeyFormat type of each parameter set.
+//
 // https://nvlpubs.nist.gov/nistpubs/fips/nist.fips.203.pdf
 package mlkem
 
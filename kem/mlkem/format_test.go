@@ -0,0 +1,84 @@
+package mlkem
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+)
+
+func TestSeed(t *testing.T) {
+	for _, mode := range validateModes {
+		s := mode.scheme
+		t.Run(s.Name(), func(t *testing.T) {
+			seed := make([]byte, s.SeedSize())
+			for i := range seed {
+				seed[i] = byte(i)
+			}
+			_, sk := s.DeriveKeyPair(seed)
+			got := sk.(interface{ Seed() []byte }).Seed()
+			test.CheckOk(bytes.Equal(got, seed), "wrong seed", t)
+
+			packed, _ := sk.MarshalBinary()
+			sk2, err := s.UnmarshalBinaryPrivateKey(packed)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+			got = sk2.(interface{ Seed() []byte }).Seed()
+			test.CheckOk(got == nil, "expanded key retains a seed", t)
+		})
+	}
+}
+
+func TestPrivateKeyFormat(t *testing.T) {
+	var seed [mlkem768.KeySeedSize]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	_, sk := mlkem768.NewKeyFromSeed(seed[:])
+	expanded, _ := sk.MarshalBinary()
+
+	for _, format := range []mlkem768.PrivateKeyFormat{
+		mlkem768.ExpandedFormat,
+		mlkem768.SeedFormat,
+		mlkem768.SeedAndExpandedFormat,
+	} {
+		buf, err := sk.MarshalBinaryFormat(format)
+		test.CheckNoErr(t, err, "MarshalBinaryFormat")
+		test.CheckOk(len(buf) == format.Size(), "wrong size", t)
+
+		var sk2 mlkem768.PrivateKey
+		test.CheckNoErr(t, sk2.UnmarshalBinaryFormat(buf, format),
+			"UnmarshalBinaryFormat")
+		test.CheckOk(sk.Equal(&sk2), "private keys differ", t)
+		test.CheckOk(
+			(sk2.Seed() != nil) == (format != mlkem768.ExpandedFormat),
+			"wrong seed retention", t)
+
+		err = sk2.UnmarshalBinaryFormat(buf[1:], format)
+		test.CheckOk(err == kem.ErrPrivKeySize, "wrong size accepted", t)
+	}
+
+	buf, err := sk.MarshalBinaryFormat(mlkem768.SeedAndExpandedFormat)
+	test.CheckNoErr(t, err, "MarshalBinaryFormat")
+	test.CheckOk(bytes.Equal(buf[:len(seed)], seed[:]) &&
+		bytes.Equal(buf[len(seed):], expanded), "wrong encoding", t)
+
+	// The seed and the expanded key must agree.
+	var sk2 mlkem768.PrivateKey
+	for _, i := range []int{0, 32, len(seed), len(buf) - 1} {
+		bad := bytes.Clone(buf)
+		bad[i] ^= 1
+		err = sk2.UnmarshalBinaryFormat(bad, mlkem768.SeedAndExpandedFormat)
+		test.CheckOk(err == kem.ErrPrivKey, "inconsistent key accepted", t)
+	}
+
+	// A key unpacked from its expanded form can't be encoded by its seed.
+	test.CheckNoErr(t, sk2.Unpack(expanded), "Unpack")
+	_, err = sk2.MarshalBinaryFormat(mlkem768.SeedFormat)
+	test.CheckIsErr(t, err, "seed of expanded key")
+	_, err = sk2.MarshalBinaryFormat(mlkem768.SeedAndExpandedFormat)
+	test.CheckIsErr(t, err, "seed of expanded key")
+	_, err = sk.MarshalBinaryFormat(mlkem768.PrivateKeyFormat(3))
+	test.CheckIsErr(t, err, "unknown format")
+}
@@ -7,6 +7,8 @@ package mlkem512
 import (
 	"bytes"
 	"crypto/subtle"
+	"encoding/asn1"
+	"errors"
 	"io"
 
 	cryptoRand "crypto/rand"
@@ -48,6 +50,40 @@ type PrivateKey struct {
 	pk  *cpapke.PublicKey
 	hpk [32]byte // H(pk)
 	z   [32]byte
+
+	seed    [KeySeedSize]byte // (d, z), if retained
+	seedSet bool
+}
+
+// PrivateKeyFormat selects the encoding of a private key.
+type PrivateKeyFormat int
+
+const (
+	// ExpandedFormat is the PrivateKeySize bytes decapsulation key of
+	// FIPS 203, as returned by Pack and MarshalBinary.
+	ExpandedFormat PrivateKeyFormat = iota
+
+	// SeedFormat is the KeySeedSize bytes seed (d, z) given to
+	// NewKeyFromSeed.
+	SeedFormat
+
+	// SeedAndExpandedFormat is the seed followed by the decapsulation key.
+	SeedAndExpandedFormat
+)
+
+// Size returns the length of a private key encoded in format f, or zero
+// if f is not a valid format.
+func (f PrivateKeyFormat) Size() int {
+	switch f {
+	case ExpandedFormat:
+		return PrivateKeySize
+	case SeedFormat:
+		return KeySeedSize
+	case SeedAndExpandedFormat:
+		return KeySeedSize + PrivateKeySize
+	default:
+		return 0
+	}
 }
 
 // NewKeyFromSeed derives a public/private keypair deterministically
@@ -65,6 +101,8 @@ func NewKeyFromSeed(seed []byte) (*PublicKey, *PrivateKey) {
 	pk.pk, sk.sk = cpapke.NewKeyFromSeedMLKEM(seed[:cpapke.KeySeedSize])
 	sk.pk = pk.pk
 	copy(sk.z[:], seed[cpapke.KeySeedSize:])
+	copy(sk.seed[:], seed)
+	sk.seedSet = true
 
 	// Compute H(pk)
 	var ppk [cpapke.PublicKeySize]byte
@@ -79,6 +117,10 @@ func NewKeyFromSeed(seed []byte) (*PublicKey, *PrivateKey) {
 
 // GenerateKeyPair generates public and private keys using entropy from rand.
 // If rand is nil, crypto/rand.Reader will be used.
+//
+// The pairwise consistency test of FIPS 140-3 is not run, as it costs an
+// encapsulation and a decapsulation. It is run by the GenerateKeyPair method
+// of StrictScheme, and by PrivateKey.Validate.
 func GenerateKeyPair(rand io.Reader) (*PublicKey, *PrivateKey, error) {
 	var seed [KeySeedSize]byte
 	if rand == nil {
@@ -92,6 +134,45 @@ func GenerateKeyPair(rand io.Reader) (*PublicKey, *PrivateKey, error) {
 	return pk, sk, nil
 }
 
+// Validate checks that sk is a well-formed decapsulation key: that the
+// stored H(ek) matches the embedded encapsulation key (the hash check of
+// FIPS 203 §7.3), and that sk decapsulates a ciphertext encapsulated to
+// its own public key (the pairwise consistency test of FIPS 140-3).
+//
+// Returns kem.ErrPrivKey if a check fails.
+func (sk *PrivateKey) Validate() error {
+	if sk.sk == nil || sk.pk == nil {
+		return kem.ErrPrivKey
+	}
+
+	var ppk [PublicKeySize]byte
+	sk.pk.Pack(ppk[:])
+	var hpk [32]byte
+	h := sha3.New256()
+	h.Write(ppk[:])
+	h.Read(hpk[:])
+	if !bytes.Equal(hpk[:], sk.hpk[:]) {
+		return kem.ErrPrivKey
+	}
+
+	return sk.pairwiseConsistencyTest()
+}
+
+// pairwiseConsistencyTest encapsulates a fresh shared key to the public
+// key of sk and checks that sk recovers it.
+func (sk *PrivateKey) pairwiseConsistencyTest() error {
+	var ct [CiphertextSize]byte
+	var ss, ss2 [SharedKeySize]byte
+
+	pk := PublicKey{pk: sk.pk, hpk: sk.hpk}
+	pk.EncapsulateTo(ct[:], ss[:], nil)
+	sk.DecapsulateTo(ss2[:], ct[:])
+	if subtle.ConstantTimeCompare(ss[:], ss2[:]) != 1 {
+		return kem.ErrPrivKey
+	}
+	return nil
+}
+
 // EncapsulateTo generates a shared key and ciphertext that contains it
 // for the public key using randomness from seed and writes the shared key
 // to ss and ciphertext to ct.
@@ -211,6 +292,7 @@ func (sk *PrivateKey) Unpack(buf []byte) error {
 		return kem.ErrPrivKeySize
 	}
 
+	sk.seedSet = false
 	sk.sk = new(cpapke.PrivateKey)
 	sk.sk.Unpack(buf[:cpapke.PrivateKeySize])
 	buf = buf[cpapke.PrivateKeySize:]
@@ -229,6 +311,72 @@ func (sk *PrivateKey) Unpack(buf []byte) error {
 	return nil
 }
 
+// Seed returns the seed (d, z) from which sk was derived, or nil if sk
+// was unpacked from its expanded form and so doesn't retain it.
+func (sk *PrivateKey) Seed() []byte {
+	if !sk.seedSet {
+		return nil
+	}
+	var ret [KeySeedSize]byte
+	copy(ret[:], sk.seed[:])
+	return ret[:]
+}
+
+// MarshalBinaryFormat encodes sk in the given format.
+//
+// Returns an error if the format includes the seed and sk doesn't retain
+// it.
+func (sk *PrivateKey) MarshalBinaryFormat(format PrivateKeyFormat) ([]byte, error) {
+	ret := make([]byte, format.Size())
+	switch format {
+	case ExpandedFormat:
+		sk.Pack(ret)
+	case SeedFormat, SeedAndExpandedFormat:
+		if !sk.seedSet {
+			return nil, errors.New("private key does not retain its seed")
+		}
+		copy(ret, sk.seed[:])
+		if format == SeedAndExpandedFormat {
+			sk.Pack(ret[KeySeedSize:])
+		}
+	default:
+		return nil, errors.New("unknown private key format")
+	}
+	return ret, nil
+}
+
+// UnmarshalBinaryFormat decodes a private key encoded in the given
+// format into sk.
+//
+// Expanded keys are subject to the same checks as in Unpack. If both the
+// seed and the expanded key are present, the expanded key must be the one
+// derived from the seed, or kem.ErrPrivKey is returned.
+func (sk *PrivateKey) UnmarshalBinaryFormat(
+	buf []byte, format PrivateKeyFormat,
+) error {
+	if format.Size() == 0 {
+		return errors.New("unknown private key format")
+	}
+	if len(buf) != format.Size() {
+		return kem.ErrPrivKeySize
+	}
+
+	if format == ExpandedFormat {
+		return sk.Unpack(buf)
+	}
+
+	_, sk2 := NewKeyFromSeed(buf[:KeySeedSize])
+	if format == SeedAndExpandedFormat {
+		var expanded [PrivateKeySize]byte
+		sk2.Pack(expanded[:])
+		if subtle.ConstantTimeCompare(expanded[:], buf[KeySeedSize:]) != 1 {
+			return kem.ErrPrivKey
+		}
+	}
+	*sk = *sk2
+	return nil
+}
+
 // Packs pk to buf.
 //
 // Panics if buf is not of size PublicKeySize.
@@ -264,13 +412,30 @@ func (pk *PublicKey) Unpack(buf []byte) error {
 
 // Boilerplate down below for the KEM scheme API.
 
-type scheme struct{}
+type scheme struct {
+	strict bool
+}
 
-var sch kem.Scheme = &scheme{}
+var (
+	sch       kem.Scheme = &scheme{}
+	strictSch kem.Scheme = &scheme{strict: true}
+)
 
 // Scheme returns a KEM interface.
+//
+// Its UnmarshalBinaryPublicKey and UnmarshalBinaryPrivateKey methods apply
+// the input checks of FIPS 203 §7.2 and §7.3.
 func Scheme() kem.Scheme { return sch }
 
+// StrictScheme returns a KEM interface that rejects non-canonical keys.
+//
+// Besides the checks done by Scheme, its UnmarshalBinaryPrivateKey
+// requires every coefficient of the encoded keys to be reduced modulo q,
+// and runs the pairwise consistency test. Invalid keys are rejected with
+// kem.ErrPubKey or kem.ErrPrivKey. Its GenerateKeyPair also runs the
+// pairwise consistency test on the keys it returns.
+func StrictScheme() kem.Scheme { return strictSch }
+
 func (*scheme) Name() string               { return "ML-KEM-512" }
 func (*scheme) PublicKeySize() int         { return PublicKeySize }
 func (*scheme) PrivateKeySize() int        { return PrivateKeySize }
@@ -279,6 +444,10 @@ func (*scheme) SharedKeySize() int         { return SharedKeySize }
 func (*scheme) CiphertextSize() int        { return CiphertextSize }
 func (*scheme) EncapsulationSeedSize() int { return EncapsulationSeedSize }
 
+func (*scheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}
+}
+
 func (sk *PrivateKey) Scheme() kem.Scheme { return sch }
 func (pk *PublicKey) Scheme() kem.Scheme  { return sch }
 
@@ -333,8 +502,18 @@ func (pk *PublicKey) MarshalBinary() ([]byte, error) {
 	return ret[:], nil
 }
 
-func (*scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
-	return GenerateKeyPair(cryptoRand.Reader)
+func (s *scheme) GenerateKeyPair() (kem.PublicKey, kem.PrivateKey, error) {
+	pk, sk, err := GenerateKeyPair(cryptoRand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.strict {
+		// Pairwise consistency test required by FIPS 140-3 IG 10.3.A.
+		if err := sk.pairwiseConsistencyTest(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pk, sk, nil
 }
 
 func (*scheme) DeriveKeyPair(seed []byte) (kem.PublicKey, kem.PrivateKey) {
@@ -395,7 +574,7 @@ func (*scheme) UnmarshalBinaryPublicKey(buf []byte) (kem.PublicKey, error) {
 	return &ret, nil
 }
 
-func (*scheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
+func (s *scheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
 	if len(buf) != PrivateKeySize {
 		return nil, kem.ErrPrivKeySize
 	}
@@ -403,5 +582,17 @@ func (*scheme) UnmarshalBinaryPrivateKey(buf []byte) (kem.PrivateKey, error) {
 	if err := ret.Unpack(buf); err != nil {
 		return nil, err
 	}
+	if s.strict {
+		// Unpack reduces the coefficients, so the packed key only matches
+		// buf if every coefficient in buf was reduced modulo q.
+		var buf2 [PrivateKeySize]byte
+		ret.Pack(buf2[:])
+		if !bytes.Equal(buf, buf2[:]) {
+			return nil, kem.ErrPrivKey
+		}
+		if err := ret.pairwiseConsistencyTest(); err != nil {
+			return nil, err
+		}
+	}
 	return &ret, nil
 }
@@ -0,0 +1,144 @@
+package mlkem
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem1024"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem512"
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+)
+
+type validator interface {
+	Validate() error
+}
+
+var validateModes = []struct {
+	scheme, strict kem.Scheme
+	skSize         int // size of the encoded s in the private key
+}{
+	{mlkem512.Scheme(), mlkem512.StrictScheme(), 2 * 384},
+	{mlkem768.Scheme(), mlkem768.StrictScheme(), 3 * 384},
+	{mlkem1024.Scheme(), mlkem1024.StrictScheme(), 4 * 384},
+}
+
+// setCoeff overwrites the i-th 12-bit coefficient of an encoded vector.
+func setCoeff(buf []byte, i int, v uint16) {
+	b := buf[3*(i/2):]
+	if i%2 == 0 {
+		b[0] = byte(v)
+		b[1] = b[1]&0xf0 | byte(v>>8)
+	} else {
+		b[1] = b[1]&0x0f | byte(v<<4)
+		b[2] = byte(v >> 4)
+	}
+}
+
+func getCoeff(buf []byte, i int) uint16 {
+	b := buf[3*(i/2):]
+	if i%2 == 0 {
+		return uint16(b[0]) | uint16(b[1]&0x0f)<<8
+	}
+	return uint16(b[1]>>4) | uint16(b[2])<<4
+}
+
+// rehash recomputes H(ek) in an encoded private key.
+func rehash(sk []byte, skSize, pkSize int) {
+	h := sha3.New256()
+	h.Write(sk[skSize : skSize+pkSize])
+	h.Read(sk[skSize+pkSize : skSize+pkSize+32])
+}
+
+func TestValidate(t *testing.T) {
+	const q = 3329
+
+	for _, mode := range validateModes {
+		s, strict := mode.scheme, mode.strict
+		pkSize := s.PublicKeySize()
+
+		t.Run(s.Name(), func(t *testing.T) {
+			pk, sk, err := s.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair")
+			test.CheckNoErr(t, sk.(validator).Validate(), "sk.Validate")
+
+			// StrictScheme runs the pairwise consistency test on new keys.
+			_, strictSk, err := strict.GenerateKeyPair()
+			test.CheckNoErr(t, err, "StrictScheme GenerateKeyPair")
+			test.CheckNoErr(t, strictSk.(validator).Validate(), "sk.Validate")
+
+			ppk, _ := pk.MarshalBinary()
+			psk, _ := sk.MarshalBinary()
+
+			for _, sch := range []kem.Scheme{s, strict} {
+				_, err = sch.UnmarshalBinaryPublicKey(ppk)
+				test.CheckNoErr(t, err, "UnmarshalBinaryPublicKey")
+				sk2, err := sch.UnmarshalBinaryPrivateKey(psk)
+				test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+				test.CheckOk(sk.Equal(sk2), "private keys differ", t)
+			}
+
+			// Modulus check: a coefficient of the encapsulation key ≥ q.
+			bad := append([]byte{}, ppk...)
+			setCoeff(bad, 1, q)
+			for _, sch := range []kem.Scheme{s, strict} {
+				_, err = sch.UnmarshalBinaryPublicKey(bad)
+				test.CheckIsErr(t, err, "non-canonical public key accepted")
+				test.CheckOk(err == kem.ErrPubKey,
+					fmt.Sprintf("expected ErrPubKey, got %v", err), t)
+			}
+
+			// Hash check: H(ek) stored in the decapsulation key is wrong.
+			bad = append([]byte{}, psk...)
+			bad[mode.skSize+pkSize] ^= 1
+			for _, sch := range []kem.Scheme{s, strict} {
+				_, err = sch.UnmarshalBinaryPrivateKey(bad)
+				test.CheckOk(err == kem.ErrPrivKey,
+					fmt.Sprintf("expected ErrPrivKey, got %v", err), t)
+			}
+
+			// Coefficient of the embedded encapsulation key ≥ q, with a
+			// matching H(ek): only rejected in strict mode.
+			bad = append([]byte{}, psk...)
+			ek := bad[mode.skSize:]
+			i := 0
+			for ; getCoeff(ek, i) >= 4096-q; i++ {
+			}
+			setCoeff(ek, i, getCoeff(ek, i)+q)
+			rehash(bad, mode.skSize, pkSize)
+			_, err = s.UnmarshalBinaryPrivateKey(bad)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+			_, err = strict.UnmarshalBinaryPrivateKey(bad)
+			test.CheckOk(err == kem.ErrPrivKey,
+				fmt.Sprintf("expected ErrPrivKey, got %v", err), t)
+
+			// Coefficient of s ≥ q: only rejected in strict mode.
+			bad = append([]byte{}, psk...)
+			i = 0
+			for ; getCoeff(bad, i) >= 4096-q; i++ {
+			}
+			setCoeff(bad, i, getCoeff(bad, i)+q)
+			_, err = s.UnmarshalBinaryPrivateKey(bad)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+			_, err = strict.UnmarshalBinaryPrivateKey(bad)
+			test.CheckOk(err == kem.ErrPrivKey,
+				fmt.Sprintf("expected ErrPrivKey, got %v", err), t)
+
+			// Pairwise consistency: s taken from another key pair.
+			_, other, err := s.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair")
+			pother, _ := other.MarshalBinary()
+			bad = append([]byte{}, psk...)
+			copy(bad[:mode.skSize], pother[:mode.skSize])
+			sk2, err := s.UnmarshalBinaryPrivateKey(bad)
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey")
+			test.CheckOk(sk2.(validator).Validate() == kem.ErrPrivKey,
+				"inconsistent private key passed Validate", t)
+			_, err = strict.UnmarshalBinaryPrivateKey(bad)
+			test.CheckOk(err == kem.ErrPrivKey,
+				fmt.Sprintf("expected ErrPrivKey, got %v", err), t)
+		})
+	}
+}
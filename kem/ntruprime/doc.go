@@ -0,0 +1,18 @@
+//go:generate go run gen.go
+
+// Package ntruprime provides the key encapsulation mechanism Streamlined NTRU
+// Prime.
+//
+// This implements the parameter sets sntrup653, sntrup761, sntrup857,
+// sntrup953, sntrup1013 and sntrup1277 of the third round of the NIST PQC
+// competition [1], where the ciphertext includes a confirmation hash.
+// sntrup761 is used by the hybrid key exchange sntrup761x25519-sha512 of
+// OpenSSH, see kem/hybrid.
+//
+// This implementation follows the reference implementation [2].
+//
+// References:
+//
+//	[1] https://ntruprime.cr.yp.to/nist/ntruprime-20201007.pdf
+//	[2] https://ntruprime.cr.yp.to/software.html
+package ntruprime
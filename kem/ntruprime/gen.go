@@ -0,0 +1,105 @@
+//go:build ignore
+// +build ignore
+
+// Autogenerates the Streamlined NTRU Prime variants from templates to prevent
+// too much duplicated code between the code for different parameter sets.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/cloudflare/circl/kem/ntruprime/internal"
+)
+
+type Instance struct {
+	Name string
+
+	// Degree p of the polynomial x^p - x - 1, which is a prime.
+	P int
+
+	// Modulus q, a prime.
+	Q int
+
+	// Hamming weight w of the short polynomials.
+	W int
+}
+
+func (m Instance) Pkg() string {
+	return strings.ToLower(m.Name)
+}
+
+func encodedSize(p, m int) int {
+	M := make([]uint16, p)
+	for i := range M {
+		M[i] = uint16(m)
+	}
+	return internal.EncodedSize(M)
+}
+
+// RqBytes returns the length of an encoded polynomial with coefficients
+// modulo q.
+func (m Instance) RqBytes() int { return encodedSize(m.P, m.Q) }
+
+// RoundedBytes returns the length of an encoded polynomial with coefficients
+// modulo q that are multiples of 3.
+func (m Instance) RoundedBytes() int { return encodedSize(m.P, (m.Q+2)/3) }
+
+var (
+	Instances = []Instance{
+		{Name: "sntrup653", P: 653, Q: 4621, W: 288},
+		{Name: "sntrup761", P: 761, Q: 4591, W: 286},
+		{Name: "sntrup857", P: 857, Q: 5167, W: 322},
+		{Name: "sntrup953", P: 953, Q: 6343, W: 396},
+		{Name: "sntrup1013", P: 1013, Q: 7177, W: 448},
+		{Name: "sntrup1277", P: 1277, Q: 7879, W: 492},
+	}
+	Templates       = []string{"sntrup", "poly"}
+	TemplateWarning = "// Code generated from"
+)
+
+func main() {
+	generatePackageFiles()
+}
+
+// Generates instance/<file>.go from templates/<file>.templ.go
+func generatePackageFiles() {
+	for _, name := range Templates {
+		tl, err := template.ParseFiles("templates/" + name + ".templ.go")
+		if err != nil {
+			panic(err)
+		}
+
+		for _, mode := range Instances {
+			buf := new(bytes.Buffer)
+			err := tl.Execute(buf, mode)
+			if err != nil {
+				panic(err)
+			}
+
+			// Formating output code
+			code, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(fmt.Sprintf("error formating code: %v", err))
+			}
+
+			res := string(code)
+			offset := strings.Index(res, TemplateWarning)
+			if offset == -1 {
+				panic("Missing template warning in " + name + ".templ.go")
+			}
+			err = os.MkdirAll(mode.Pkg(), 0o755)
+			if err != nil {
+				panic(err)
+			}
+			err = os.WriteFile(mode.Pkg()+"/"+name+".go", []byte(res[offset:]), 0o644)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package internal contains the parts of Streamlined NTRU Prime that do not
+// depend on the parameter set: the encoding of sequences of integers of
+// arbitrary ranges, constant-time division and constant-time sorting.
+package internal
+
+// DivMod14 returns x / m and x % m in constant time, for 0 < m < 16384.
+// The running time depends on m, but not on x.
+func DivMod14(x uint32, m uint16) (uint32, uint16) {
+	v := uint32(0x80000000) / uint32(m)
+
+	// vm <= 2^31 <= vm+m-1, so that the quotient is off by at most one
+	// after two rounds.
+	qpart := uint32((uint64(x) * uint64(v)) >> 31)
+	x -= qpart * uint32(m)
+	q := qpart
+
+	qpart = uint32((uint64(x) * uint64(v)) >> 31)
+	x -= qpart * uint32(m)
+	q += qpart
+
+	x -= uint32(m)
+	q++
+	mask := -(x >> 31)
+	x += mask & uint32(m)
+	q += mask
+
+	return q, uint16(x)
+}
+
+// Mod14 returns x % m in constant time, for 0 < m < 16384.
+func Mod14(x uint32, m uint16) uint16 {
+	_, r := DivMod14(x, m)
+	return r
+}
+
+// EncodedSize returns the length of the encoding of a sequence of integers
+// R with 0 <= R[i] < M[i] < 16384.
+func EncodedSize(M []uint16) int {
+	if len(M) == 1 {
+		n := 0
+		for m := uint32(M[0]); m > 1; m = (m + 255) >> 8 {
+			n++
+		}
+		return n
+	}
+
+	n := 0
+	M2 := make([]uint16, (len(M)+1)/2)
+	i := 0
+	for ; i < len(M)-1; i += 2 {
+		m := uint32(M[i+1]) * uint32(M[i])
+		for ; m >= 16384; m = (m + 255) >> 8 {
+			n++
+		}
+		M2[i/2] = uint16(m)
+	}
+	if i < len(M) {
+		M2[i/2] = M[i]
+	}
+	return n + EncodedSize(M2)
+}
+
+// Encode writes to out the encoding of R, where 0 <= R[i] < M[i] < 16384,
+// and returns the remainder of out.
+//
+// Pairs of adjacent integers are merged, the low bytes of which are written
+// out until they fit in 14 bits, recursively until a single integer is left.
+func Encode(out []byte, R, M []uint16) []byte {
+	if len(M) == 1 {
+		r, m := R[0], M[0]
+		for m > 1 {
+			out[0] = byte(r)
+			out = out[1:]
+			r >>= 8
+			m = (m + 255) >> 8
+		}
+		return out
+	}
+
+	R2 := make([]uint16, (len(M)+1)/2)
+	M2 := make([]uint16, (len(M)+1)/2)
+	i := 0
+	for ; i < len(M)-1; i += 2 {
+		m0 := uint32(M[i])
+		r := uint32(R[i]) + uint32(R[i+1])*m0
+		m := uint32(M[i+1]) * m0
+		for m >= 16384 {
+			out[0] = byte(r)
+			out = out[1:]
+			r >>= 8
+			m = (m + 255) >> 8
+		}
+		R2[i/2] = uint16(r)
+		M2[i/2] = uint16(m)
+	}
+	if i < len(M) {
+		R2[i/2] = R[i]
+		M2[i/2] = M[i]
+	}
+	return Encode(out, R2, M2)
+}
+
+// Decode reads the encoding S of the integers out, where 0 <= out[i] < M[i]
+// < 16384, as written by Encode.
+//
+// Every S decodes to integers in range, even if it is not the output of
+// Encode.
+func Decode(out []uint16, S []byte, M []uint16) {
+	if len(M) == 1 {
+		switch {
+		case M[0] == 1:
+			out[0] = 0
+		case M[0] <= 256:
+			out[0] = Mod14(uint32(S[0]), M[0])
+		default:
+			out[0] = Mod14(uint32(S[0])+uint32(S[1])<<8, M[0])
+		}
+		return
+	}
+
+	R2 := make([]uint16, (len(M)+1)/2)
+	M2 := make([]uint16, (len(M)+1)/2)
+	bottomr := make([]uint16, len(M)/2)
+	bottomt := make([]uint32, len(M)/2)
+	i := 0
+	for ; i < len(M)-1; i += 2 {
+		m := uint32(M[i]) * uint32(M[i+1])
+		switch {
+		case m > 256*16383:
+			bottomt[i/2] = 256 * 256
+			bottomr[i/2] = uint16(S[0]) + 256*uint16(S[1])
+			S = S[2:]
+			M2[i/2] = uint16((((m + 255) >> 8) + 255) >> 8)
+		case m >= 16384:
+			bottomt[i/2] = 256
+			bottomr[i/2] = uint16(S[0])
+			S = S[1:]
+			M2[i/2] = uint16((m + 255) >> 8)
+		default:
+			bottomt[i/2] = 1
+			bottomr[i/2] = 0
+			M2[i/2] = uint16(m)
+		}
+	}
+	if i < len(M) {
+		M2[i/2] = M[i]
+	}
+
+	Decode(R2, S, M2)
+
+	for i = 0; i < len(M)-1; i += 2 {
+		r := uint32(bottomr[i/2]) + bottomt[i/2]*uint32(R2[i/2])
+		r1, r0 := DivMod14(r, M[i])
+		out[i] = r0
+		// Only needed for invalid inputs.
+		out[i+1] = Mod14(r1, M[i+1])
+	}
+	if i < len(M) {
+		out[i] = R2[i/2]
+	}
+}
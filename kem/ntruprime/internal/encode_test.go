@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sort"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 761, 1277} {
+		for _, mod := range []uint16{1, 2, 255, 256, 257, 1531, 4591, 16383} {
+			R := make([]uint16, n)
+			M := make([]uint16, n)
+			var buf [2]byte
+			for i := range R {
+				M[i] = mod
+				_, _ = rand.Read(buf[:])
+				R[i] = binary.LittleEndian.Uint16(buf[:]) % mod
+			}
+
+			enc := make([]byte, EncodedSize(M))
+			if rest := Encode(enc, R, M); len(rest) != 0 {
+				t.Fatalf("n=%d m=%d: wrong encoded size", n, mod)
+			}
+			dec := make([]uint16, n)
+			Decode(dec, enc, M)
+			for i := range R {
+				if dec[i] != R[i] {
+					t.Fatalf("n=%d m=%d: decoding failed", n, mod)
+				}
+			}
+		}
+	}
+}
+
+func TestDivMod14(t *testing.T) {
+	var buf [4]byte
+	for _, m := range []uint16{1, 2, 3, 1531, 4591, 16383} {
+		for i := 0; i < 10000; i++ {
+			_, _ = rand.Read(buf[:])
+			x := binary.LittleEndian.Uint32(buf[:])
+			q, r := DivMod14(x, m)
+			if q != x/uint32(m) || uint32(r) != x%uint32(m) {
+				t.Fatalf("DivMod14(%d, %d) = %d, %d", x, m, q, r)
+			}
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 100, 761, 1277} {
+		x := make([]uint32, n)
+		var buf [4]byte
+		for i := range x {
+			_, _ = rand.Read(buf[:])
+			x[i] = binary.LittleEndian.Uint32(buf[:])
+		}
+		Uint32Sort(x)
+		if !sort.SliceIsSorted(x, func(i, j int) bool { return x[i] < x[j] }) {
+			t.Fatalf("n=%d: not sorted", n)
+		}
+	}
+}
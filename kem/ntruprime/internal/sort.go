@@ -0,0 +1,42 @@
+package internal
+
+// Uint32Sort sorts x in increasing order in constant time, with the sorting
+// network of djbsort.
+func Uint32Sort(x []uint32) {
+	n := len(x)
+	if n < 2 {
+		return
+	}
+	top := 1
+	for top < n-top {
+		top += top
+	}
+	for p := top; p > 0; p >>= 1 {
+		for i := 0; i < n-p; i++ {
+			if i&p == 0 {
+				uint32MinMax(&x[i], &x[i+p])
+			}
+		}
+		i := 0
+		for q := top; q > p; q >>= 1 {
+			for ; i < n-q; i++ {
+				if i&p == 0 {
+					a := x[i+p]
+					for r := q; r > p; r >>= 1 {
+						uint32MinMax(&a, &x[i+r])
+					}
+					x[i+p] = a
+				}
+			}
+		}
+	}
+}
+
+// Sets a, b to min(a, b), max(a, b) in constant time.
+func uint32MinMax(a, b *uint32) {
+	ab := *a ^ *b
+	c := uint32((uint64(*b) - uint64(*a)) >> 63)
+	c = -c & ab
+	*a ^= c
+	*b ^= c
+}
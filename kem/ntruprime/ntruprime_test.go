@@ -0,0 +1,51 @@
+package ntruprime
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup761"
+)
+
+func TestImplicitRejection(t *testing.T) {
+	scheme := sntrup761.Scheme()
+	pk, sk, err := scheme.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	ct, ss, err := scheme.Encapsulate(pk)
+	test.CheckNoErr(t, err, "Encapsulate failed")
+
+	// Modifying either the rounded polynomial or the confirmation hash
+	// gives a different shared key, which depends only on the ciphertext.
+	for _, i := range []int{0, sntrup761.CiphertextSize - 1} {
+		ct2 := bytes.Clone(ct)
+		ct2[i] ^= 1
+		ss2, err := scheme.Decapsulate(sk, ct2)
+		test.CheckNoErr(t, err, "Decapsulate failed")
+		test.CheckOk(!bytes.Equal(ss, ss2), "tampered ciphertext accepted", t)
+		ss3, err := scheme.Decapsulate(sk, ct2)
+		test.CheckNoErr(t, err, "Decapsulate failed")
+		test.CheckOk(bytes.Equal(ss2, ss3), "rejection is not deterministic", t)
+	}
+}
+
+func TestNonCanonicalKeys(t *testing.T) {
+	scheme := sntrup761.Scheme()
+	pk, sk, err := scheme.GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+
+	// The last two bytes encode a coefficient modulo q < 2^16: 0xffff is
+	// out of range.
+	ppk, _ := pk.MarshalBinary()
+	ppk[len(ppk)-2] = 0xff
+	ppk[len(ppk)-1] = 0xff
+	_, err = scheme.UnmarshalBinaryPublicKey(ppk)
+	test.CheckOk(err == kem.ErrPubKey, "non-canonical public key accepted", t)
+
+	// A small coefficient of f encoded as 3.
+	psk, _ := sk.MarshalBinary()
+	psk[0] |= 3
+	_, err = scheme.UnmarshalBinaryPrivateKey(psk)
+	test.CheckOk(err == kem.ErrPrivKey, "non-canonical private key accepted", t)
+}
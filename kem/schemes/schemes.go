@@ -13,7 +13,14 @@
 // Post-quantum kems:
 //
 //	FrodoKEM-640-SHAKE
+//	FrodoKEM-976-SHAKE-Salted, FrodoKEM-976-AES-Salted,
+//	FrodoKEM-1344-SHAKE-Salted, FrodoKEM-1344-AES-Salted
+//	eFrodoKEM-976-SHAKE, eFrodoKEM-976-AES, eFrodoKEM-1344-SHAKE, eFrodoKEM-1344-AES
+//	HQC-128, HQC-192, HQC-256
+//	mceliece348864, mceliece348864f, mceliece460896, mceliece460896f,
+//	mceliece6688128, mceliece6688128f, mceliece6960119, mceliece6960119f
 //	Kyber512, Kyber768, Kyber1024
+//	sntrup653, sntrup761, sntrup857, sntrup953, sntrup1013, sntrup1277
 package schemes
 
 import (
@@ -21,14 +28,39 @@ import (
 
 	"github.com/cloudflare/circl/hpke"
 	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/frodo/efrodo1344aes"
+	"github.com/cloudflare/circl/kem/frodo/efrodo1344shake"
+	"github.com/cloudflare/circl/kem/frodo/efrodo976aes"
+	"github.com/cloudflare/circl/kem/frodo/efrodo976shake"
+	"github.com/cloudflare/circl/kem/frodo/frodo1344aessalted"
+	"github.com/cloudflare/circl/kem/frodo/frodo1344shakesalted"
 	"github.com/cloudflare/circl/kem/frodo/frodo640shake"
+	"github.com/cloudflare/circl/kem/frodo/frodo976aessalted"
+	"github.com/cloudflare/circl/kem/frodo/frodo976shakesalted"
+	"github.com/cloudflare/circl/kem/hqc/hqc128"
+	"github.com/cloudflare/circl/kem/hqc/hqc192"
+	"github.com/cloudflare/circl/kem/hqc/hqc256"
 	"github.com/cloudflare/circl/kem/hybrid"
 	"github.com/cloudflare/circl/kem/kyber/kyber1024"
 	"github.com/cloudflare/circl/kem/kyber/kyber512"
 	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece348864"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece348864f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece460896"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece460896f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6688128"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6688128f"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6960119"
+	"github.com/cloudflare/circl/kem/mceliece/mceliece6960119f"
 	"github.com/cloudflare/circl/kem/mlkem/mlkem1024"
 	"github.com/cloudflare/circl/kem/mlkem/mlkem512"
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup1013"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup1277"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup653"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup761"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup857"
+	"github.com/cloudflare/circl/kem/ntruprime/sntrup953"
 	"github.com/cloudflare/circl/kem/xwing"
 )
 
@@ -39,18 +71,46 @@ var allSchemes = [...]kem.Scheme{
 	hpke.KEM_X25519_HKDF_SHA256.Scheme(),
 	hpke.KEM_X448_HKDF_SHA512.Scheme(),
 	frodo640shake.Scheme(),
+	frodo976shakesalted.Scheme(),
+	frodo976aessalted.Scheme(),
+	frodo1344shakesalted.Scheme(),
+	frodo1344aessalted.Scheme(),
+	efrodo976shake.Scheme(),
+	efrodo976aes.Scheme(),
+	efrodo1344shake.Scheme(),
+	efrodo1344aes.Scheme(),
+	hqc128.Scheme(),
+	hqc192.Scheme(),
+	hqc256.Scheme(),
+	mceliece348864.Scheme(),
+	mceliece348864f.Scheme(),
+	mceliece460896.Scheme(),
+	mceliece460896f.Scheme(),
+	mceliece6688128.Scheme(),
+	mceliece6688128f.Scheme(),
+	mceliece6960119.Scheme(),
+	mceliece6960119f.Scheme(),
 	kyber512.Scheme(),
 	kyber768.Scheme(),
 	kyber1024.Scheme(),
 	mlkem512.Scheme(),
 	mlkem768.Scheme(),
 	mlkem1024.Scheme(),
+	sntrup653.Scheme(),
+	sntrup761.Scheme(),
+	sntrup857.Scheme(),
+	sntrup953.Scheme(),
+	sntrup1013.Scheme(),
+	sntrup1277.Scheme(),
 	hybrid.Kyber512X25519(),
 	hybrid.Kyber768X25519(),
 	hybrid.Kyber768X448(),
 	hybrid.Kyber1024X448(),
 	hybrid.P256Kyber768Draft00(),
 	hybrid.X25519MLKEM768(),
+	hybrid.SecP256r1MLKEM768(),
+	hybrid.SecP384r1MLKEM1024(),
+	hybrid.Sntrup761X25519SHA512(),
 	xwing.Scheme(),
 }
 
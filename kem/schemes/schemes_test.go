@@ -148,17 +148,45 @@ func Example_schemes() {
 	// HPKE_KEM_X25519_HKDF_SHA256
 	// HPKE_KEM_X448_HKDF_SHA512
 	// FrodoKEM-640-SHAKE
+	// FrodoKEM-976-SHAKE-Salted
+	// FrodoKEM-976-AES-Salted
+	// FrodoKEM-1344-SHAKE-Salted
+	// FrodoKEM-1344-AES-Salted
+	// eFrodoKEM-976-SHAKE
+	// eFrodoKEM-976-AES
+	// eFrodoKEM-1344-SHAKE
+	// eFrodoKEM-1344-AES
+	// HQC-128
+	// HQC-192
+	// HQC-256
+	// mceliece348864
+	// mceliece348864f
+	// mceliece460896
+	// mceliece460896f
+	// mceliece6688128
+	// mceliece6688128f
+	// mceliece6960119
+	// mceliece6960119f
 	// Kyber512
 	// Kyber768
 	// Kyber1024
 	// ML-KEM-512
 	// ML-KEM-768
 	// ML-KEM-1024
+	// sntrup653
+	// sntrup761
+	// sntrup857
+	// sntrup953
+	// sntrup1013
+	// sntrup1277
 	// Kyber512-X25519
 	// Kyber768-X25519
 	// Kyber768-X448
 	// Kyber1024-X448
 	// P256Kyber768Draft00
 	// X25519MLKEM768
+	// SecP256r1MLKEM768
+	// SecP384r1MLKEM1024
+	// sntrup761x25519-sha512
 	// X-Wing
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	cryptoRand "crypto/rand"
 	"crypto/subtle"
+	"encoding/asn1"
 
 	"github.com/cloudflare/circl/kem"
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
@@ -27,6 +28,12 @@ func (scheme) CiphertextSize() int        { return CiphertextSize }
 func (*PrivateKey) Scheme() kem.Scheme    { return scheme{} }
 func (*PublicKey) Scheme() kem.Scheme     { return scheme{} }
 
+// Oid returns the object identifier id-XWing of the draft, used for both
+// public and private keys.
+func (scheme) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 62253, 25722}
+}
+
 func (sch scheme) Encapsulate(pk kem.PublicKey) (ct, ss []byte, err error) {
 	var seed [EncapsulationSeedSize]byte
 	_, err = cryptoRand.Read(seed[:])
@@ -0,0 +1,157 @@
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// RevocationList is a certificate revocation list signed with a signature
+// scheme of the sign package.
+//
+// The embedded x509.RevocationList describes its contents, except for the
+// signature algorithm, which is given by SignatureScheme.
+type RevocationList struct {
+	*x509.RevocationList
+
+	// SignatureScheme is the scheme with which the issuer signed the list.
+	SignatureScheme sign.Scheme
+}
+
+type tbsCertificateList struct {
+	Version             int
+	Signature           pkix.AlgorithmIdentifier
+	Issuer              asn1.RawValue
+	ThisUpdate          time.Time
+	NextUpdate          time.Time            `asn1:"optional"`
+	RevokedCertificates []revokedCertificate `asn1:"optional,omitempty"`
+	Extensions          []pkix.Extension     `asn1:"tag:0,optional,explicit"`
+}
+
+type revokedCertificate struct {
+	SerialNumber   *big.Int
+	RevocationTime time.Time
+	Extensions     []pkix.Extension `asn1:"optional,omitempty"`
+}
+
+// CreateRevocationList returns a DER-encoded X.509 v2 certificate
+// revocation list based on template, signed with priv for issuer.
+//
+// As crypto/x509 does, it uses the following fields of template:
+// RevokedCertificateEntries, Number, ThisUpdate, NextUpdate and
+// ExtraExtensions. The issuer must have a subject key identifier, and be
+// allowed to sign revocation lists.
+func CreateRevocationList(
+	template *x509.RevocationList,
+	issuer *Certificate,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	if template.Number == nil {
+		return nil, errors.New("template contains nil Number field")
+	}
+	if template.Number.Sign() < 0 || len(template.Number.Bytes()) > 20 {
+		return nil, errors.New("invalid CRL number")
+	}
+	if issuer.KeyUsage != 0 && issuer.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return nil, errors.New("issuer must have the crlSign key usage bit set")
+	}
+	if len(issuer.SubjectKeyId) == 0 {
+		return nil, errors.New("issuer certificate doesn't contain a subject key identifier")
+	}
+	if template.NextUpdate.Before(template.ThisUpdate) {
+		return nil, errors.New("template.ThisUpdate is after template.NextUpdate")
+	}
+	if !issuer.PublicKey.Equal(priv.Public()) {
+		return nil, errors.New("private key does not match the issuer's public key")
+	}
+
+	algorithm, err := signatureAlgorithm(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked []revokedCertificate
+	for _, entry := range template.RevokedCertificateEntries {
+		if entry.SerialNumber == nil {
+			return nil, errors.New("revoked certificate entry contains nil SerialNumber")
+		}
+		var exts []pkix.Extension
+		if entry.ReasonCode != 0 {
+			value, err := asn1.Marshal(asn1.Enumerated(entry.ReasonCode))
+			if err != nil {
+				return nil, err
+			}
+			exts = append(exts, pkix.Extension{
+				Id: oidExtensionReasonCode, Value: value,
+			})
+		}
+		exts = append(exts, entry.ExtraExtensions...)
+		revoked = append(revoked, revokedCertificate{
+			SerialNumber:   entry.SerialNumber,
+			RevocationTime: entry.RevocationTime.UTC().Truncate(time.Second),
+			Extensions:     exts,
+		})
+	}
+
+	aki, err := asn1.Marshal(authKeyID{issuer.SubjectKeyId})
+	if err != nil {
+		return nil, err
+	}
+	number, err := asn1.Marshal(template.Number)
+	if err != nil {
+		return nil, err
+	}
+	exts := []pkix.Extension{
+		{Id: oidExtensionAuthorityKeyID, Value: aki},
+		{Id: oidExtensionCRLNumber, Value: number},
+	}
+	exts = append(exts, template.ExtraExtensions...)
+
+	tbs, err := asn1.Marshal(tbsCertificateList{
+		Version:             1,
+		Signature:           algorithm,
+		Issuer:              asn1.RawValue{FullBytes: issuer.RawSubject},
+		ThisUpdate:          template.ThisUpdate.UTC().Truncate(time.Second),
+		NextUpdate:          template.NextUpdate.UTC().Truncate(time.Second),
+		RevokedCertificates: revoked,
+		Extensions:          exts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signTBS(tbs, algorithm, priv)
+}
+
+// ParseRevocationList parses a DER-encoded certificate revocation list. Its
+// signature algorithm must be supported by a scheme of the register.
+func ParseRevocationList(der []byte) (*RevocationList, error) {
+	rl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := parseSignatureAlgorithm(der, true)
+	if err != nil {
+		return nil, err
+	}
+	return &RevocationList{rl, scheme}, nil
+}
+
+// CheckSignatureFrom verifies that the signature on rl is a valid signature
+// from parent, and that parent is allowed to sign revocation lists.
+func (rl *RevocationList) CheckSignatureFrom(parent *Certificate) error {
+	if parent.Version == 3 && !parent.BasicConstraintsValid ||
+		parent.BasicConstraintsValid && !parent.IsCA {
+		return x509.ConstraintViolationError{}
+	}
+	if parent.KeyUsage != 0 && parent.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return x509.ConstraintViolationError{}
+	}
+	return checkSignature(
+		rl.SignatureScheme, parent.PublicKey, rl.RawTBSRevocationList, rl.Signature)
+}
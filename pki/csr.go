@@ -0,0 +1,145 @@
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+var oidExtensionRequest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+
+// CertificateRequest is a PKCS #10 certificate signing request signed with
+// a signature scheme of the sign package.
+//
+// The embedded x509.CertificateRequest describes its contents, except for
+// the public key and the signature algorithm, which are given by PublicKey
+// and SignatureScheme.
+type CertificateRequest struct {
+	*x509.CertificateRequest
+
+	// PublicKey is the public key of the subject.
+	PublicKey sign.PublicKey
+
+	// SignatureScheme is the scheme of the public key, with which the
+	// request is signed.
+	SignatureScheme sign.Scheme
+}
+
+type tbsCertificateRequest struct {
+	Version    int
+	Subject    asn1.RawValue
+	PublicKey  asn1.RawValue
+	Attributes []asn1.RawValue `asn1:"tag:0"`
+}
+
+type extensionRequest struct {
+	Type   asn1.ObjectIdentifier
+	Values [][]pkix.Extension `asn1:"set"`
+}
+
+// CreateCertificateRequest returns a DER-encoded certificate signing
+// request based on template, signed with priv, whose public key is the one
+// of the request.
+//
+// It uses the following fields of template: Subject, DNSNames,
+// EmailAddresses, IPAddresses, URIs and ExtraExtensions, which are all
+// requested as extensions. ExtraExtensions take precedence over the
+// subject alternative names.
+func CreateCertificateRequest(
+	template *x509.CertificateRequest,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	algorithm, err := signatureAlgorithm(priv)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := priv.Public().(sign.PublicKey)
+	if !ok {
+		return nil, sign.ErrTypeMismatch
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := template.RawSubject
+	if len(subject) == 0 {
+		subject, err = asn1.Marshal(template.Subject.ToRDNSequence())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var exts []pkix.Extension
+	if len(template.DNSNames) > 0 || len(template.EmailAddresses) > 0 ||
+		len(template.IPAddresses) > 0 || len(template.URIs) > 0 {
+		hasSAN := false
+		for i := range template.ExtraExtensions {
+			hasSAN = hasSAN ||
+				template.ExtraExtensions[i].Id.Equal(oidExtensionSubjectAltName)
+		}
+		if !hasSAN {
+			value, err := marshalGeneralNames(template.DNSNames,
+				template.EmailAddresses, template.IPAddresses, template.URIs)
+			if err != nil {
+				return nil, err
+			}
+			exts = append(exts, pkix.Extension{
+				Id: oidExtensionSubjectAltName, Value: value,
+			})
+		}
+	}
+	exts = append(exts, template.ExtraExtensions...)
+
+	attributes := []asn1.RawValue{}
+	if len(exts) > 0 {
+		b, err := asn1.Marshal(extensionRequest{
+			Type:   oidExtensionRequest,
+			Values: [][]pkix.Extension{exts},
+		})
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, asn1.RawValue{FullBytes: b})
+	}
+
+	tbs, err := asn1.Marshal(tbsCertificateRequest{
+		Version:    0,
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  asn1.RawValue{FullBytes: spki},
+		Attributes: attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signTBS(tbs, algorithm, priv)
+}
+
+// ParseCertificateRequest parses a DER-encoded certificate signing request.
+// Its public key and its signature algorithm must be supported by schemes
+// of the register.
+func ParseCertificateRequest(der []byte) (*CertificateRequest, error) {
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := parseSignatureAlgorithm(der, false)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := UnmarshalPKIXPublicKey(csr.RawSubjectPublicKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateRequest{csr, pub, scheme}, nil
+}
+
+// CheckSignature verifies that the signature on c is valid, which proves
+// possession of the private key.
+func (c *CertificateRequest) CheckSignature() error {
+	return checkSignature(
+		c.SignatureScheme, c.PublicKey, c.RawTBSCertificateRequest, c.Signature)
+}
@@ -0,0 +1,250 @@
+package pki
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	"github.com/cloudflare/circl/kem"
+	kemschemes "github.com/cloudflare/circl/kem/schemes"
+
+	"golang.org/x/crypto/cryptobyte"
+	casn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// KEM schemes are supported when they implement CertificateScheme. These
+// are ML-KEM, with the object identifiers of NIST, and X-Wing.
+var allKEMSchemesByOID map[string]kem.Scheme
+
+func init() {
+	allKEMSchemesByOID = make(map[string]kem.Scheme)
+	for _, scheme := range kemschemes.All() {
+		if cert, ok := scheme.(CertificateScheme); ok {
+			allKEMSchemesByOID[cert.Oid().String()] = scheme
+		}
+	}
+}
+
+func KEMSchemeByOid(oid asn1.ObjectIdentifier) kem.Scheme {
+	return allKEMSchemesByOID[oid.String()]
+}
+
+// kemSeeded is implemented by KEM private keys that retain the seed from
+// which they were derived.
+type kemSeeded interface {
+	Seed() []byte
+}
+
+func isMLKEM(scheme kem.Scheme) bool {
+	return strings.HasPrefix(scheme.Name(), "ML-KEM-")
+}
+
+func UnmarshalPEMKEMPublicKey(data []byte) (kem.PublicKey, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data")
+	}
+	if !strings.HasSuffix(block.Type, "PUBLIC KEY") {
+		return nil, errors.New("pem block type is not public key")
+	}
+
+	return UnmarshalPKIXKEMPublicKey(block.Bytes)
+}
+
+// UnmarshalPKIXKEMPublicKey parses a KEM public key in the
+// SubjectPublicKeyInfo format.
+func UnmarshalPKIXKEMPublicKey(data []byte) (kem.PublicKey, error) {
+	var spki struct {
+		Raw       asn1.RawContent
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(data, &spki); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data")
+	}
+	if len(spki.Algorithm.Parameters.FullBytes) != 0 {
+		return nil, errors.New("unexpected public key algorithm parameters")
+	}
+	scheme := KEMSchemeByOid(spki.Algorithm.Algorithm)
+	if scheme == nil {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+	return scheme.UnmarshalBinaryPublicKey(spki.PublicKey.RightAlign())
+}
+
+func UnmarshalPEMKEMPrivateKey(data []byte) (kem.PrivateKey, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data")
+	}
+	if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return nil, errors.New("pem block type is not private key")
+	}
+
+	return UnmarshalPKIXKEMPrivateKey(block.Bytes)
+}
+
+// UnmarshalPKIXKEMPrivateKey parses a KEM private key in the PKCS #8
+// format.
+//
+// ML-KEM private keys may be given by their seed, by their expanded form,
+// or by both, in which case they must match, as in
+// draft-ietf-lamps-kyber-certificates. X-Wing private keys are given by
+// their seed.
+func UnmarshalPKIXKEMPrivateKey(data []byte) (kem.PrivateKey, error) {
+	var pkix pkixPrivKey
+	if rest, err := asn1.Unmarshal(data, &pkix); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, errors.New("trailing data")
+	}
+	scheme := KEMSchemeByOid(pkix.Algorithm.Algorithm)
+	if scheme == nil {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+
+	if !isMLKEM(scheme) {
+		return scheme.UnmarshalBinaryPrivateKey(pkix.PrivateKey)
+	}
+
+	// ML-KEM-PrivateKey ::= CHOICE {
+	//   seed [0] IMPLICIT OCTET STRING (SIZE (64)),
+	//   expandedKey OCTET STRING,
+	//   both SEQUENCE { seed OCTET STRING, expandedKey OCTET STRING } }
+	ss := cryptobyte.String(pkix.PrivateKey)
+	var seed, expanded cryptobyte.String
+	switch {
+	case ss.PeekASN1Tag(casn1.Tag(0).ContextSpecific()):
+		if !ss.ReadASN1(&seed, casn1.Tag(0).ContextSpecific()) {
+			return nil, errors.New("truncated seed")
+		}
+	case ss.PeekASN1Tag(casn1.OCTET_STRING):
+		if !ss.ReadASN1(&expanded, casn1.OCTET_STRING) {
+			return nil, errors.New("truncated expanded private key")
+		}
+	default:
+		var both cryptobyte.String
+		if !ss.ReadASN1(&both, casn1.SEQUENCE) ||
+			!both.ReadASN1(&seed, casn1.OCTET_STRING) ||
+			!both.ReadASN1(&expanded, casn1.OCTET_STRING) ||
+			!both.Empty() {
+			return nil, errors.New("malformed private key")
+		}
+	}
+	if !ss.Empty() {
+		return nil, errors.New("trailing data")
+	}
+
+	if seed == nil {
+		return scheme.UnmarshalBinaryPrivateKey(expanded)
+	}
+	if len(seed) != scheme.SeedSize() {
+		return nil, errors.New("incorrect seed size")
+	}
+	_, sk := scheme.DeriveKeyPair(seed)
+	if expanded != nil {
+		sk2, err := scheme.UnmarshalBinaryPrivateKey(expanded)
+		if err != nil {
+			return nil, err
+		}
+		if !sk2.Equal(sk) {
+			return nil, errors.New("mismatching seed and expanded private key")
+		}
+	}
+	return sk, nil
+}
+
+func MarshalPEMKEMPublicKey(pk kem.PublicKey) ([]byte, error) {
+	data, err := MarshalPKIXKEMPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	str := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: data,
+	})
+	return str, nil
+}
+
+// MarshalPKIXKEMPublicKey encodes a KEM public key in the
+// SubjectPublicKeyInfo format.
+func MarshalPKIXKEMPublicKey(pk kem.PublicKey) ([]byte, error) {
+	scheme, ok := pk.Scheme().(CertificateScheme)
+	if !ok {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+	data, err := pk.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct {
+		pkix.AlgorithmIdentifier
+		asn1.BitString
+	}{
+		pkix.AlgorithmIdentifier{Algorithm: scheme.Oid()},
+		asn1.BitString{Bytes: data, BitLength: len(data) * 8},
+	})
+}
+
+// MarshalPEMKEMPrivateKey encodes a KEM private key in the PKCS #8 format
+// within a PEM block of type PRIVATE KEY, as OpenSSL does.
+func MarshalPEMKEMPrivateKey(sk kem.PrivateKey) ([]byte, error) {
+	data, err := MarshalPKIXKEMPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	str := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: data,
+	})
+	return str, nil
+}
+
+// MarshalPKIXKEMPrivateKey encodes a KEM private key in the PKCS #8 format.
+//
+// ML-KEM private keys are encoded by their seed if they retain it, and by
+// their expanded form otherwise.
+func MarshalPKIXKEMPrivateKey(sk kem.PrivateKey) ([]byte, error) {
+	scheme, ok := sk.Scheme().(CertificateScheme)
+	if !ok {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if !isMLKEM(sk.Scheme()) {
+		data, err = sk.MarshalBinary()
+	} else if s, ok := sk.(kemSeeded); ok && s.Seed() != nil {
+		var b cryptobyte.Builder
+		b.AddASN1(casn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(s.Seed())
+		})
+		data, err = b.Bytes()
+	} else {
+		data, err = sk.MarshalBinary()
+		if err == nil {
+			data, err = asn1.Marshal(data)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(pkixPrivKey{
+		0,
+		pkix.AlgorithmIdentifier{Algorithm: scheme.Oid()},
+		data,
+	})
+}
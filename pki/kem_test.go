@@ -0,0 +1,164 @@
+package pki_test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	kemschemes "github.com/cloudflare/circl/kem/schemes"
+	"github.com/cloudflare/circl/pki"
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+func TestKEMPEM(t *testing.T) {
+	supported := 0
+	for _, scheme := range kemschemes.All() {
+		if _, ok := scheme.(pki.CertificateScheme); !ok {
+			continue
+		}
+		supported++
+		t.Run(scheme.Name(), func(t *testing.T) {
+			pk, sk, err := scheme.GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair failed")
+
+			packedPk, err := pki.MarshalPEMKEMPublicKey(pk)
+			test.CheckNoErr(t, err, "MarshalPEMKEMPublicKey failed")
+			pk2, err := pki.UnmarshalPEMKEMPublicKey(packedPk)
+			test.CheckNoErr(t, err, "UnmarshalPEMKEMPublicKey failed")
+			test.CheckOk(pk.Equal(pk2), "public key mismatch", t)
+
+			packedSk, err := pki.MarshalPEMKEMPrivateKey(sk)
+			test.CheckNoErr(t, err, "MarshalPEMKEMPrivateKey failed")
+			sk2, err := pki.UnmarshalPEMKEMPrivateKey(packedSk)
+			test.CheckNoErr(t, err, "UnmarshalPEMKEMPrivateKey failed")
+			test.CheckOk(sk.Equal(sk2), "private key mismatch", t)
+
+			// Signature keys are not KEM keys.
+			_, err = pki.UnmarshalPEMPublicKey(packedPk)
+			test.CheckIsErr(t, err, "KEM key parsed as signature key")
+		})
+	}
+	test.CheckOk(supported == 4, "expected ML-KEM and X-Wing", t)
+
+	pk, _, err := kemschemes.ByName("Kyber768").GenerateKeyPair()
+	test.CheckNoErr(t, err, "GenerateKeyPair failed")
+	_, err = pki.MarshalPKIXKEMPublicKey(pk)
+	test.CheckIsErr(t, err, "Kyber768 has no object identifier")
+}
+
+func mlkemPrivateKey(t *testing.T, choice []byte) []byte {
+	t.Helper()
+	der, err := asn1.Marshal(struct {
+		Version    int
+		Algorithm  asn1.RawValue
+		PrivateKey []byte
+	}{
+		0,
+		asn1.RawValue{FullBytes: []byte{
+			0x30, 0x0b, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x04, 0x02,
+		}},
+		choice,
+	})
+	test.CheckNoErr(t, err, "Marshal failed")
+	return der
+}
+
+func TestMLKEMPrivateKeyFormats(t *testing.T) {
+	scheme := kemschemes.ByName("ML-KEM-768")
+	seed := make([]byte, 64)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	pk, sk := scheme.DeriveKeyPair(seed)
+	expanded, err := sk.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+
+	// The encodings of the public key and of the seed-only private key
+	// start with the same bytes as in draft-ietf-lamps-kyber-certificates.
+	spki, err := pki.MarshalPKIXKEMPublicKey(pk)
+	test.CheckNoErr(t, err, "MarshalPKIXKEMPublicKey failed")
+	want, _ := hex.DecodeString("308204b2300b0609608648016503040402038204a100")
+	test.CheckOk(bytes.HasPrefix(spki, want), "wrong SubjectPublicKeyInfo", t)
+
+	seedOnly := append([]byte{0x80, 0x40}, seed...)
+	der := mlkemPrivateKey(t, seedOnly)
+	want, _ = hex.DecodeString("3054020100300b06096086480165030404020442804000010203")
+	test.CheckOk(bytes.HasPrefix(der, want), "wrong seed-only private key", t)
+
+	expandedOnly, _ := asn1.Marshal(expanded)
+	both, _ := asn1.Marshal(struct{ Seed, Expanded []byte }{seed, expanded})
+	for _, choice := range [][]byte{seedOnly, expandedOnly, both} {
+		sk2, err := pki.UnmarshalPKIXKEMPrivateKey(mlkemPrivateKey(t, choice))
+		test.CheckNoErr(t, err, "UnmarshalPKIXKEMPrivateKey failed")
+		test.CheckOk(sk.Equal(sk2), "private key mismatch", t)
+	}
+
+	// Keys that retain their seed are encoded by it.
+	der, err = pki.MarshalPKIXKEMPrivateKey(sk)
+	test.CheckNoErr(t, err, "MarshalPKIXKEMPrivateKey failed")
+	test.CheckOk(bytes.Equal(der, mlkemPrivateKey(t, seedOnly)),
+		"wrong encoding of seed-only private key", t)
+
+	// Without the seed, the expanded form is used.
+	sk2, err := scheme.UnmarshalBinaryPrivateKey(expanded)
+	test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey failed")
+	der, err = pki.MarshalPKIXKEMPrivateKey(sk2)
+	test.CheckNoErr(t, err, "MarshalPKIXKEMPrivateKey failed")
+	test.CheckOk(bytes.Equal(der, mlkemPrivateKey(t, expandedOnly)),
+		"wrong encoding of expanded private key", t)
+
+	otherSeed := bytes.Clone(seed)
+	otherSeed[0] ^= 1
+	mismatch, _ := asn1.Marshal(struct{ Seed, Expanded []byte }{otherSeed, expanded})
+	for _, choice := range [][]byte{
+		mismatch,
+		seedOnly[:len(seedOnly)-1],
+		append([]byte{0x80, 0x3f}, seed[:63]...),
+		append(bytes.Clone(seedOnly), 0),
+		expandedOnly[:len(expandedOnly)-1],
+	} {
+		_, err := pki.UnmarshalPKIXKEMPrivateKey(mlkemPrivateKey(t, choice))
+		test.CheckIsErr(t, err, "malformed private key accepted")
+	}
+}
+
+func TestKEMCertificate(t *testing.T) {
+	ca := issue(t, schemes.ByName("ML-DSA-65"), caTemplate("root", 1), nil)
+	for _, name := range []string{"ML-KEM-768", "X-Wing"} {
+		t.Run(name, func(t *testing.T) {
+			pk, _, err := kemschemes.ByName(name).GenerateKeyPair()
+			test.CheckNoErr(t, err, "GenerateKeyPair failed")
+
+			template := leafTemplate()
+			template.KeyUsage = 0
+			der, err := pki.CreateKEMCertificate(template, ca.cert.Certificate, pk, ca.priv)
+			test.CheckNoErr(t, err, "CreateKEMCertificate failed")
+			cert, err := pki.ParseCertificate(der)
+			test.CheckNoErr(t, err, "ParseCertificate failed")
+			test.CheckOk(cert.PublicKey == nil && cert.KEMPublicKey.Equal(pk),
+				"wrong public key", t)
+			test.CheckOk(cert.KeyUsage == x509.KeyUsageKeyEncipherment,
+				"wrong key usage", t)
+
+			roots := pki.NewCertPool()
+			roots.AddCert(ca.cert)
+			_, err = cert.Verify(pki.VerifyOptions{
+				DNSName:     "example.com",
+				Roots:       roots,
+				CurrentTime: now,
+			})
+			test.CheckNoErr(t, err, "Verify failed")
+
+			// A KEM key cannot sign certificates.
+			template.IsCA = true
+			template.BasicConstraintsValid = true
+			template.KeyUsage = x509.KeyUsageCertSign
+			_, err = pki.CreateKEMCertificate(template, ca.cert.Certificate, pk, ca.priv)
+			test.CheckIsErr(t, err, "KEM key accepted for a CA")
+			test.CheckIsErr(t, ca.cert.CheckSignatureFrom(cert), "KEM key verified a signature")
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package pki
 
 import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
@@ -8,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/ecdsa"
 	"github.com/cloudflare/circl/sign/schemes"
 
 	"golang.org/x/crypto/cryptobyte"
@@ -17,6 +20,10 @@ import (
 var (
 	allSchemesByOID map[string]sign.Scheme
 	allSchemesByTLS map[uint]sign.Scheme
+
+	// ECDSA keys are identified by id-ecPublicKey and a named curve rather
+	// than by the OID of the signature algorithm, see RFC 5480.
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
 )
 
 type pkixPrivKey struct {
@@ -46,7 +53,9 @@ func SchemeByTLSID(id uint) sign.Scheme { return allSchemesByTLS[id] }
 type CertificateScheme interface {
 	// Return the appropriate OIDs for this instance.  It is implicitly
 	// assumed that the encoding is simple: e.g. uses the same OID for
-	// signature and public key like Ed25519.
+	// signature and public key like Ed25519. ECDSA is the exception: Oid
+	// returns the signature algorithm, and keys are handled separately.
+	// KEM schemes implement it with the OID of their keys.
 	Oid() asn1.ObjectIdentifier
 }
 
@@ -78,6 +87,9 @@ func UnmarshalPKIXPublicKey(data []byte) (sign.PublicKey, error) {
 	} else if len(rest) != 0 {
 		return nil, errors.New("trailing data")
 	}
+	if pkix.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return unmarshalECDSAPublicKey(data)
+	}
 	scheme := SchemeByOid(pkix.Algorithm.Algorithm)
 	if scheme == nil {
 		return nil, errors.New("unsupported public key algorithm")
@@ -85,6 +97,19 @@ func UnmarshalPKIXPublicKey(data []byte) (sign.PublicKey, error) {
 	return scheme.UnmarshalBinaryPublicKey(pkix.PublicKey.RightAlign())
 }
 
+// unmarshalECDSAPublicKey parses an ECDSA public key with crypto/x509.
+func unmarshalECDSAPublicKey(data []byte) (sign.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*stdecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+	return ecdsa.NewPublicKey(pub)
+}
+
 func UnmarshalPEMPrivateKey(data []byte) (sign.PrivateKey, error) {
 	block, rest := pem.Decode(data)
 	if len(rest) != 0 {
@@ -94,6 +119,15 @@ func UnmarshalPEMPrivateKey(data []byte) (sign.PrivateKey, error) {
 		return nil, errors.New("pem block type is not private key")
 	}
 
+	// ECDSA private keys are also found in the format of SEC 1.
+	if block.Type == "EC PRIVATE KEY" {
+		priv, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.NewPrivateKey(priv)
+	}
+
 	return UnmarshalPKIXPrivateKey(block.Bytes)
 }
 
@@ -102,6 +136,12 @@ func isMLDSA(scheme sign.Scheme) bool {
 	return name == "ML-DSA-44" || name == "ML-DSA-65" || name == "ML-DSA-87"
 }
 
+// isSLHDSA returns whether the private keys of scheme are stored without
+// an additional OCTET STRING, as for SLH-DSA.
+func isSLHDSA(scheme sign.Scheme) bool {
+	return strings.HasPrefix(scheme.Name(), "SLH-DSA-")
+}
+
 func UnmarshalPKIXPrivateKey(data []byte) (sign.PrivateKey, error) {
 	var pkix pkixPrivKey
 	if rest, err := asn1.Unmarshal(data, &pkix); err != nil {
@@ -109,6 +149,9 @@ func UnmarshalPKIXPrivateKey(data []byte) (sign.PrivateKey, error) {
 	} else if len(rest) != 0 {
 		return nil, errors.New("trailing data")
 	}
+	if pkix.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return unmarshalECDSAPrivateKey(data)
+	}
 	scheme := SchemeByOid(pkix.Algorithm.Algorithm)
 	if scheme == nil {
 		return nil, errors.New("unsupported public key algorithm")
@@ -165,6 +208,10 @@ func UnmarshalPKIXPrivateKey(data []byte) (sign.PrivateKey, error) {
 		return sk, nil
 	}
 
+	if isSLHDSA(scheme) {
+		return scheme.UnmarshalBinaryPrivateKey(pkix.PrivateKey)
+	}
+
 	var sk []byte
 	if rest, err := asn1.Unmarshal(pkix.PrivateKey, &sk); err != nil {
 		return nil, err
@@ -174,6 +221,19 @@ func UnmarshalPKIXPrivateKey(data []byte) (sign.PrivateKey, error) {
 	return scheme.UnmarshalBinaryPrivateKey(sk)
 }
 
+// unmarshalECDSAPrivateKey parses an ECDSA private key with crypto/x509.
+func unmarshalECDSAPrivateKey(data []byte) (sign.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*stdecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("unsupported public key algorithm")
+	}
+	return ecdsa.NewPrivateKey(priv)
+}
+
 func MarshalPEMPublicKey(pk sign.PublicKey) ([]byte, error) {
 	data, err := MarshalPKIXPublicKey(pk)
 	if err != nil {
@@ -187,6 +247,10 @@ func MarshalPEMPublicKey(pk sign.PublicKey) ([]byte, error) {
 }
 
 func MarshalPKIXPublicKey(pk sign.PublicKey) ([]byte, error) {
+	if k, ok := pk.(*ecdsa.PublicKey); ok {
+		return x509.MarshalPKIXPublicKey(k.ECDSA())
+	}
+
 	data, err := pk.MarshalBinary()
 	if err != nil {
 		return nil, err
@@ -221,6 +285,10 @@ func MarshalPEMPrivateKey(sk sign.PrivateKey) ([]byte, error) {
 }
 
 func MarshalPKIXPrivateKey(sk sign.PrivateKey) ([]byte, error) {
+	if k, ok := sk.(*ecdsa.PrivateKey); ok {
+		return x509.MarshalPKCS8PrivateKey(k.ECDSA())
+	}
+
 	var (
 		data []byte
 		err  error
@@ -247,9 +315,11 @@ func MarshalPKIXPrivateKey(sk sign.PrivateKey) ([]byte, error) {
 			return nil, err
 		}
 
-		data, err = asn1.Marshal(data)
-		if err != nil {
-			return nil, err
+		if !isSLHDSA(scheme) {
+			data, err = asn1.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -2,6 +2,11 @@ package pki_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"testing"
@@ -113,3 +118,65 @@ func TestMLDSA(t *testing.T) {
 
 	testMLDSASad(t, "bad-ML-DSA-44-1.priv.gz")
 }
+
+func TestECDSA(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		curve elliptic.Curve
+	}{
+		{"ECDSA-P256-SHA256", elliptic.P256()},
+		{"ECDSA-P384-SHA384", elliptic.P384()},
+		{"ECDSA-P521-SHA512", elliptic.P521()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := schemes.ByName(tc.name)
+			if scheme == nil {
+				t.Fatal("scheme not found")
+			}
+			if pki.SchemeByOid(scheme.(pki.CertificateScheme).Oid()) != scheme {
+				t.Fatal("scheme not found by OID")
+			}
+			if pki.SchemeByTLSID(scheme.(pki.TLSScheme).TLSIdentifier()) != scheme {
+				t.Fatal("scheme not found by TLS identifier")
+			}
+
+			// Keys encoded by crypto/x509.
+			priv, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			test.CheckNoErr(t, err, "GenerateKey failed")
+			pkcs8, err := x509.MarshalPKCS8PrivateKey(priv)
+			test.CheckNoErr(t, err, "MarshalPKCS8PrivateKey failed")
+			sec1, err := x509.MarshalECPrivateKey(priv)
+			test.CheckNoErr(t, err, "MarshalECPrivateKey failed")
+			spki, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+			test.CheckNoErr(t, err, "MarshalPKIXPublicKey failed")
+
+			var sks []sign.PrivateKey
+			for _, block := range []*pem.Block{
+				{Type: "PRIVATE KEY", Bytes: pkcs8},
+				{Type: "EC PRIVATE KEY", Bytes: sec1},
+			} {
+				sk, err := pki.UnmarshalPEMPrivateKey(pem.EncodeToMemory(block))
+				test.CheckNoErr(t, err, "UnmarshalPEMPrivateKey failed")
+				sks = append(sks, sk)
+			}
+			test.CheckOk(sks[0].Equal(sks[1]), "private keys differ", t)
+			test.CheckOk(sks[0].Scheme() == scheme, "wrong scheme", t)
+
+			pk, err := pki.UnmarshalPEMPublicKey(pem.EncodeToMemory(
+				&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+			test.CheckNoErr(t, err, "UnmarshalPEMPublicKey failed")
+			test.CheckOk(pk.Equal(sks[0].Public()), "public keys differ", t)
+
+			b, err := pki.MarshalPKIXPublicKey(pk)
+			test.CheckNoErr(t, err, "MarshalPKIXPublicKey failed")
+			test.CheckOk(bytes.Equal(b, spki), "public key encoding differs", t)
+			b, err = pki.MarshalPKIXPrivateKey(sks[1])
+			test.CheckNoErr(t, err, "MarshalPKIXPrivateKey failed")
+			test.CheckOk(bytes.Equal(b, pkcs8), "private key encoding differs", t)
+
+			msg := []byte("message")
+			sig := scheme.Sign(sks[0], msg, nil)
+			test.CheckOk(scheme.Verify(pk, msg, sig, nil), "Verify failed", t)
+		})
+	}
+}
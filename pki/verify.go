@@ -0,0 +1,255 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"slices"
+	"time"
+)
+
+// maxChainLength bounds the number of certificates of a chain.
+const maxChainLength = 16
+
+// CertPool is a set of certificates.
+type CertPool struct {
+	bySubject map[string][]*Certificate
+}
+
+// NewCertPool returns an empty CertPool.
+func NewCertPool() *CertPool {
+	return &CertPool{bySubject: make(map[string][]*Certificate)}
+}
+
+// AddCert adds a certificate to the pool.
+func (p *CertPool) AddCert(cert *Certificate) {
+	if p.contains(cert) {
+		return
+	}
+	key := string(cert.RawSubject)
+	p.bySubject[key] = append(p.bySubject[key], cert)
+}
+
+func (p *CertPool) contains(cert *Certificate) bool {
+	if p == nil {
+		return false
+	}
+	for _, c := range p.bySubject[string(cert.RawSubject)] {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// findParents returns the certificates of the pool that may have issued
+// cert: their subject is the issuer of cert, and their subject key
+// identifier is its authority key identifier when both are present.
+func (p *CertPool) findParents(cert *Certificate) []*Certificate {
+	if p == nil {
+		return nil
+	}
+	var parents []*Certificate
+	for _, c := range p.bySubject[string(cert.RawIssuer)] {
+		if len(c.SubjectKeyId) > 0 && len(cert.AuthorityKeyId) > 0 &&
+			!bytes.Equal(c.SubjectKeyId, cert.AuthorityKeyId) {
+			continue
+		}
+		parents = append(parents, c)
+	}
+	return parents
+}
+
+// VerifyOptions are the parameters of chain verification.
+type VerifyOptions struct {
+	// DNSName, if not empty, is checked against the leaf certificate.
+	DNSName string
+
+	// Intermediates are certificates that may be used to build chains.
+	Intermediates *CertPool
+
+	// Roots are the trust anchors of the chains.
+	Roots *CertPool
+
+	// CurrentTime is the time at which certificates must be valid. If
+	// zero, the current time is used.
+	CurrentTime time.Time
+
+	// KeyUsages are the acceptable extended key usages. If empty,
+	// x509.ExtKeyUsageServerAuth is used, as in crypto/x509. Use
+	// x509.ExtKeyUsageAny to accept any usage.
+	KeyUsages []x509.ExtKeyUsage
+}
+
+// Verify builds the chains from c to a certificate of opts.Roots, through
+// certificates of opts.Intermediates, and returns those that are valid.
+// Each chain starts with c and ends with a root.
+//
+// Certificates must be valid at the current time, and issuers must be
+// allowed to sign certificates and must satisfy the path length
+// constraints. The extended key usages of all certificates of a chain must
+// allow the ones of opts. Certificates with critical extensions that are
+// not handled are rejected. Name constraints are not supported: chains
+// with a certificate that has them are rejected. Policies are not checked.
+//
+// Errors are of the types of crypto/x509 when applicable.
+func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
+	if opts.CurrentTime.IsZero() {
+		opts.CurrentTime = time.Now()
+	}
+	if len(opts.KeyUsages) == 0 {
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+
+	if err := checkCertificate(c, opts.CurrentTime); err != nil {
+		return nil, err
+	}
+	if opts.DNSName != "" {
+		if err := c.VerifyHostname(opts.DNSName); err != nil {
+			return nil, err
+		}
+	}
+
+	var chains [][]*Certificate
+	var err error
+	if opts.Roots.contains(c) {
+		chains = [][]*Certificate{{c}}
+	} else {
+		chains, err = buildChains([]*Certificate{c}, &opts)
+	}
+
+	chains = slices.DeleteFunc(chains, func(chain []*Certificate) bool {
+		return !checkKeyUsages(chain, opts.KeyUsages)
+	})
+	if len(chains) == 0 {
+		if err == nil {
+			err = x509.CertificateInvalidError{
+				Cert:   c.Certificate,
+				Reason: x509.IncompatibleUsage,
+			}
+		}
+		return nil, err
+	}
+	return chains, nil
+}
+
+// buildChains extends chain up to the roots. It returns the error of the
+// last candidate parent if no chain is found.
+func buildChains(chain []*Certificate, opts *VerifyOptions) ([][]*Certificate, error) {
+	cert := chain[len(chain)-1]
+	var chains [][]*Certificate
+	var err error = x509.UnknownAuthorityError{Cert: chain[0].Certificate}
+
+	consider := func(parent *Certificate, isRoot bool) {
+		for _, c := range chain {
+			if c.Equal(parent) {
+				return
+			}
+		}
+		if e := checkParent(cert, parent, len(chain)-1, opts.CurrentTime); e != nil {
+			err = e
+			return
+		}
+
+		extended := append(slices.Clip(chain), parent)
+		if isRoot {
+			chains = append(chains, extended)
+			return
+		}
+		if len(extended) >= maxChainLength {
+			err = x509.CertificateInvalidError{
+				Cert:   parent.Certificate,
+				Reason: x509.TooManyIntermediates,
+			}
+			return
+		}
+		more, e := buildChains(extended, opts)
+		if e != nil {
+			err = e
+		}
+		chains = append(chains, more...)
+	}
+
+	for _, root := range opts.Roots.findParents(cert) {
+		consider(root, true)
+	}
+	for _, intermediate := range opts.Intermediates.findParents(cert) {
+		consider(intermediate, false)
+	}
+
+	if len(chains) > 0 {
+		return chains, nil
+	}
+	return nil, err
+}
+
+// checkParent checks that parent is valid and issued cert, which has the
+// given number of intermediate certificates below.
+func checkParent(cert, parent *Certificate, below int, now time.Time) error {
+	if err := checkCertificate(parent, now); err != nil {
+		return err
+	}
+	if err := cert.CheckSignatureFrom(parent); err != nil {
+		if _, ok := err.(x509.ConstraintViolationError); ok {
+			return x509.CertificateInvalidError{
+				Cert:   parent.Certificate,
+				Reason: x509.NotAuthorizedToSign,
+			}
+		}
+		return err
+	}
+	if parent.BasicConstraintsValid && parent.MaxPathLen >= 0 &&
+		below > parent.MaxPathLen {
+		return x509.CertificateInvalidError{
+			Cert:   parent.Certificate,
+			Reason: x509.TooManyIntermediates,
+		}
+	}
+	return nil
+}
+
+// checkCertificate checks that c is valid at the given time, and has
+// neither unhandled critical extension nor name constraints.
+func checkCertificate(c *Certificate, now time.Time) error {
+	if len(c.UnhandledCriticalExtensions) > 0 {
+		return x509.UnhandledCriticalExtension{}
+	}
+	if slices.ContainsFunc(c.Extensions, func(e pkix.Extension) bool {
+		return e.Id.Equal(oidExtensionNameConstraints)
+	}) {
+		return x509.CertificateInvalidError{
+			Cert:   c.Certificate,
+			Reason: x509.CANotAuthorizedForThisName,
+			Detail: "name constraints are not supported",
+		}
+	}
+	if now.Before(c.NotBefore) || now.After(c.NotAfter) {
+		return x509.CertificateInvalidError{
+			Cert:   c.Certificate,
+			Reason: x509.Expired,
+		}
+	}
+	return nil
+}
+
+// checkKeyUsages returns whether the chain allows one of the extended key
+// usages. Certificates without extended key usages allow any of them.
+func checkKeyUsages(chain []*Certificate, usages []x509.ExtKeyUsage) bool {
+	if slices.Contains(usages, x509.ExtKeyUsageAny) {
+		return true
+	}
+	usages = slices.Clone(usages)
+	for _, c := range chain {
+		if len(c.ExtKeyUsage) == 0 && len(c.UnknownExtKeyUsage) == 0 ||
+			slices.Contains(c.ExtKeyUsage, x509.ExtKeyUsageAny) {
+			continue
+		}
+		usages = slices.DeleteFunc(usages, func(u x509.ExtKeyUsage) bool {
+			return !slices.Contains(c.ExtKeyUsage, u)
+		})
+		if len(usages) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,601 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/sign"
+
+	"golang.org/x/crypto/cryptobyte"
+	casn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var (
+	// ErrUnsupportedAlgorithm is returned for a signature algorithm that
+	// does not correspond to a scheme implementing CertificateScheme.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+
+	// ErrSignature is returned when a signature is not valid.
+	ErrSignature = errors.New("invalid signature")
+
+	errMalformed = errors.New("malformed signed data")
+)
+
+var (
+	oidExtensionSubjectKeyID          = asn1.ObjectIdentifier{2, 5, 29, 14}
+	oidExtensionKeyUsage              = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionSubjectAltName        = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidExtensionBasicConstraints      = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionCRLNumber             = asn1.ObjectIdentifier{2, 5, 29, 20}
+	oidExtensionReasonCode            = asn1.ObjectIdentifier{2, 5, 29, 21}
+	oidExtensionNameConstraints       = asn1.ObjectIdentifier{2, 5, 29, 30}
+	oidExtensionCRLDistributionPoints = asn1.ObjectIdentifier{2, 5, 29, 31}
+	oidExtensionAuthorityKeyID        = asn1.ObjectIdentifier{2, 5, 29, 35}
+	oidExtensionExtendedKeyUsage      = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+// Tags of the GeneralName choices, see Section 4.2.1.6 of RFC 5280.
+const (
+	nameTypeEmail = 1
+	nameTypeDNS   = 2
+	nameTypeURI   = 6
+	nameTypeIP    = 7
+)
+
+var extKeyUsageOIDs = []struct {
+	usage x509.ExtKeyUsage
+	oid   asn1.ObjectIdentifier
+}{
+	{x509.ExtKeyUsageAny, asn1.ObjectIdentifier{2, 5, 29, 37, 0}},
+	{x509.ExtKeyUsageServerAuth, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 1}},
+	{x509.ExtKeyUsageClientAuth, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 2}},
+	{x509.ExtKeyUsageCodeSigning, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 3}},
+	{x509.ExtKeyUsageEmailProtection, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 4}},
+	{x509.ExtKeyUsageIPSECEndSystem, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 5}},
+	{x509.ExtKeyUsageIPSECTunnel, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 6}},
+	{x509.ExtKeyUsageIPSECUser, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 7}},
+	{x509.ExtKeyUsageTimeStamping, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}},
+	{x509.ExtKeyUsageOCSPSigning, asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 9}},
+}
+
+// Certificate is an X.509 certificate signed with a signature scheme of
+// the sign package.
+//
+// The embedded x509.Certificate describes its contents, except for the
+// public key and the signature algorithm, which are given by PublicKey
+// or KEMPublicKey, and SignatureScheme.
+type Certificate struct {
+	*x509.Certificate
+
+	// PublicKey is the public key of the subject, if it is a signature
+	// key.
+	PublicKey sign.PublicKey
+
+	// KEMPublicKey is the public key of the subject, if it is a KEM key.
+	KEMPublicKey kem.PublicKey
+
+	// SignatureScheme is the scheme with which the issuer signed the
+	// certificate.
+	SignatureScheme sign.Scheme
+}
+
+type tbsCertificate struct {
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	Extensions         []pkix.Extension `asn1:"omitempty,optional,explicit,tag:3"`
+}
+
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+// signed is a certificate, a certificate request or a revocation list.
+type signed struct {
+	TBS                asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+type authKeyID struct {
+	ID []byte `asn1:"optional,tag:0"`
+}
+
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+}
+
+type distributionPointName struct {
+	FullName []asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// CreateCertificate returns a DER-encoded X.509 v3 certificate issued by
+// parent and signed with priv, for the public key pub of the subject given
+// by template. The certificate is self-signed if parent is template.
+//
+// As crypto/x509 does, it uses the following fields of template:
+// SerialNumber, Subject, NotBefore, NotAfter, KeyUsage, ExtKeyUsage,
+// UnknownExtKeyUsage, BasicConstraintsValid, IsCA, MaxPathLen,
+// MaxPathLenZero, SubjectKeyId, DNSNames, EmailAddresses, IPAddresses,
+// URIs, CRLDistributionPoints and ExtraExtensions. Other extensions can be
+// given with ExtraExtensions, which take precedence over the ones above.
+//
+// The authority key identifier is taken from parent, and a subject key
+// identifier is generated for CA certificates if template has none.
+func CreateCertificate(
+	template, parent *x509.Certificate,
+	pub sign.PublicKey,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	if _, ok := pub.Scheme().(CertificateScheme); !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	key, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return createCertificate(template, parent, spki, key, priv)
+}
+
+// CreateKEMCertificate is like CreateCertificate for the KEM public key pub.
+// As required for ML-KEM by draft-ietf-lamps-kyber-certificates, template
+// must not be a CA, and the key usage must be key encipherment, which is
+// the default.
+func CreateKEMCertificate(
+	template, parent *x509.Certificate,
+	pub kem.PublicKey,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	if template.IsCA {
+		return nil, errors.New("KEM keys cannot be used by CAs")
+	}
+	if template.KeyUsage != 0 &&
+		template.KeyUsage != x509.KeyUsageKeyEncipherment {
+		return nil, errors.New("KEM keys only allow key encipherment")
+	}
+	spki, err := MarshalPKIXKEMPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	key, err := pub.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	kemTemplate := *template
+	kemTemplate.KeyUsage = x509.KeyUsageKeyEncipherment
+	return createCertificate(&kemTemplate, parent, spki, key, priv)
+}
+
+// createCertificate implements CreateCertificate for the encoded public key
+// spki, whose raw encoding key is used for the subject key identifier.
+func createCertificate(
+	template, parent *x509.Certificate,
+	spki, key []byte,
+	priv sign.PrivateKey,
+) ([]byte, error) {
+	if template.SerialNumber == nil {
+		return nil, errors.New("no SerialNumber given")
+	}
+	if template.SerialNumber.Sign() < 0 {
+		return nil, errors.New("negative SerialNumber given")
+	}
+	if template.BasicConstraintsValid && !template.IsCA &&
+		template.MaxPathLen != -1 &&
+		(template.MaxPathLen != 0 || template.MaxPathLenZero) {
+		return nil, errors.New("only CAs are allowed to specify MaxPathLen")
+	}
+
+	algorithm, err := signatureAlgorithm(priv)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := subjectBytes(parent)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := subjectBytes(template)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectKeyID := template.SubjectKeyId
+	if len(subjectKeyID) == 0 && template.IsCA {
+		// Method 1 of RFC 7093.
+		h := sha256.Sum256(key)
+		subjectKeyID = h[:20]
+	}
+	var authorityKeyID []byte
+	if !bytes.Equal(issuer, subject) {
+		authorityKeyID = parent.SubjectKeyId
+	}
+
+	extensions, err := certificateExtensions(
+		template, subject, subjectKeyID, authorityKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       template.SerialNumber,
+		SignatureAlgorithm: algorithm,
+		Issuer:             asn1.RawValue{FullBytes: issuer},
+		Validity: validity{
+			template.NotBefore.UTC().Truncate(time.Second),
+			template.NotAfter.UTC().Truncate(time.Second),
+		},
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  asn1.RawValue{FullBytes: spki},
+		Extensions: extensions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signTBS(tbs, algorithm, priv)
+}
+
+// ParseCertificate parses a DER-encoded certificate. Its public key and
+// its signature algorithm must be supported by schemes of the registers
+// of signature schemes, or of KEMs for the public key.
+func ParseCertificate(der []byte) (*Certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	scheme, err := parseSignatureAlgorithm(der, true)
+	if err != nil {
+		return nil, err
+	}
+	c := &Certificate{Certificate: cert, SignatureScheme: scheme}
+	if isKEMPublicKey(cert.RawSubjectPublicKeyInfo) {
+		c.KEMPublicKey, err = UnmarshalPKIXKEMPublicKey(cert.RawSubjectPublicKeyInfo)
+	} else {
+		c.PublicKey, err = UnmarshalPKIXPublicKey(cert.RawSubjectPublicKeyInfo)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// isKEMPublicKey returns whether the algorithm of the DER-encoded
+// SubjectPublicKeyInfo is the one of a KEM of the register.
+func isKEMPublicKey(spki []byte) bool {
+	input := cryptobyte.String(spki)
+	var oid asn1.ObjectIdentifier
+	if !input.ReadASN1(&input, casn1.SEQUENCE) ||
+		!input.ReadASN1(&input, casn1.SEQUENCE) ||
+		!input.ReadASN1ObjectIdentifier(&oid) {
+		return false
+	}
+	return KEMSchemeByOid(oid) != nil
+}
+
+// CheckSignatureFrom verifies that the signature on c is a valid signature
+// from parent, and that parent is allowed to sign certificates.
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if parent.Version == 3 && !parent.BasicConstraintsValid ||
+		parent.BasicConstraintsValid && !parent.IsCA {
+		return x509.ConstraintViolationError{}
+	}
+	if parent.KeyUsage != 0 && parent.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return x509.ConstraintViolationError{}
+	}
+	return checkSignature(
+		c.SignatureScheme, parent.PublicKey, c.RawTBSCertificate, c.Signature)
+}
+
+// Equal reports whether c and other are the same certificate.
+func (c *Certificate) Equal(other *Certificate) bool {
+	return c.Certificate.Equal(other.Certificate)
+}
+
+// signatureAlgorithm returns the identifier of the algorithm of priv.
+// Parameters are absent for all the supported schemes.
+func signatureAlgorithm(priv sign.PrivateKey) (pkix.AlgorithmIdentifier, error) {
+	scheme, ok := priv.Scheme().(CertificateScheme)
+	if !ok {
+		return pkix.AlgorithmIdentifier{}, ErrUnsupportedAlgorithm
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: scheme.Oid()}, nil
+}
+
+// signTBS signs the DER-encoded tbs, and returns the DER encoding of the
+// resulting signed structure.
+func signTBS(
+	tbs []byte, algorithm pkix.AlgorithmIdentifier, priv sign.PrivateKey,
+) ([]byte, error) {
+	sig := priv.Scheme().Sign(priv, tbs, nil)
+	return asn1.Marshal(signed{
+		TBS:                asn1.RawValue{FullBytes: tbs},
+		SignatureAlgorithm: algorithm,
+		SignatureValue:     asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+}
+
+// parseSignatureAlgorithm returns the scheme of the signature algorithm of
+// a DER-encoded signed structure. If inner is true, it checks that the
+// algorithm inside the signed part, which is the first SEQUENCE in it, is
+// the same, as required for certificates and revocation lists.
+func parseSignatureAlgorithm(der []byte, inner bool) (sign.Scheme, error) {
+	input := cryptobyte.String(der)
+	var outer, tbs, algorithm cryptobyte.String
+	if !input.ReadASN1(&outer, casn1.SEQUENCE) ||
+		!outer.ReadASN1(&tbs, casn1.SEQUENCE) ||
+		!outer.ReadASN1Element(&algorithm, casn1.SEQUENCE) {
+		return nil, errMalformed
+	}
+
+	if inner {
+		var element cryptobyte.String
+		var tag casn1.Tag
+		for tag != casn1.SEQUENCE {
+			if !tbs.ReadAnyASN1Element(&element, &tag) {
+				return nil, errMalformed
+			}
+		}
+		if !bytes.Equal(element, algorithm) {
+			return nil, errors.New("signature algorithm mismatch")
+		}
+	}
+
+	var oid asn1.ObjectIdentifier
+	if !algorithm.ReadASN1(&algorithm, casn1.SEQUENCE) ||
+		!algorithm.ReadASN1ObjectIdentifier(&oid) {
+		return nil, errMalformed
+	}
+	if !algorithm.Empty() {
+		return nil, errors.New("unexpected signature algorithm parameters")
+	}
+
+	scheme := SchemeByOid(oid)
+	if scheme == nil {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return scheme, nil
+}
+
+// checkSignature verifies a signature with a public key of the scheme.
+func checkSignature(
+	scheme sign.Scheme, pub sign.PublicKey, signed, signature []byte,
+) error {
+	if pub == nil {
+		return errors.New("public key of the issuer is not a signature key")
+	}
+	if pub.Scheme() != scheme {
+		return errors.New("signature algorithm does not match the public key")
+	}
+	if !scheme.Verify(pub, signed, signature, nil) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// subjectBytes returns the DER-encoded subject of the certificate.
+func subjectBytes(cert *x509.Certificate) ([]byte, error) {
+	if len(cert.RawSubject) > 0 {
+		return cert.RawSubject, nil
+	}
+	return asn1.Marshal(cert.Subject.ToRDNSequence())
+}
+
+// certificateExtensions returns the extensions of a certificate given
+// by the template.
+func certificateExtensions(
+	template *x509.Certificate,
+	subject, subjectKeyID, authorityKeyID []byte,
+) ([]pkix.Extension, error) {
+	var exts []pkix.Extension
+	add := func(ext pkix.Extension, err error) error {
+		if err != nil {
+			return err
+		}
+		for i := range template.ExtraExtensions {
+			if template.ExtraExtensions[i].Id.Equal(ext.Id) {
+				return nil
+			}
+		}
+		exts = append(exts, ext)
+		return nil
+	}
+
+	if template.KeyUsage != 0 {
+		if err := add(marshalKeyUsage(template.KeyUsage)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(template.ExtKeyUsage) > 0 || len(template.UnknownExtKeyUsage) > 0 {
+		err := add(marshalExtKeyUsage(
+			template.ExtKeyUsage, template.UnknownExtKeyUsage))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if template.BasicConstraintsValid {
+		maxPathLen := template.MaxPathLen
+		if maxPathLen == 0 && !template.MaxPathLenZero {
+			maxPathLen = -1
+		}
+		value, err := asn1.Marshal(basicConstraints{template.IsCA, maxPathLen})
+		err = add(pkix.Extension{
+			Id: oidExtensionBasicConstraints, Critical: true, Value: value,
+		}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(subjectKeyID) > 0 {
+		value, err := asn1.Marshal(subjectKeyID)
+		err = add(pkix.Extension{Id: oidExtensionSubjectKeyID, Value: value}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(authorityKeyID) > 0 {
+		value, err := asn1.Marshal(authKeyID{authorityKeyID})
+		err = add(pkix.Extension{Id: oidExtensionAuthorityKeyID, Value: value}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(template.DNSNames) > 0 || len(template.EmailAddresses) > 0 ||
+		len(template.IPAddresses) > 0 || len(template.URIs) > 0 {
+		value, err := marshalGeneralNames(template.DNSNames,
+			template.EmailAddresses, template.IPAddresses, template.URIs)
+		// The extension is critical if the subject is empty, see Section
+		// 4.2.1.6 of RFC 5280.
+		err = add(pkix.Extension{
+			Id:       oidExtensionSubjectAltName,
+			Critical: bytes.Equal(subject, emptyName),
+			Value:    value,
+		}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(template.CRLDistributionPoints) > 0 {
+		var points []distributionPoint
+		for _, uri := range template.CRLDistributionPoints {
+			points = append(points, distributionPoint{
+				DistributionPoint: distributionPointName{
+					FullName: []asn1.RawValue{
+						{Tag: nameTypeURI, Class: asn1.ClassContextSpecific, Bytes: []byte(uri)},
+					},
+				},
+			})
+		}
+		value, err := asn1.Marshal(points)
+		err = add(pkix.Extension{Id: oidExtensionCRLDistributionPoints, Value: value}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(exts, template.ExtraExtensions...), nil
+}
+
+// emptyName is the DER encoding of an empty Name.
+var emptyName = []byte{0x30, 0x00}
+
+func marshalKeyUsage(ku x509.KeyUsage) (pkix.Extension, error) {
+	// Bit i of ku is the i-th named bit of the BIT STRING, whose first bit
+	// is the most significant one.
+	var b [2]byte
+	for i := 0; i < 16; i++ {
+		if ku&(1<<i) != 0 {
+			b[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	bitString := b[:1]
+	if b[1] != 0 {
+		bitString = b[:]
+	}
+	bitLength := 8 * len(bitString)
+	for bitLength > 0 && bitString[(bitLength-1)/8]&(0x80>>((bitLength-1)%8)) == 0 {
+		bitLength--
+	}
+
+	value, err := asn1.Marshal(asn1.BitString{Bytes: bitString, BitLength: bitLength})
+	return pkix.Extension{Id: oidExtensionKeyUsage, Critical: true, Value: value}, err
+}
+
+func marshalExtKeyUsage(
+	usages []x509.ExtKeyUsage, unknown []asn1.ObjectIdentifier,
+) (pkix.Extension, error) {
+	oids := make([]asn1.ObjectIdentifier, 0, len(usages)+len(unknown))
+	for _, u := range usages {
+		oid, ok := oidFromExtKeyUsage(u)
+		if !ok {
+			return pkix.Extension{}, errors.New("unknown extended key usage")
+		}
+		oids = append(oids, oid)
+	}
+	oids = append(oids, unknown...)
+
+	value, err := asn1.Marshal(oids)
+	return pkix.Extension{Id: oidExtensionExtendedKeyUsage, Value: value}, err
+}
+
+func oidFromExtKeyUsage(u x509.ExtKeyUsage) (asn1.ObjectIdentifier, bool) {
+	for _, e := range extKeyUsageOIDs {
+		if e.usage == u {
+			return e.oid, true
+		}
+	}
+	return nil, false
+}
+
+// marshalGeneralNames returns the GeneralNames of a subject alternative
+// name extension.
+func marshalGeneralNames(
+	dnsNames, emailAddresses []string, ipAddresses []net.IP, uris []*url.URL,
+) ([]byte, error) {
+	var names []asn1.RawValue
+	addName := func(tag int, name string) error {
+		for i := 0; i < len(name); i++ {
+			if name[i] >= utf8.RuneSelf {
+				return errors.New("name is not an IA5String: " + name)
+			}
+		}
+		names = append(names, asn1.RawValue{
+			Tag: tag, Class: asn1.ClassContextSpecific, Bytes: []byte(name),
+		})
+		return nil
+	}
+
+	for _, name := range dnsNames {
+		if err := addName(nameTypeDNS, name); err != nil {
+			return nil, err
+		}
+	}
+	for _, email := range emailAddresses {
+		if err := addName(nameTypeEmail, email); err != nil {
+			return nil, err
+		}
+	}
+	for _, ip := range ipAddresses {
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		names = append(names, asn1.RawValue{
+			Tag: nameTypeIP, Class: asn1.ClassContextSpecific, Bytes: ip,
+		})
+	}
+	for _, uri := range uris {
+		if err := addName(nameTypeURI, uri.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return asn1.Marshal(names)
+}
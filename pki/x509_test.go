@@ -0,0 +1,340 @@
+package pki_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/pki"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/schemes"
+)
+
+var now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+type testCA struct {
+	cert *pki.Certificate
+	priv sign.PrivateKey
+}
+
+// issue creates a certificate for template issued by ca, or self-signed if
+// ca is nil.
+func issue(t *testing.T, scheme sign.Scheme, template *x509.Certificate, ca *testCA) testCA {
+	t.Helper()
+	pk, sk, err := scheme.GenerateKey()
+	test.CheckNoErr(t, err, "GenerateKey failed")
+
+	parent, signer := template, sk
+	if ca != nil {
+		parent, signer = ca.cert.Certificate, ca.priv
+	}
+	der, err := pki.CreateCertificate(template, parent, pk, signer)
+	test.CheckNoErr(t, err, "CreateCertificate failed")
+	cert, err := pki.ParseCertificate(der)
+	test.CheckNoErr(t, err, "ParseCertificate failed")
+	test.CheckOk(cert.PublicKey.Equal(pk), "public key mismatch", t)
+	return testCA{cert, sk}
+}
+
+func caTemplate(name string, serial int64) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+}
+
+func leafTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"example.com"},
+		IPAddresses:  []net.IP{net.IPv4(192, 0, 2, 1)},
+	}
+}
+
+func TestCertificateChain(t *testing.T) {
+	for _, name := range []string{
+		"Ed25519",
+		"Ed448",
+		"ECDSA-P256-SHA256",
+		"ML-DSA-44",
+		"SLH-DSA-SHA2-128f",
+		"MLDSA44-Ed25519-SHA512",
+	} {
+		scheme := schemes.ByName(name)
+		t.Run(name, func(t *testing.T) {
+			root := issue(t, scheme, caTemplate("root", 1), nil)
+			inter := issue(t, scheme, caTemplate("intermediate", 2), &root)
+			leaf := issue(t, scheme, leafTemplate(), &inter)
+
+			test.CheckOk(leaf.cert.SignatureScheme == scheme, "wrong scheme", t)
+			test.CheckOk(len(root.cert.SubjectKeyId) > 0, "no subject key id", t)
+			test.CheckNoErr(t, leaf.cert.CheckSignatureFrom(inter.cert), "CheckSignatureFrom failed")
+			test.CheckIsErr(t, leaf.cert.CheckSignatureFrom(root.cert), "wrong parent accepted")
+
+			roots, inters := pki.NewCertPool(), pki.NewCertPool()
+			roots.AddCert(root.cert)
+			inters.AddCert(inter.cert)
+			opts := pki.VerifyOptions{
+				DNSName:       "example.com",
+				Roots:         roots,
+				Intermediates: inters,
+				CurrentTime:   now,
+			}
+			chains, err := leaf.cert.Verify(opts)
+			test.CheckNoErr(t, err, "Verify failed")
+			test.CheckOk(len(chains) == 1 && len(chains[0]) == 3, "wrong chains", t)
+			test.CheckOk(chains[0][2].Equal(root.cert), "wrong root", t)
+
+			bad := opts
+			bad.DNSName = "example.org"
+			_, err = leaf.cert.Verify(bad)
+			var hostErr x509.HostnameError
+			test.CheckOk(errors.As(err, &hostErr), "expected HostnameError", t)
+
+			bad = opts
+			bad.CurrentTime = now.Add(2 * time.Hour)
+			_, err = leaf.cert.Verify(bad)
+			var invalidErr x509.CertificateInvalidError
+			test.CheckOk(errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired,
+				"expected expired certificate", t)
+
+			bad = opts
+			bad.Intermediates = nil
+			_, err = leaf.cert.Verify(bad)
+			var authErr x509.UnknownAuthorityError
+			test.CheckOk(errors.As(err, &authErr), "expected UnknownAuthorityError", t)
+
+			bad = opts
+			bad.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+			_, err = leaf.cert.Verify(bad)
+			test.CheckOk(errors.As(err, &invalidErr) && invalidErr.Reason == x509.IncompatibleUsage,
+				"expected incompatible usage", t)
+
+			// A tampered signature is rejected.
+			tampered := *leaf.cert
+			tampered.Certificate = new(x509.Certificate)
+			*tampered.Certificate = *leaf.cert.Certificate
+			tampered.Signature = append([]byte{}, leaf.cert.Signature...)
+			tampered.Signature[0] ^= 1
+			_, err = tampered.Verify(opts)
+			test.CheckIsErr(t, err, "tampered certificate accepted")
+		})
+	}
+}
+
+func TestUnsupportedScheme(t *testing.T) {
+	// FN-DSA has no object identifier yet.
+	pk, sk, err := schemes.ByName("Falcon-512").GenerateKey()
+	test.CheckNoErr(t, err, "GenerateKey failed")
+	template := caTemplate("root", 1)
+	_, err = pki.CreateCertificate(template, template, pk, sk)
+	test.CheckOk(errors.Is(err, pki.ErrUnsupportedAlgorithm), "expected ErrUnsupportedAlgorithm", t)
+}
+
+func TestPathLength(t *testing.T) {
+	scheme := schemes.ByName("Ed25519")
+	rootTemplate := caTemplate("root", 1)
+	rootTemplate.MaxPathLenZero = true
+	root := issue(t, scheme, rootTemplate, nil)
+	inter := issue(t, scheme, caTemplate("intermediate", 2), &root)
+	leaf := issue(t, scheme, leafTemplate(), &inter)
+
+	roots, inters := pki.NewCertPool(), pki.NewCertPool()
+	roots.AddCert(root.cert)
+	inters.AddCert(inter.cert)
+	_, err := leaf.cert.Verify(pki.VerifyOptions{
+		Roots:         roots,
+		Intermediates: inters,
+		CurrentTime:   now,
+	})
+	var invalidErr x509.CertificateInvalidError
+	test.CheckOk(errors.As(err, &invalidErr) && invalidErr.Reason == x509.TooManyIntermediates,
+		"expected too many intermediates", t)
+}
+
+func TestUnhandledCriticalExtension(t *testing.T) {
+	scheme := schemes.ByName("Ed25519")
+	critical := pkix.Extension{
+		Id:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 44363, 99},
+		Critical: true,
+		Value:    []byte{0x05, 0x00},
+	}
+
+	for i, name := range []string{"leaf", "intermediate", "root"} {
+		t.Run(name, func(t *testing.T) {
+			templates := []*x509.Certificate{
+				leafTemplate(), caTemplate("intermediate", 2), caTemplate("root", 1),
+			}
+			templates[i].ExtraExtensions = []pkix.Extension{critical}
+			root := issue(t, scheme, templates[2], nil)
+			inter := issue(t, scheme, templates[1], &root)
+			leaf := issue(t, scheme, templates[0], &inter)
+
+			roots, inters := pki.NewCertPool(), pki.NewCertPool()
+			roots.AddCert(root.cert)
+			inters.AddCert(inter.cert)
+			_, err := leaf.cert.Verify(pki.VerifyOptions{
+				Roots:         roots,
+				Intermediates: inters,
+				CurrentTime:   now,
+			})
+			var extErr x509.UnhandledCriticalExtension
+			test.CheckOk(errors.As(err, &extErr), "expected UnhandledCriticalExtension", t)
+		})
+	}
+}
+
+func TestNameConstraints(t *testing.T) {
+	// Take the extension from a certificate of crypto/x509, which encodes
+	// name constraints.
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+	constrained := caTemplate("constrained", 1)
+	constrained.PermittedDNSDomains = []string{"example.com"}
+	der, err := x509.CreateCertificate(rand.Reader, constrained, constrained, &ecKey.PublicKey, ecKey)
+	test.CheckNoErr(t, err, "CreateCertificate failed")
+	ecCert, err := x509.ParseCertificate(der)
+	test.CheckNoErr(t, err, "ParseCertificate failed")
+	var constraints pkix.Extension
+	for _, ext := range ecCert.Extensions {
+		if ext.Id.Equal(asn1.ObjectIdentifier{2, 5, 29, 30}) {
+			constraints = ext
+		}
+	}
+	test.CheckOk(constraints.Id != nil, "missing name constraints", t)
+
+	scheme := schemes.ByName("Ed25519")
+	for i, name := range []string{"intermediate", "root"} {
+		t.Run(name, func(t *testing.T) {
+			templates := []*x509.Certificate{caTemplate("intermediate", 2), caTemplate("root", 1)}
+			templates[i].ExtraExtensions = []pkix.Extension{constraints}
+			root := issue(t, scheme, templates[1], nil)
+			inter := issue(t, scheme, templates[0], &root)
+			leaf := issue(t, scheme, leafTemplate(), &inter)
+
+			roots, inters := pki.NewCertPool(), pki.NewCertPool()
+			roots.AddCert(root.cert)
+			inters.AddCert(inter.cert)
+			_, err := leaf.cert.Verify(pki.VerifyOptions{
+				Roots:         roots,
+				Intermediates: inters,
+				CurrentTime:   now,
+			})
+			var invalidErr x509.CertificateInvalidError
+			test.CheckOk(errors.As(err, &invalidErr) && invalidErr.Reason == x509.CANotAuthorizedForThisName,
+				"expected name constraints to be rejected", t)
+		})
+	}
+}
+
+// TestInterop checks certificates of schemes known to crypto/x509 in both
+// directions.
+func TestInterop(t *testing.T) {
+	scheme := schemes.ByName("ECDSA-P256-SHA256")
+	root := issue(t, scheme, caTemplate("root", 1), nil)
+	leaf := issue(t, scheme, leafTemplate(), &root)
+	test.CheckNoErr(t, leaf.cert.Certificate.CheckSignatureFrom(root.cert.Certificate),
+		"crypto/x509 rejects the certificate")
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+	template := caTemplate("std", 1)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	test.CheckNoErr(t, err, "CreateCertificate failed")
+	cert, err := pki.ParseCertificate(der)
+	test.CheckNoErr(t, err, "ParseCertificate failed")
+	test.CheckNoErr(t, cert.CheckSignatureFrom(cert), "CheckSignatureFrom failed")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+	template.SignatureAlgorithm = x509.ECDSAWithSHA384
+	der, err = x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	test.CheckNoErr(t, err, "CreateCertificate failed")
+	cert, err = pki.ParseCertificate(der)
+	test.CheckNoErr(t, err, "ParseCertificate failed")
+	test.CheckIsErr(t, cert.CheckSignatureFrom(cert), "P-256 with SHA-384 accepted")
+}
+
+func TestCertificateRequest(t *testing.T) {
+	for _, name := range []string{"Ed448", "ML-DSA-65", "ECDSA-P384-SHA384"} {
+		scheme := schemes.ByName(name)
+		t.Run(name, func(t *testing.T) {
+			_, sk, err := scheme.GenerateKey()
+			test.CheckNoErr(t, err, "GenerateKey failed")
+
+			der, err := pki.CreateCertificateRequest(&x509.CertificateRequest{
+				Subject:        pkix.Name{CommonName: "subject", Organization: []string{"CIRCL"}},
+				DNSNames:       []string{"example.com", "www.example.com"},
+				EmailAddresses: []string{"admin@example.com"},
+			}, sk)
+			test.CheckNoErr(t, err, "CreateCertificateRequest failed")
+
+			csr, err := pki.ParseCertificateRequest(der)
+			test.CheckNoErr(t, err, "ParseCertificateRequest failed")
+			test.CheckNoErr(t, csr.CheckSignature(), "CheckSignature failed")
+			test.CheckOk(csr.PublicKey.Equal(sk.Public()), "public key mismatch", t)
+			test.CheckOk(csr.Subject.CommonName == "subject", "wrong subject", t)
+			test.CheckOk(len(csr.DNSNames) == 2 && len(csr.EmailAddresses) == 1,
+				"wrong subject alternative names", t)
+
+			csr.Signature[len(csr.Signature)/2] ^= 1
+			test.CheckIsErr(t, csr.CheckSignature(), "tampered request accepted")
+		})
+	}
+}
+
+func TestRevocationList(t *testing.T) {
+	for _, name := range []string{"Ed25519", "ML-DSA-44"} {
+		scheme := schemes.ByName(name)
+		t.Run(name, func(t *testing.T) {
+			ca := issue(t, scheme, caTemplate("root", 1), nil)
+			der, err := pki.CreateRevocationList(&x509.RevocationList{
+				Number:     big.NewInt(7),
+				ThisUpdate: now,
+				NextUpdate: now.Add(time.Hour),
+				RevokedCertificateEntries: []x509.RevocationListEntry{
+					{SerialNumber: big.NewInt(3), RevocationTime: now, ReasonCode: 1},
+					{SerialNumber: big.NewInt(4), RevocationTime: now},
+				},
+			}, ca.cert, ca.priv)
+			test.CheckNoErr(t, err, "CreateRevocationList failed")
+
+			rl, err := pki.ParseRevocationList(der)
+			test.CheckNoErr(t, err, "ParseRevocationList failed")
+			test.CheckNoErr(t, rl.CheckSignatureFrom(ca.cert), "CheckSignatureFrom failed")
+			test.CheckOk(rl.Number.Int64() == 7, "wrong number", t)
+			entries := rl.RevokedCertificateEntries
+			test.CheckOk(len(entries) == 2 && entries[0].SerialNumber.Int64() == 3 &&
+				entries[0].ReasonCode == 1, "wrong entries", t)
+
+			other := issue(t, scheme, caTemplate("other", 2), nil)
+			test.CheckIsErr(t, rl.CheckSignatureFrom(other.cert), "wrong issuer accepted")
+			_, err = pki.CreateRevocationList(&x509.RevocationList{
+				Number:     big.NewInt(1),
+				ThisUpdate: now,
+				NextUpdate: now.Add(time.Hour),
+			}, ca.cert, other.priv)
+			test.CheckIsErr(t, err, "mismatching private key accepted")
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package composite provides composite signatures, which combine ML-DSA
+// with a traditional signature algorithm, so that they remain secure as
+// long as one of the two components is.
+//
+// This package follows the composite ML-DSA [draft] of the IETF LAMPS
+// working group, which is not final yet: encodings and identifiers may
+// change in later versions. The [ID] represents the composite algorithms
+// listed in the draft except those using brainpool curves.
+//
+// Both components sign a message representative that binds the message,
+// the optional context, and the composite algorithm:
+//
+//	M' = Prefix || Label || len(ctx) || ctx || PH(M)
+//
+// where Prefix is "CompositeAlgorithmSignatures2025", Label is the name
+// of the algorithm prefixed by "COMPSIG-", and PH is the pre-hash
+// function of the algorithm. ML-DSA also uses Label as context.
+//
+// Public keys and signatures are the concatenation of those of ML-DSA and
+// of the traditional algorithm. Private keys are the concatenation of the
+// seed of the ML-DSA key and the traditional private key.
+//
+// [draft]: https://datatracker.ietf.org/doc/draft-ietf-lamps-pq-composite-sigs/
+package composite
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/sign"
+)
+
+// SeedSize is the size of the seeds used by [NewKeyFromSeed].
+const SeedSize = 32
+
+var (
+	ErrParam   = errors.New("sign/composite: invalid composite algorithm")
+	ErrPreHash = errors.New("sign/composite: pre-hashed messages are not supported")
+	ErrContext = errors.New("sign/composite: context larger than 255 bytes")
+)
+
+// [GenerateKey] returns a pair of keys of the composite algorithm
+// specified. It returns an error if it fails reading from the random
+// source.
+func GenerateKey(
+	random io.Reader, id ID,
+) (pub PublicKey, priv PrivateKey, err error) {
+	params := id.params()
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, params.mldsa.SeedSize())
+	_, err = io.ReadFull(random, seed)
+	if err != nil {
+		return
+	}
+
+	trad, err := params.trad.generateKey(random)
+	if err != nil {
+		return
+	}
+
+	mldsaPub, mldsaPriv := params.mldsa.DeriveKey(seed)
+	pub = PublicKey{mldsaPub, trad.Public(), id}
+	priv = PrivateKey{mldsaPriv, trad, id}
+	return
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of keys from a seed.
+//
+// The seed is expanded with SHAKE256 into the seed of the ML-DSA key and
+// the randomness used to generate the traditional key. As the draft does
+// not specify this derivation, keys are only reproducible with this
+// package.
+func NewKeyFromSeed(id ID, seed *[SeedSize]byte) (PublicKey, PrivateKey) {
+	params := id.params()
+	h := sha3.NewShake256()
+	_, _ = h.Write(seed[:])
+
+	mldsaSeed := make([]byte, params.mldsa.SeedSize())
+	_, _ = h.Read(mldsaSeed)
+	mldsaPub, mldsaPriv := params.mldsa.DeriveKey(mldsaSeed)
+	trad := params.trad.deriveKey(&h)
+
+	return PublicKey{mldsaPub, trad.Public(), id},
+		PrivateKey{mldsaPriv, trad, id}
+}
+
+// [Sign] returns a signature of the message with the specified context.
+// The traditional component reads randomness from the random source, if
+// needed, while the ML-DSA component is deterministic.
+// It returns an error if the context is larger than 255 bytes, or if the
+// traditional signature fails.
+func Sign(
+	priv *PrivateKey, random io.Reader, message, context []byte,
+) (signature []byte, err error) {
+	params := priv.ID.params()
+	if random == nil {
+		random = rand.Reader
+	}
+
+	msgPrime, err := params.messageRepresentative(message, context)
+	if err != nil {
+		return nil, err
+	}
+
+	tradSig, err := params.trad.sign(priv.trad, random, msgPrime)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &sign.SignatureOpts{Context: string(params.label())}
+	mldsaSig := params.mldsa.Sign(priv.mldsa, msgPrime, opts)
+	return append(mldsaSig, tradSig...), nil
+}
+
+// [Verify] returns true if the signature of the message with the specified
+// context is valid, that is, if both components are valid.
+func Verify(pub *PublicKey, message, signature, context []byte) bool {
+	params := pub.ID.params()
+	size := params.mldsa.SignatureSize()
+	if len(signature) < size || len(signature) > params.SignatureSize() {
+		return false
+	}
+
+	msgPrime, err := params.messageRepresentative(message, context)
+	if err != nil {
+		return false
+	}
+
+	opts := &sign.SignatureOpts{Context: string(params.label())}
+	mldsaOk := params.mldsa.Verify(pub.mldsa, msgPrime, signature[:size], opts)
+	tradOk := params.trad.verify(pub.trad, msgPrime, signature[size:])
+	return mldsaOk && tradOk
+}
+
+// messageRepresentative returns M' = Prefix || Label || len(ctx) || ctx ||
+// PH(M).
+func (p *params) messageRepresentative(message, context []byte) ([]byte, error) {
+	if len(context) > 255 {
+		return nil, ErrContext
+	}
+
+	var digest []byte
+	if p.ph.Xof != 0 {
+		h := p.ph.Xof.New()
+		_, _ = h.Write(message)
+		digest = make([]byte, 64)
+		_, _ = h.Read(digest)
+	} else {
+		h := p.ph.Hash.New()
+		_, _ = h.Write(message)
+		digest = h.Sum(nil)
+	}
+
+	label := p.label()
+	m := make([]byte, 0, len(prefix)+len(label)+1+len(context)+len(digest))
+	m = append(m, prefix...)
+	m = append(m, label...)
+	m = append(m, byte(len(context)))
+	m = append(m, context...)
+	m = append(m, digest...)
+	return m, nil
+}
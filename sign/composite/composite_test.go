@@ -0,0 +1,145 @@
+package composite_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/pki"
+	"github.com/cloudflare/circl/sign/composite"
+)
+
+var fastIDs = [...]composite.ID{
+	composite.MLDSA44_Ed25519_SHA512,
+	composite.MLDSA44_ECDSA_P256_SHA256,
+	composite.MLDSA65_ECDSA_P384_SHA512,
+	composite.MLDSA87_Ed448_SHAKE256,
+	composite.MLDSA87_ECDSA_P521_SHA512,
+}
+
+// oids are the object identifiers of the composite algorithms, as assigned
+// under id-alg by draft-ietf-lamps-pq-composite-sigs. Arcs 47 and 50 are the
+// brainpool variants, which are not supported.
+var oids = map[composite.ID]asn1.ObjectIdentifier{
+	composite.MLDSA44_RSA2048_PSS_SHA256:    {1, 3, 6, 1, 5, 5, 7, 6, 37},
+	composite.MLDSA44_RSA2048_PKCS15_SHA256: {1, 3, 6, 1, 5, 5, 7, 6, 38},
+	composite.MLDSA44_Ed25519_SHA512:        {1, 3, 6, 1, 5, 5, 7, 6, 39},
+	composite.MLDSA44_ECDSA_P256_SHA256:     {1, 3, 6, 1, 5, 5, 7, 6, 40},
+	composite.MLDSA65_RSA3072_PSS_SHA512:    {1, 3, 6, 1, 5, 5, 7, 6, 41},
+	composite.MLDSA65_RSA3072_PKCS15_SHA512: {1, 3, 6, 1, 5, 5, 7, 6, 42},
+	composite.MLDSA65_RSA4096_PSS_SHA512:    {1, 3, 6, 1, 5, 5, 7, 6, 43},
+	composite.MLDSA65_RSA4096_PKCS15_SHA512: {1, 3, 6, 1, 5, 5, 7, 6, 44},
+	composite.MLDSA65_ECDSA_P256_SHA512:     {1, 3, 6, 1, 5, 5, 7, 6, 45},
+	composite.MLDSA65_ECDSA_P384_SHA512:     {1, 3, 6, 1, 5, 5, 7, 6, 46},
+	composite.MLDSA65_Ed25519_SHA512:        {1, 3, 6, 1, 5, 5, 7, 6, 48},
+	composite.MLDSA87_ECDSA_P384_SHA512:     {1, 3, 6, 1, 5, 5, 7, 6, 49},
+	composite.MLDSA87_Ed448_SHAKE256:        {1, 3, 6, 1, 5, 5, 7, 6, 51},
+	composite.MLDSA87_RSA3072_PSS_SHA512:    {1, 3, 6, 1, 5, 5, 7, 6, 52},
+	composite.MLDSA87_RSA4096_PSS_SHA512:    {1, 3, 6, 1, 5, 5, 7, 6, 53},
+	composite.MLDSA87_ECDSA_P521_SHA512:     {1, 3, 6, 1, 5, 5, 7, 6, 54},
+}
+
+func TestComposite(t *testing.T) {
+	for id := composite.ID(1); id.IsValid(); id++ {
+		t.Run(id.String(), func(t *testing.T) {
+			pub, priv, err := composite.GenerateKey(rand.Reader, id)
+			test.CheckNoErr(t, err, "GenerateKey failed")
+			testSign(t, &pub, &priv)
+			testPKIX(t, &pub, &priv)
+		})
+	}
+}
+
+func testSign(t *testing.T, pub *composite.PublicKey, priv *composite.PrivateKey) {
+	msg := []byte("Alice and Bob")
+	ctx := []byte("A context")
+	sig, err := composite.Sign(priv, rand.Reader, msg, ctx)
+	test.CheckNoErr(t, err, "Sign failed")
+	test.CheckOk(composite.Verify(pub, msg, sig, ctx), "Verify failed", t)
+
+	test.CheckOk(!composite.Verify(pub, msg[1:], sig, ctx), "Verify should fail", t)
+	test.CheckOk(!composite.Verify(pub, msg, sig, nil), "Verify should fail", t)
+
+	// Both components must be valid.
+	mldsaSize := len(sig) - 1
+	for _, i := range []int{0, mldsaSize} {
+		sig[i] ^= 1
+		test.CheckOk(!composite.Verify(pub, msg, sig, ctx), "Verify should fail", t)
+		sig[i] ^= 1
+	}
+
+	_, err = composite.Sign(priv, rand.Reader, msg, make([]byte, 256))
+	test.CheckIsErr(t, err, "Sign should fail with a long context")
+
+	sig, err = priv.Sign(rand.Reader, msg, crypto.Hash(0))
+	test.CheckNoErr(t, err, "Sign failed")
+	test.CheckOk(composite.Verify(pub, msg, sig, nil), "Verify failed", t)
+
+	_, err = priv.Sign(rand.Reader, msg, crypto.SHA256)
+	test.CheckIsErr(t, err, "Sign should fail with pre-hash")
+}
+
+func testPKIX(t *testing.T, pub *composite.PublicKey, priv *composite.PrivateKey) {
+	der, err := pki.MarshalPKIXPublicKey(pub)
+	test.CheckNoErr(t, err, "MarshalPKIXPublicKey failed")
+
+	var spki struct {
+		Algorithm struct{ Algorithm asn1.ObjectIdentifier }
+		PublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(der, &spki)
+	test.CheckNoErr(t, err, "asn1.Unmarshal failed")
+	want, ok := oids[pub.ID]
+	test.CheckOk(ok, "missing OID", t)
+	test.CheckOk(spki.Algorithm.Algorithm.Equal(want), "wrong OID", t)
+
+	pub2, err := pki.UnmarshalPKIXPublicKey(der)
+	test.CheckNoErr(t, err, "UnmarshalPKIXPublicKey failed")
+	test.CheckOk(pub.Equal(pub2), "public key not equal", t)
+
+	der, err = pki.MarshalPKIXPrivateKey(priv)
+	test.CheckNoErr(t, err, "MarshalPKIXPrivateKey failed")
+	priv2, err := pki.UnmarshalPKIXPrivateKey(der)
+	test.CheckNoErr(t, err, "UnmarshalPKIXPrivateKey failed")
+	test.CheckOk(priv.Equal(priv2), "private key not equal", t)
+}
+
+func TestDeriveKey(t *testing.T) {
+	for _, id := range fastIDs {
+		t.Run(id.String(), func(t *testing.T) {
+			scheme := id.Scheme()
+			seed := make([]byte, scheme.SeedSize())
+			pub0, priv0 := scheme.DeriveKey(seed)
+			pub1, priv1 := scheme.DeriveKey(seed)
+			test.CheckOk(pub0.Equal(pub1), "public key not equal", t)
+			test.CheckOk(priv0.Equal(priv1), "private key not equal", t)
+
+			seed[0]++
+			pub2, _ := scheme.DeriveKey(seed)
+			test.CheckOk(!pub0.Equal(pub2), "public keys must differ", t)
+
+			b, err := pub0.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary failed")
+			_, err = scheme.UnmarshalBinaryPublicKey(b[:len(b)-1])
+			test.CheckIsErr(t, err, "UnmarshalBinary should fail")
+
+			b, err = priv0.MarshalBinary()
+			test.CheckNoErr(t, err, "MarshalBinary failed")
+			_, err = scheme.UnmarshalBinaryPrivateKey(b[:len(b)-1])
+			test.CheckIsErr(t, err, "UnmarshalBinary should fail")
+		})
+	}
+}
+
+func TestIDByName(t *testing.T) {
+	for id := composite.ID(1); id.IsValid(); id++ {
+		got, err := composite.IDByName(id.String())
+		test.CheckNoErr(t, err, "IDByName failed")
+		test.CheckOk(got == id, "wrong ID", t)
+	}
+
+	_, err := composite.IDByName("MLDSA44-Ed448-SHA512")
+	test.CheckIsErr(t, err, "IDByName should fail")
+}
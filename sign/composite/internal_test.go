@@ -0,0 +1,31 @@
+package composite
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestMessageRepresentative(t *testing.T) {
+	p := MLDSA44_ECDSA_P256_SHA256.params()
+	msg := []byte("message")
+	got, err := p.messageRepresentative(msg, []byte("ctx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(msg)
+	want, _ := hex.DecodeString(
+		"436f6d706f73697465416c676f726974686d5369676e61747572657332303235" +
+			hex.EncodeToString([]byte("COMPSIG-MLDSA44-ECDSA-P256-SHA256")) +
+			"03" + hex.EncodeToString([]byte("ctx")) +
+			hex.EncodeToString(digest[:]))
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %x\nwant %x", got, want)
+	}
+
+	if _, err := p.messageRepresentative(msg, make([]byte, 256)); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,147 @@
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// [PrivateKey] stores a private key of a composite algorithm.
+// It implements the [crypto.Signer] and [crypto.PrivateKey] interfaces.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PrivateKey struct {
+	mldsa sign.PrivateKey
+	trad  crypto.Signer
+	ID
+}
+
+// MarshalBinary returns the seed of the ML-DSA key followed by the
+// traditional private key.
+func (k PrivateKey) MarshalBinary() ([]byte, error) {
+	params := k.ID.params()
+	seed := k.mldsa.(sign.Seeded).Seed()
+	if seed == nil {
+		return nil, errors.New("sign/composite: seed not retained in ML-DSA private key")
+	}
+
+	return append(bytes.Clone(seed), params.trad.marshalPrivateKey(k.trad)...), nil
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes.
+// Caller must specify the private key's [ID] in advance.
+// Example:
+//
+//	key := PrivateKey{ID: MLDSA65_Ed25519_SHA512}
+//	key.UnmarshalBinary(bytes) // returns nil
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	params := k.ID.params()
+	size := params.mldsa.SeedSize()
+	if len(b) < size || len(b) > params.PrivateKeySize() {
+		return sign.ErrPrivKeySize
+	}
+
+	trad, ok := params.trad.unmarshalPrivateKey(b[size:])
+	if !ok {
+		return errors.New("sign/composite: invalid traditional private key")
+	}
+
+	_, k.mldsa = params.mldsa.DeriveKey(b[:size])
+	k.trad = trad
+	return nil
+}
+
+func (k PrivateKey) Public() crypto.PublicKey { return k.PublicKey() }
+func (k PrivateKey) PublicKey() PublicKey {
+	return PublicKey{k.mldsa.Public().(sign.PublicKey), k.trad.Public(), k.ID}
+}
+
+func (k PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(PrivateKey)
+	if !ok || k.ID != other.ID {
+		return false
+	}
+
+	a, errA := k.MarshalBinary()
+	b, errB := other.MarshalBinary()
+	return errA == nil && errB == nil && subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// [PrivateKey.Sign] returns a signature of the message with an empty
+// context.
+// Pre-hashed messages are not supported, so opts.HashFunc() must be zero.
+// It returns an error if the traditional signature fails.
+func (k PrivateKey) Sign(
+	random io.Reader, message []byte, opts crypto.SignerOpts,
+) (signature []byte, err error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, ErrPreHash
+	}
+
+	return Sign(&k, random, message, nil)
+}
+
+// [PublicKey] stores a public key of a composite algorithm.
+// It implements the [crypto.PublicKey] interface.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PublicKey struct {
+	mldsa sign.PublicKey
+	trad  crypto.PublicKey
+	ID
+}
+
+// MarshalBinary returns the ML-DSA public key followed by the traditional
+// public key.
+func (k PublicKey) MarshalBinary() ([]byte, error) {
+	params := k.ID.params()
+	b, err := k.mldsa.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, params.trad.marshalPublicKey(k.trad)...), nil
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+// Caller must specify the public key's [ID] in advance.
+// Example:
+//
+//	key := PublicKey{ID: MLDSA65_Ed25519_SHA512}
+//	key.UnmarshalBinary(bytes) // returns nil
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	params := k.ID.params()
+	if len(b) != params.PublicKeySize() {
+		return sign.ErrPubKeySize
+	}
+
+	size := params.mldsa.PublicKeySize()
+	mldsa, err := params.mldsa.UnmarshalBinaryPublicKey(b[:size])
+	if err != nil {
+		return err
+	}
+
+	trad, ok := params.trad.unmarshalPublicKey(b[size:])
+	if !ok {
+		return errors.New("sign/composite: invalid traditional public key")
+	}
+
+	k.mldsa = mldsa
+	k.trad = trad
+	return nil
+}
+
+func (k PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(PublicKey)
+	if !ok || k.ID != other.ID {
+		return false
+	}
+
+	a, errA := k.MarshalBinary()
+	b, errB := other.MarshalBinary()
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
@@ -0,0 +1,206 @@
+package composite
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"strings"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/mldsa/mldsa44"
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+	"github.com/cloudflare/circl/sign/mldsa/mldsa87"
+	"github.com/cloudflare/circl/xof"
+)
+
+// [ID] identifies the supported composite algorithms.
+// Note that the zero value is not a valid identifier.
+type ID byte
+
+//nolint:stylecheck
+const (
+	MLDSA44_RSA2048_PSS_SHA256    ID = iota + 1 // MLDSA44-RSA2048-PSS-SHA256
+	MLDSA44_RSA2048_PKCS15_SHA256               // MLDSA44-RSA2048-PKCS15-SHA256
+	MLDSA44_Ed25519_SHA512                      // MLDSA44-Ed25519-SHA512
+	MLDSA44_ECDSA_P256_SHA256                   // MLDSA44-ECDSA-P256-SHA256
+	MLDSA65_RSA3072_PSS_SHA512                  // MLDSA65-RSA3072-PSS-SHA512
+	MLDSA65_RSA3072_PKCS15_SHA512               // MLDSA65-RSA3072-PKCS15-SHA512
+	MLDSA65_RSA4096_PSS_SHA512                  // MLDSA65-RSA4096-PSS-SHA512
+	MLDSA65_RSA4096_PKCS15_SHA512               // MLDSA65-RSA4096-PKCS15-SHA512
+	MLDSA65_ECDSA_P256_SHA512                   // MLDSA65-ECDSA-P256-SHA512
+	MLDSA65_ECDSA_P384_SHA512                   // MLDSA65-ECDSA-P384-SHA512
+	MLDSA65_Ed25519_SHA512                      // MLDSA65-Ed25519-SHA512
+	MLDSA87_ECDSA_P384_SHA512                   // MLDSA87-ECDSA-P384-SHA512
+	MLDSA87_Ed448_SHAKE256                      // MLDSA87-Ed448-SHAKE256
+	MLDSA87_RSA3072_PSS_SHA512                  // MLDSA87-RSA3072-PSS-SHA512
+	MLDSA87_RSA4096_PSS_SHA512                  // MLDSA87-RSA4096-PSS-SHA512
+	MLDSA87_ECDSA_P521_SHA512                   // MLDSA87-ECDSA-P521-SHA512
+	_MaxParams
+)
+
+// [IDByName] returns the [ID] that corresponds to the given name,
+// or an error if no composite algorithm was found.
+// See [ID] documentation for the specific names of each algorithm.
+// Names are case insensitive.
+//
+// Example:
+//
+//	IDByName("MLDSA65-Ed25519-SHA512") // returns (MLDSA65_Ed25519_SHA512, nil)
+func IDByName(name string) (ID, error) {
+	v := strings.ToLower(name)
+	for i := range supportedParams {
+		if strings.ToLower(supportedParams[i].name) == v {
+			return supportedParams[i].ID, nil
+		}
+	}
+
+	return ID(0), ErrParam
+}
+
+// IsValid returns true if the composite algorithm is supported.
+func (id ID) IsValid() bool { return 0 < id && id < _MaxParams }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return supportedParams[id-1].name
+}
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+	return &supportedParams[id-1]
+}
+
+// params contains all the relevant constants of a composite algorithm.
+type params struct {
+	name  string      // Name of the algorithm, also used in its label.
+	oid   int         // Last arc of the OID under id-alg.
+	mldsa sign.Scheme // ML-DSA component.
+	trad  traditional // Traditional component.
+	ph    sign.PreHash
+	ID    // Identifier of the algorithm.
+}
+
+// The prefix of every message representative, which is the ASCII string
+// "CompositeAlgorithmSignatures2025".
+const prefix = "CompositeAlgorithmSignatures2025"
+
+var (
+	sha256PH = sign.PreHash{Hash: crypto.SHA256}
+	sha512PH = sign.PreHash{Hash: crypto.SHA512}
+	shakePH  = sign.PreHash{Xof: xof.SHAKE256}
+)
+
+// Stores all the supported (read-only) composite algorithms.
+// The brainpool variants are not supported.
+var supportedParams = [_MaxParams - 1]params{
+	{
+		ID: MLDSA44_RSA2048_PSS_SHA256, name: "MLDSA44-RSA2048-PSS-SHA256",
+		oid: 37, mldsa: mldsa44.Scheme(), ph: sha256PH,
+		trad: rsaPSS(2048, crypto.SHA256),
+	},
+	{
+		ID: MLDSA44_RSA2048_PKCS15_SHA256, name: "MLDSA44-RSA2048-PKCS15-SHA256",
+		oid: 38, mldsa: mldsa44.Scheme(), ph: sha256PH,
+		trad: rsaPKCS15(2048, crypto.SHA256),
+	},
+	{
+		ID: MLDSA44_Ed25519_SHA512, name: "MLDSA44-Ed25519-SHA512",
+		oid: 39, mldsa: mldsa44.Scheme(), ph: sha512PH,
+		trad: ed25519Trad{},
+	},
+	{
+		ID: MLDSA44_ECDSA_P256_SHA256, name: "MLDSA44-ECDSA-P256-SHA256",
+		oid: 40, mldsa: mldsa44.Scheme(), ph: sha256PH,
+		trad: ecdsaTrad{elliptic.P256(), crypto.SHA256},
+	},
+	{
+		ID: MLDSA65_RSA3072_PSS_SHA512, name: "MLDSA65-RSA3072-PSS-SHA512",
+		oid: 41, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: rsaPSS(3072, crypto.SHA256),
+	},
+	{
+		ID: MLDSA65_RSA3072_PKCS15_SHA512, name: "MLDSA65-RSA3072-PKCS15-SHA512",
+		oid: 42, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: rsaPKCS15(3072, crypto.SHA256),
+	},
+	{
+		ID: MLDSA65_RSA4096_PSS_SHA512, name: "MLDSA65-RSA4096-PSS-SHA512",
+		oid: 43, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: rsaPSS(4096, crypto.SHA384),
+	},
+	{
+		ID: MLDSA65_RSA4096_PKCS15_SHA512, name: "MLDSA65-RSA4096-PKCS15-SHA512",
+		oid: 44, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: rsaPKCS15(4096, crypto.SHA384),
+	},
+	{
+		ID: MLDSA65_ECDSA_P256_SHA512, name: "MLDSA65-ECDSA-P256-SHA512",
+		oid: 45, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: ecdsaTrad{elliptic.P256(), crypto.SHA256},
+	},
+	{
+		ID: MLDSA65_ECDSA_P384_SHA512, name: "MLDSA65-ECDSA-P384-SHA512",
+		oid: 46, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: ecdsaTrad{elliptic.P384(), crypto.SHA384},
+	},
+	{
+		ID: MLDSA65_Ed25519_SHA512, name: "MLDSA65-Ed25519-SHA512",
+		oid: 48, mldsa: mldsa65.Scheme(), ph: sha512PH,
+		trad: ed25519Trad{},
+	},
+	{
+		ID: MLDSA87_ECDSA_P384_SHA512, name: "MLDSA87-ECDSA-P384-SHA512",
+		oid: 49, mldsa: mldsa87.Scheme(), ph: sha512PH,
+		trad: ecdsaTrad{elliptic.P384(), crypto.SHA384},
+	},
+	{
+		ID: MLDSA87_Ed448_SHAKE256, name: "MLDSA87-Ed448-SHAKE256",
+		oid: 51, mldsa: mldsa87.Scheme(), ph: shakePH,
+		trad: ed448Trad{},
+	},
+	{
+		ID: MLDSA87_RSA3072_PSS_SHA512, name: "MLDSA87-RSA3072-PSS-SHA512",
+		oid: 52, mldsa: mldsa87.Scheme(), ph: sha512PH,
+		trad: rsaPSS(3072, crypto.SHA256),
+	},
+	{
+		ID: MLDSA87_RSA4096_PSS_SHA512, name: "MLDSA87-RSA4096-PSS-SHA512",
+		oid: 53, mldsa: mldsa87.Scheme(), ph: sha512PH,
+		trad: rsaPSS(4096, crypto.SHA384),
+	},
+	{
+		ID: MLDSA87_ECDSA_P521_SHA512, name: "MLDSA87-ECDSA-P521-SHA512",
+		oid: 54, mldsa: mldsa87.Scheme(), ph: sha512PH,
+		trad: ecdsaTrad{elliptic.P521(), crypto.SHA512},
+	},
+}
+
+// Oid returns the object identifier of the composite algorithm, which is
+// used for both its public keys and its signatures.
+func (p *params) Oid() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 6, p.oid}
+}
+
+// label returns the domain separator of the composite algorithm.
+func (p *params) label() []byte { return []byte("COMPSIG-" + p.name) }
+
+func (p *params) PublicKeySize() int {
+	return p.mldsa.PublicKeySize() + p.trad.publicKeySize()
+}
+
+// PrivateKeySize is the size of private keys, made of the seed of the
+// ML-DSA key and the traditional key. For RSA, it is the maximum size, as
+// the DER encoding of RSA private keys is of variable length.
+func (p *params) PrivateKeySize() int {
+	return p.mldsa.SeedSize() + p.trad.privateKeySize()
+}
+
+// SignatureSize is the size of signatures. For ECDSA, it is the maximum
+// size, as the DER encoding of ECDSA signatures is of variable length.
+func (p *params) SignatureSize() int {
+	return p.mldsa.SignatureSize() + p.trad.signatureSize()
+}
@@ -0,0 +1,116 @@
+package composite
+
+import (
+	"crypto/rand"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// Scheme returns a generic signature interface for the composite
+// algorithm. It also implements pki.CertificateScheme.
+func (id ID) Scheme() sign.Scheme { return scheme{id.params()} }
+
+func (k PrivateKey) Scheme() sign.Scheme { return k.ID.Scheme() }
+func (k PublicKey) Scheme() sign.Scheme  { return k.ID.Scheme() }
+
+type scheme struct{ *params }
+
+func (s scheme) Name() string          { return s.name }
+func (s scheme) SeedSize() int         { return SeedSize }
+func (s scheme) SupportsContext() bool { return true }
+
+// GenerateKey is similar to [GenerateKey] function, except it always reads
+// random bytes from [rand.Reader].
+func (s scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	return GenerateKey(rand.Reader, s.ID)
+}
+
+// Sign returns a signature of the message with the context given.
+// If options is nil, an empty context is used.
+// It returns an empty slice if the signature generation fails.
+//
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, when
+// the context is too long, or when options selects a pre-hash function, as
+// the composite algorithm already pre-hashes the message.
+func (s scheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(PrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	sig, err := Sign(&k, rand.Reader, message, checkOpts(options))
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the signature of the message with the specified
+// context is valid.
+// If options is nil, an empty context is used.
+//
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, when
+// the context is too long, or when options selects a pre-hash function.
+func (s scheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(PublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	return Verify(&k, message, signature, checkOpts(options))
+}
+
+// checkOpts returns the context set in options.
+//
+// Panics if the context is too long or if options selects a pre-hash
+// function.
+func checkOpts(options *sign.SignatureOpts) []byte {
+	if options == nil {
+		return nil
+	}
+	if !options.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
+	if len(options.Context) > 255 {
+		panic(sign.ErrContextTooLong)
+	}
+
+	return []byte(options.Context)
+}
+
+// DeriveKey deterministically generates a pair of keys from a seed,
+// see [NewKeyFromSeed].
+//
+// Panics if seed is not of length [SeedSize].
+func (s scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != SeedSize {
+		panic(sign.ErrSeedSize)
+	}
+
+	return NewKeyFromSeed(s.ID, (*[SeedSize]byte)(seed))
+}
+
+func (s scheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k := PublicKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (s scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k := PrivateKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,383 @@
+package composite
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/sign/ed25519"
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+// traditional is the classical component of a composite algorithm.
+// Keys are encoded as follows:
+//   - Ed25519 and Ed448: public keys and seeds as in RFC 8032.
+//   - ECDSA: uncompressed points and ECPrivateKey of RFC 5915.
+//   - RSA: RSAPublicKey and RSAPrivateKey of RFC 8017.
+type traditional interface {
+	generateKey(rand io.Reader) (crypto.Signer, error)
+
+	// deriveKey generates a key reading randomness from a XOF.
+	deriveKey(xof io.Reader) crypto.Signer
+
+	marshalPublicKey(pub crypto.PublicKey) []byte
+	unmarshalPublicKey(b []byte) (crypto.PublicKey, bool)
+	marshalPrivateKey(priv crypto.Signer) []byte
+	unmarshalPrivateKey(b []byte) (crypto.Signer, bool)
+	sign(priv crypto.Signer, rand io.Reader, message []byte) ([]byte, error)
+	verify(pub crypto.PublicKey, message, signature []byte) bool
+
+	publicKeySize() int
+	privateKeySize() int
+	signatureSize() int
+}
+
+type ed25519Trad struct{}
+
+func (ed25519Trad) generateKey(rand io.Reader) (crypto.Signer, error) {
+	_, priv, err := ed25519.GenerateKey(rand)
+	return priv, err
+}
+
+func (ed25519Trad) deriveKey(xof io.Reader) crypto.Signer {
+	var seed [ed25519.SeedSize]byte
+	_, _ = io.ReadFull(xof, seed[:])
+	return ed25519.NewKeyFromSeed(seed[:])
+}
+
+func (ed25519Trad) marshalPublicKey(pub crypto.PublicKey) []byte {
+	return pub.(ed25519.PublicKey)
+}
+
+func (ed25519Trad) unmarshalPublicKey(b []byte) (crypto.PublicKey, bool) {
+	return ed25519.PublicKey(bytes.Clone(b)), len(b) == ed25519.PublicKeySize
+}
+
+func (ed25519Trad) marshalPrivateKey(priv crypto.Signer) []byte {
+	return priv.(ed25519.PrivateKey).Seed()
+}
+
+func (ed25519Trad) unmarshalPrivateKey(b []byte) (crypto.Signer, bool) {
+	if len(b) != ed25519.SeedSize {
+		return nil, false
+	}
+	return ed25519.NewKeyFromSeed(b), true
+}
+
+func (ed25519Trad) sign(priv crypto.Signer, _ io.Reader, message []byte) ([]byte, error) {
+	return ed25519.Sign(priv.(ed25519.PrivateKey), message), nil
+}
+
+func (ed25519Trad) verify(pub crypto.PublicKey, message, signature []byte) bool {
+	return ed25519.Verify(pub.(ed25519.PublicKey), message, signature)
+}
+
+func (ed25519Trad) publicKeySize() int  { return ed25519.PublicKeySize }
+func (ed25519Trad) privateKeySize() int { return ed25519.SeedSize }
+func (ed25519Trad) signatureSize() int  { return ed25519.SignatureSize }
+
+type ed448Trad struct{}
+
+func (ed448Trad) generateKey(rand io.Reader) (crypto.Signer, error) {
+	_, priv, err := ed448.GenerateKey(rand)
+	return priv, err
+}
+
+func (ed448Trad) deriveKey(xof io.Reader) crypto.Signer {
+	var seed [ed448.SeedSize]byte
+	_, _ = io.ReadFull(xof, seed[:])
+	return ed448.NewKeyFromSeed(seed[:])
+}
+
+func (ed448Trad) marshalPublicKey(pub crypto.PublicKey) []byte {
+	return pub.(ed448.PublicKey)
+}
+
+func (ed448Trad) unmarshalPublicKey(b []byte) (crypto.PublicKey, bool) {
+	return ed448.PublicKey(bytes.Clone(b)), len(b) == ed448.PublicKeySize
+}
+
+func (ed448Trad) marshalPrivateKey(priv crypto.Signer) []byte {
+	return priv.(ed448.PrivateKey).Seed()
+}
+
+func (ed448Trad) unmarshalPrivateKey(b []byte) (crypto.Signer, bool) {
+	if len(b) != ed448.SeedSize {
+		return nil, false
+	}
+	return ed448.NewKeyFromSeed(b), true
+}
+
+func (ed448Trad) sign(priv crypto.Signer, _ io.Reader, message []byte) ([]byte, error) {
+	return ed448.Sign(priv.(ed448.PrivateKey), message, ""), nil
+}
+
+func (ed448Trad) verify(pub crypto.PublicKey, message, signature []byte) bool {
+	return ed448.Verify(pub.(ed448.PublicKey), message, signature, "")
+}
+
+func (ed448Trad) publicKeySize() int  { return ed448.PublicKeySize }
+func (ed448Trad) privateKeySize() int { return ed448.SeedSize }
+func (ed448Trad) signatureSize() int  { return ed448.SignatureSize }
+
+// ecdsaTrad is ECDSA over the given curve, signing the digest of the
+// message with the given hash function. Signatures are DER-encoded.
+type ecdsaTrad struct {
+	curve elliptic.Curve
+	hash  crypto.Hash
+}
+
+func (e ecdsaTrad) generateKey(rand io.Reader) (crypto.Signer, error) {
+	return ecdsa.GenerateKey(e.curve, rand)
+}
+
+func (e ecdsaTrad) ecdh() ecdh.Curve {
+	switch e.curve {
+	case elliptic.P256():
+		return ecdh.P256()
+	case elliptic.P384():
+		return ecdh.P384()
+	default:
+		return ecdh.P521()
+	}
+}
+
+func (e ecdsaTrad) byteLen() int { return (e.curve.Params().BitSize + 7) / 8 }
+
+// deriveKey reduces 64 extra bits modulo the order, so that the bias is
+// negligible.
+func (e ecdsaTrad) deriveKey(xof io.Reader) crypto.Signer {
+	n := e.curve.Params().N
+	b := make([]byte, e.byteLen()+8)
+	_, _ = io.ReadFull(xof, b)
+	nm1 := new(big.Int).Sub(n, big.NewInt(1))
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, nm1).Add(d, big.NewInt(1))
+
+	priv, err := e.ecdh().NewPrivateKey(d.FillBytes(make([]byte, e.byteLen())))
+	if err != nil {
+		panic(err)
+	}
+	pub, _ := e.unmarshalPublicKey(priv.PublicKey().Bytes())
+	return &ecdsa.PrivateKey{PublicKey: *pub.(*ecdsa.PublicKey), D: d}
+}
+
+func (e ecdsaTrad) marshalPublicKey(pub crypto.PublicKey) []byte {
+	k, err := pub.(*ecdsa.PublicKey).ECDH()
+	if err != nil {
+		panic(err)
+	}
+	return k.Bytes()
+}
+
+func (e ecdsaTrad) unmarshalPublicKey(b []byte) (crypto.PublicKey, bool) {
+	if _, err := e.ecdh().NewPublicKey(b); err != nil {
+		return nil, false
+	}
+	size := e.byteLen()
+	return &ecdsa.PublicKey{
+		Curve: e.curve,
+		X:     new(big.Int).SetBytes(b[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(b[1+size:]),
+	}, true
+}
+
+func (e ecdsaTrad) marshalPrivateKey(priv crypto.Signer) []byte {
+	b, err := x509.MarshalECPrivateKey(priv.(*ecdsa.PrivateKey))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (e ecdsaTrad) unmarshalPrivateKey(b []byte) (crypto.Signer, bool) {
+	priv, err := x509.ParseECPrivateKey(b)
+	if err != nil || priv.Curve != e.curve {
+		return nil, false
+	}
+	return priv, true
+}
+
+func (e ecdsaTrad) sign(priv crypto.Signer, rand io.Reader, message []byte) ([]byte, error) {
+	h := e.hash.New()
+	_, _ = h.Write(message)
+	return ecdsa.SignASN1(rand, priv.(*ecdsa.PrivateKey), h.Sum(nil))
+}
+
+func (e ecdsaTrad) verify(pub crypto.PublicKey, message, signature []byte) bool {
+	h := e.hash.New()
+	_, _ = h.Write(message)
+	return ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), h.Sum(nil), signature)
+}
+
+func (e ecdsaTrad) publicKeySize() int { return 1 + 2*e.byteLen() }
+
+// privateKeySize is the size of an ECPrivateKey including the parameters
+// and the public key, as encoded by [x509.MarshalECPrivateKey].
+func (e ecdsaTrad) privateKeySize() int {
+	// Content size of the OIDs 1.2.840.10045.3.1.7 for P-256, and
+	// 1.3.132.0.34 and 1.3.132.0.35 for P-384 and P-521.
+	oid := 5
+	if e.curve == elliptic.P256() {
+		oid = 8
+	}
+
+	version := derSize(1)
+	key := derSize(e.byteLen())
+	params := derSize(derSize(oid))
+	pub := derSize(derSize(1 + e.publicKeySize()))
+	return derSize(version + key + params + pub)
+}
+
+// signatureSize is the maximum size of a DER-encoded ECDSA signature.
+func (e ecdsaTrad) signatureSize() int {
+	return derSize(2 * derSize(e.byteLen()+1))
+}
+
+// rsaTrad is RSA with public exponent 65537 and a modulus of the given size,
+// signing with either RSASSA-PSS or RSASSA-PKCS1-v1_5 with the given hash
+// function. PSS uses MGF1 with the same hash function, and salts of the
+// size of its digest.
+type rsaTrad struct {
+	bits int
+	hash crypto.Hash
+	pss  bool
+}
+
+func rsaPSS(bits int, hash crypto.Hash) rsaTrad    { return rsaTrad{bits, hash, true} }
+func rsaPKCS15(bits int, hash crypto.Hash) rsaTrad { return rsaTrad{bits, hash, false} }
+
+func (r rsaTrad) generateKey(rand io.Reader) (crypto.Signer, error) {
+	return rsa.GenerateKey(rand, r.bits)
+}
+
+// deriveKey finds the prime factors of the modulus by rejection sampling.
+// It is not constant-time.
+func (r rsaTrad) deriveKey(xof io.Reader) crypto.Signer {
+	e := big.NewInt(65537)
+	one := big.NewInt(1)
+	for {
+		p := derivePrime(xof, r.bits/2, e)
+		q := derivePrime(xof, r.bits/2, e)
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		// d = e^-1 mod lcm(p-1, q-1).
+		pm1 := new(big.Int).Sub(p, one)
+		qm1 := new(big.Int).Sub(q, one)
+		gcd := new(big.Int).GCD(nil, nil, pm1, qm1)
+		lambda := new(big.Int).Mul(pm1, qm1)
+		lambda.Div(lambda, gcd)
+
+		priv := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: new(big.Int).Mul(p, q), E: 65537},
+			D:         new(big.Int).ModInverse(e, lambda),
+			Primes:    []*big.Int{p, q},
+		}
+		priv.Precompute()
+		if priv.Validate() == nil {
+			return priv
+		}
+	}
+}
+
+// derivePrime returns a prime p of the given size, such that p-1 is coprime
+// with e. Its two most significant bits are set, so that the product of
+// two such primes has twice the size.
+func derivePrime(xof io.Reader, bits int, e *big.Int) *big.Int {
+	b := make([]byte, bits/8)
+	p := new(big.Int)
+	t := new(big.Int)
+	for {
+		_, _ = io.ReadFull(xof, b)
+		b[0] |= 0xC0
+		b[len(b)-1] |= 1
+		p.SetBytes(b)
+		if p.ProbablyPrime(20) && t.GCD(nil, nil, t.Sub(p, big.NewInt(1)), e).Cmp(big.NewInt(1)) == 0 {
+			return p
+		}
+	}
+}
+
+func (r rsaTrad) marshalPublicKey(pub crypto.PublicKey) []byte {
+	return x509.MarshalPKCS1PublicKey(pub.(*rsa.PublicKey))
+}
+
+func (r rsaTrad) unmarshalPublicKey(b []byte) (crypto.PublicKey, bool) {
+	pub, err := x509.ParsePKCS1PublicKey(b)
+	if err != nil || pub.N.BitLen() != r.bits {
+		return nil, false
+	}
+	return pub, true
+}
+
+func (r rsaTrad) marshalPrivateKey(priv crypto.Signer) []byte {
+	return x509.MarshalPKCS1PrivateKey(priv.(*rsa.PrivateKey))
+}
+
+func (r rsaTrad) unmarshalPrivateKey(b []byte) (crypto.Signer, bool) {
+	priv, err := x509.ParsePKCS1PrivateKey(b)
+	if err != nil || priv.N.BitLen() != r.bits {
+		return nil, false
+	}
+	return priv, true
+}
+
+func (r rsaTrad) sign(priv crypto.Signer, rand io.Reader, message []byte) ([]byte, error) {
+	h := r.hash.New()
+	_, _ = h.Write(message)
+	k := priv.(*rsa.PrivateKey)
+	if r.pss {
+		return rsa.SignPSS(rand, k, r.hash, h.Sum(nil), &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+		})
+	}
+	return rsa.SignPKCS1v15(rand, k, r.hash, h.Sum(nil))
+}
+
+func (r rsaTrad) verify(pub crypto.PublicKey, message, signature []byte) bool {
+	h := r.hash.New()
+	_, _ = h.Write(message)
+	k := pub.(*rsa.PublicKey)
+	if r.pss {
+		return rsa.VerifyPSS(k, r.hash, h.Sum(nil), signature, &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+		}) == nil
+	}
+	return rsa.VerifyPKCS1v15(k, r.hash, h.Sum(nil), signature) == nil
+}
+
+// publicKeySize is the size of an RSAPublicKey with exponent 65537.
+func (r rsaTrad) publicKeySize() int {
+	return derSize(derSize(r.bits/8+1) + derSize(3))
+}
+
+// privateKeySize is the maximum size of an RSAPrivateKey.
+func (r rsaTrad) privateKeySize() int {
+	n := derSize(r.bits/8 + 1)
+	half := derSize(r.bits/16 + 1)
+	// Version, n, e, d, p, q, d mod (p-1), d mod (q-1), and q^-1 mod p.
+	return derSize(derSize(1) + n + derSize(3) + n + 5*half)
+}
+
+func (r rsaTrad) signatureSize() int { return r.bits / 8 }
+
+// derSize returns the size of a DER encoding whose content is of the
+// given size.
+func derSize(content int) int {
+	switch {
+	case content < 0x80:
+		return 2 + content
+	case content < 0x100:
+		return 3 + content
+	default:
+		return 4 + content
+	}
+}
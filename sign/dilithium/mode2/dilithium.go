@@ -158,10 +158,10 @@ func (sk *PrivateKey) Seed() []byte {
 func (sk *PrivateKey) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) (
 	sig []byte, err error) {
 	var ret [SignatureSize]byte
-
 	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
 		return nil, errors.New("dilithium: cannot sign hashed message")
 	}
+
 	SignTo(sk, msg, ret[:])
 
 	return ret[:], nil
@@ -232,6 +232,9 @@ func (*scheme) Sign(
 	if opts != nil && opts.Context != "" {
 		panic(sign.ErrContextNotSupported)
 	}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	SignTo(priv, msg, sig)
 
 	return sig
@@ -249,6 +252,9 @@ func (*scheme) Verify(
 	if opts != nil && opts.Context != "" {
 		panic(sign.ErrContextNotSupported)
 	}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	return Verify(pub, msg, sig)
 }
 
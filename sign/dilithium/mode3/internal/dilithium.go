@@ -37,6 +37,9 @@ const (
 
 	// Size of packed w₁
 	PolyW1Size = (common.N * (common.QBits - Gamma1Bits)) / 8
+
+	// Size of the message representative μ
+	MuSize = 64
 )
 
 // PublicKey is the type of Dilithium public keys.
@@ -265,13 +268,53 @@ func (sk *PrivateKey) computeT0andT1(t0, t1 *VecK) {
 	t.Power2Round(t0, t1)
 }
 
+// Computes the message representative μ = CRH(tr ‖ msg).
+func computeMu(tr *[TRSize]byte, msg func(io.Writer), mu *[MuSize]byte) {
+	h := sha3.NewShake256()
+	_, _ = h.Write(tr[:])
+	msg(&h)
+	_, _ = h.Read(mu[:])
+}
+
+// ComputeMu returns the message representative μ = CRH(tr ‖ msg) of msg
+// for this public key.
+func (pk *PublicKey) ComputeMu(msg func(io.Writer)) [MuSize]byte {
+	var mu [MuSize]byte
+	computeMu(&pk.tr, msg, &mu)
+	return mu
+}
+
+// NewMuHash returns a SHAKE-256 instance that absorbed tr, from which the
+// message representative μ = CRH(tr ‖ msg) can be read after msg has been
+// written to it.
+func (pk *PublicKey) NewMuHash() *sha3.State {
+	h := sha3.NewShake256()
+	_, _ = h.Write(pk.tr[:])
+	return &h
+}
+
+// NewMuHash returns a SHAKE-256 instance that absorbed tr, from which the
+// message representative μ = CRH(tr ‖ msg) can be read after msg has been
+// written to it.
+func (sk *PrivateKey) NewMuHash() *sha3.State {
+	h := sha3.NewShake256()
+	_, _ = h.Write(sk.tr[:])
+	return &h
+}
+
 // Verify checks whether the given signature by pk on msg is valid.
 //
 // For Dilithium this is the top-level verification function.
 // In ML-DSA, this is ML-DSA.Verify_internal.
 func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
+	mu := pk.ComputeMu(msg)
+	return VerifyMu(pk, &mu, signature)
+}
+
+// VerifyMu checks whether the given signature by pk on the message with
+// representative μ is valid.
+func VerifyMu(pk *PublicKey, mu *[MuSize]byte, signature []byte) bool {
 	var sig unpackedSignature
-	var mu [64]byte
 	var zh VecL
 	var Az, Az2dct1, w1 VecK
 	var ch common.Poly
@@ -284,12 +327,6 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 		return false
 	}
 
-	// μ = CRH(tr ‖ msg)
-	h := sha3.NewShake256()
-	_, _ = h.Write(pk.tr[:])
-	msg(&h)
-	_, _ = h.Read(mu[:])
-
 	// Compute Az
 	zh = sig.z
 	zh.NTT()
@@ -322,7 +359,7 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 	w1.PackW1(w1Packed[:])
 
 	// c' = H(μ, w₁)
-	h.Reset()
+	h := sha3.NewShake256()
 	_, _ = h.Write(mu[:])
 	_, _ = h.Write(w1Packed[:])
 	_, _ = h.Read(cp[:])
@@ -334,10 +371,20 @@ func Verify(pk *PublicKey, msg func(io.Writer), signature []byte) bool {
 //
 // For Dilithium this is the top-level signing function. For ML-DSA
 // this is ML-DSA.Sign_internal.
+func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte) {
+	var mu [MuSize]byte
+
+	//  μ = CRH(tr ‖ msg)
+	computeMu(&sk.tr, msg, &mu)
+	SignMuTo(sk, &mu, rnd, signature)
+}
+
+// SignMuTo signs the message with representative μ and writes the signature
+// into signature.
 //
 //nolint:funlen
-func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte) {
-	var mu, rhop [64]byte
+func SignMuTo(sk *PrivateKey, mu *[MuSize]byte, rnd [32]byte, signature []byte) {
+	var rhop [64]byte
 	var w1Packed [PolyW1Size * K]byte
 	var y, yh VecL
 	var w, w0, w1, w0mcs2, ct0, w0mcs2pct0 VecK
@@ -349,14 +396,8 @@ func SignTo(sk *PrivateKey, msg func(io.Writer), rnd [32]byte, signature []byte)
 		panic("Signature does not fit in that byteslice")
 	}
 
-	//  μ = CRH(tr ‖ msg)
-	h := sha3.NewShake256()
-	_, _ = h.Write(sk.tr[:])
-	msg(&h)
-	_, _ = h.Read(mu[:])
-
 	// ρ' = CRH(key ‖ μ)
-	h.Reset()
+	h := sha3.NewShake256()
 	_, _ = h.Write(sk.key[:])
 	if NIST {
 		_, _ = h.Write(rnd[:])
@@ -8,10 +8,14 @@ package {{.Pkg}}
 
 import (
 	"bytes"
+	"crypto"
 	"encoding/json"
 	"testing"
 
 	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/mldsa/{{.Pkg}}/internal"
+	"github.com/cloudflare/circl/xof"
 )
 
 func TestACVP(t *testing.T) {
@@ -21,14 +25,49 @@ func TestACVP(t *testing.T) {
 		"sigVer",
 	} {
 		t.Run(sub, func(t *testing.T) {
-			testACVP(t, sub)
+			testACVP(t, "../testdata/ML-DSA-"+sub+"-FIPS204", sub)
 		})
 	}
+
+	// Pre-hash and external μ vectors in the same format, which are not from
+	// NIST, see testdata/crosscheck/README.md.
+	for _, v := range []struct{ name, dir, sub string }{
+		{"HashML-DSA/sigGen", "crosscheck/HashML-DSA-sigGen", "sigGen"},
+		{"HashML-DSA/sigVer", "crosscheck/HashML-DSA-sigVer", "sigVer"},
+		{"externalMu/sigGen", "crosscheck/externalMu-sigGen", "sigGen"},
+		{"externalMu/sigVer", "crosscheck/externalMu-sigVer", "sigVer"},
+	} {
+		t.Run(v.name, func(t *testing.T) {
+			testACVP(t, "../testdata/"+v.dir, v.sub)
+		})
+	}
+}
+
+// Returns the pre-hash function for the given ACVP hashAlg.
+func acvpPreHash(t *testing.T, hashAlg string) sign.PreHash {
+	ph, ok := map[string]sign.PreHash{
+		"SHA2-224":     {Hash: crypto.SHA224},
+		"SHA2-256":     {Hash: crypto.SHA256},
+		"SHA2-384":     {Hash: crypto.SHA384},
+		"SHA2-512":     {Hash: crypto.SHA512},
+		"SHA2-512/224": {Hash: crypto.SHA512_224},
+		"SHA2-512/256": {Hash: crypto.SHA512_256},
+		"SHA3-224":     {Hash: crypto.SHA3_224},
+		"SHA3-256":     {Hash: crypto.SHA3_256},
+		"SHA3-384":     {Hash: crypto.SHA3_384},
+		"SHA3-512":     {Hash: crypto.SHA3_512},
+		"SHAKE-128":    {Xof: xof.SHAKE128},
+		"SHAKE-256":    {Xof: xof.SHAKE256},
+	}[hashAlg]
+	if !ok {
+		t.Fatalf("unknown hash algorithm %s", hashAlg)
+	}
+	return ph
 }
 
 // nolint:funlen,gocyclo
-func testACVP(t *testing.T, sub string) {
-	buf, err := test.ReadGzip("../testdata/ML-DSA-" + sub + "-FIPS204/prompt.json.gz")
+func testACVP(t *testing.T, dir, sub string) {
+	buf, err := test.ReadGzip(dir + "/prompt.json.gz")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -41,7 +80,7 @@ func testACVP(t *testing.T, sub string) {
 		t.Fatal(err)
 	}
 
-	buf, err = test.ReadGzip("../testdata/ML-DSA-" + sub + "-FIPS204/expectedResults.json.gz")
+	buf, err = test.ReadGzip(dir + "/expectedResults.json.gz")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -140,11 +179,16 @@ func testACVP(t *testing.T, sub string) {
 				TgID          int    `json:"tgId"`
 				ParameterSet  string `json:"parameterSet"`
 				Deterministic bool   `json:"deterministic"`
+				PreHash       string `json:"preHash"`
+				ExternalMu    bool   `json:"externalMu"`
 				Tests         []struct {
 					TcID    int           `json:"tcId"`
 					Sk      test.HexBytes `json:"sk"`
 					Message test.HexBytes `json:"message"`
+					Mu      test.HexBytes `json:"mu"`
 					Rnd     test.HexBytes `json:"rnd"`
+					Context test.HexBytes `json:"context"`
+					HashAlg string        `json:"hashAlg"`
 				}
 			}
 			if err := json.Unmarshal(rawGroup, &group); err != nil {
@@ -172,12 +216,53 @@ func testACVP(t *testing.T, sub string) {
 					t.Fatal(err)
 				}
 
-				var rnd [32]byte
-				if !group.Deterministic {
-					copy(rnd[:], tst.Rnd)
-				}
+				var sig2 []byte
+				if group.ExternalMu {
+					priv := sk.(*PrivateKey)
+					var mu [MuSize]byte
+					copy(mu[:], tst.Mu)
+
+					if tst.Message != nil {
+						pub := priv.Public().(*PublicKey)
+						mu2, err := pub.ComputeMu(tst.Message, tst.Context)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if mu2 != mu {
+							t.Fatalf("mu doesn't match: %x ≠ %x", mu2, mu)
+						}
+					}
 
-				sig2 := sk.(*PrivateKey).unsafeSignInternal(tst.Message, rnd)
+					sig2 = make([]byte, SignatureSize)
+					if group.Deterministic {
+						err = priv.SignMuTo(&mu, false, sig2)
+						if err != nil {
+							t.Fatal(err)
+						}
+					} else {
+						var rnd [32]byte
+						copy(rnd[:], tst.Rnd)
+						internal.SignMuTo((*internal.PrivateKey)(priv), &mu, rnd, sig2)
+					}
+				} else if group.PreHash == "preHash" {
+					if !group.Deterministic {
+						t.Fatal("randomized pre-hash tests are not supported")
+					}
+					ph := acvpPreHash(t, tst.HashAlg)
+					sig2 = make([]byte, SignatureSize)
+					err = SignPreHashTo(sk.(*PrivateKey), ph,
+						preHash(ph, tst.Message), tst.Context, false, sig2)
+					if err != nil {
+						t.Fatal(err)
+					}
+				} else {
+					var rnd [32]byte
+					if !group.Deterministic {
+						copy(rnd[:], tst.Rnd)
+					}
+
+					sig2 = sk.(*PrivateKey).unsafeSignInternal(tst.Message, rnd)
+				}
 
 				if !bytes.Equal(sig2, result.Signature) {
 					t.Fatalf("signature doesn't match: %x ≠ %x",
@@ -188,11 +273,16 @@ func testACVP(t *testing.T, sub string) {
 			var group struct {
 				TgID         int           `json:"tgId"`
 				ParameterSet string        `json:"parameterSet"`
+				PreHash      string        `json:"preHash"`
+				ExternalMu   bool          `json:"externalMu"`
 				Pk           test.HexBytes `json:"pk"`
 				Tests        []struct {
 					TcID      int           `json:"tcId"`
 					Message   test.HexBytes `json:"message"`
+					Mu        test.HexBytes `json:"mu"`
 					Signature test.HexBytes `json:"signature"`
+					Context   test.HexBytes `json:"context"`
+					HashAlg   string        `json:"hashAlg"`
 				}
 			}
 			if err := json.Unmarshal(rawGroup, &group); err != nil {
@@ -220,7 +310,18 @@ func testACVP(t *testing.T, sub string) {
 					t.Fatal(err)
 				}
 
-				passed2 := unsafeVerifyInternal(pk.(*PublicKey), tst.Message, tst.Signature)
+				var passed2 bool
+				if group.ExternalMu {
+					var mu [MuSize]byte
+					copy(mu[:], tst.Mu)
+					passed2 = pk.(*PublicKey).VerifyMu(&mu, tst.Signature)
+				} else if group.PreHash == "preHash" {
+					ph := acvpPreHash(t, tst.HashAlg)
+					passed2 = VerifyPreHash(pk.(*PublicKey), ph,
+						preHash(ph, tst.Message), tst.Context, tst.Signature)
+				} else {
+					passed2 = unsafeVerifyInternal(pk.(*PublicKey), tst.Message, tst.Signature)
+				}
 				if passed2 != result.TestPassed {
 					t.Fatalf("verification %v ≠ %v", passed2, result.TestPassed)
 				}
@@ -27,6 +27,9 @@ import (
 {{- end }}
 
 	"github.com/cloudflare/circl/sign"
+{{- if .NIST }}
+	"github.com/cloudflare/circl/sign/internal/stream"
+{{- end }}
 
 {{- if .NIST }}
 	"github.com/cloudflare/circl/sign/mldsa/{{.Pkg}}/internal"
@@ -48,6 +51,11 @@ const (
 
 	// Size of a signature
 	SignatureSize = internal.SignatureSize
+{{- if .NIST }}
+
+	// Size of the message representative μ
+	MuSize = internal.MuSize
+{{- end }}
 )
 
 // PublicKey is the type of {{.Name}} public key
@@ -118,6 +126,147 @@ func SignTo(sk *PrivateKey, msg, sig []byte) {
 }
 
 {{- if .NIST }}
+// SignPreHashTo signs the digest of a message using HashML-DSA and writes
+// the signature into sig. ph selects the function used to compute digest.
+// It will panic if sig is not of length at least SignatureSize.
+//
+// ctx is the optional context string. Errors if ctx is larger than 255 bytes,
+// if ph is not supported, or if digest is not of the output size of ph.
+func SignPreHashTo(
+	sk *PrivateKey,
+	ph sign.PreHash,
+	digest, ctx []byte,
+	randomized bool,
+	sig []byte,
+) error {
+	var rnd [32]byte
+
+	msg, err := common.PreHashMessage(ph, digest, ctx)
+	if err != nil {
+		return err
+	}
+
+	if randomized {
+		_, err = cryptoRand.Read(rnd[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	internal.SignTo(
+		(*internal.PrivateKey)(sk),
+		func(w io.Writer) {
+			_, _ = w.Write(msg)
+		},
+		rnd,
+		sig,
+	)
+	return nil
+}
+
+// VerifyPreHash checks whether the given HashML-DSA signature by pk on the
+// digest of a message is valid. ph selects the function used to compute
+// digest.
+//
+// ctx is the optional context string. Fails if ctx is larger than 255 bytes,
+// if ph is not supported, or if digest is not of the output size of ph.
+func VerifyPreHash(pk *PublicKey, ph sign.PreHash, digest, ctx, sig []byte) bool {
+	msg, err := common.PreHashMessage(ph, digest, ctx)
+	if err != nil {
+		return false
+	}
+	return internal.Verify(
+		(*internal.PublicKey)(pk),
+		func(w io.Writer) {
+			_, _ = w.Write(msg)
+		},
+		sig,
+	)
+}
+
+// ComputeMu returns the message representative μ of a pure ML-DSA signature
+// by pk on msg, which can be signed with SignMuTo.
+//
+// ctx is the optional context string. Errors if ctx is larger than 255 bytes.
+// A nil context string is equivalent to an empty context string.
+func (pk *PublicKey) ComputeMu(msg, ctx []byte) (mu [MuSize]byte, err error) {
+	if len(ctx) > 255 {
+		return mu, sign.ErrContextTooLong
+	}
+
+	return (*internal.PublicKey)(pk).ComputeMu(
+		func(w io.Writer) {
+			_, _ = w.Write([]byte{0})
+			_, _ = w.Write([]byte{byte(len(ctx))})
+			_, _ = w.Write(ctx)
+			_, _ = w.Write(msg)
+		},
+	), nil
+}
+
+// ComputePreHashMu returns the message representative μ of a HashML-DSA
+// signature by pk on the digest of a message, which can be signed with
+// SignMuTo. ph selects the function used to compute digest.
+//
+// ctx is the optional context string. Errors if ctx is larger than 255 bytes,
+// if ph is not supported, or if digest is not of the output size of ph.
+func (pk *PublicKey) ComputePreHashMu(
+	ph sign.PreHash,
+	digest, ctx []byte,
+) (mu [MuSize]byte, err error) {
+	msg, err := common.PreHashMessage(ph, digest, ctx)
+	if err != nil {
+		return mu, err
+	}
+
+	return (*internal.PublicKey)(pk).ComputeMu(
+		func(w io.Writer) {
+			_, _ = w.Write(msg)
+		},
+	), nil
+}
+
+// SignMuTo signs the message with representative mu and writes the
+// signature into sig. It will panic if sig is not of length at least
+// SignatureSize.
+//
+// FIPS 204 allows μ to be computed separately from signing, for instance
+// with ComputeMu or ComputePreHashMu on the corresponding public key, so
+// that only μ has to be sent to the holder of the private key. The result
+// is a regular ML-DSA or HashML-DSA signature. The caller must ensure that
+// mu was computed for the public key of sk, as otherwise the signature
+// will not verify.
+func (sk *PrivateKey) SignMuTo(mu *[MuSize]byte, randomized bool, sig []byte) error {
+	var rnd [32]byte
+	if randomized {
+		_, err := cryptoRand.Read(rnd[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	internal.SignMuTo((*internal.PrivateKey)(sk), mu, rnd, sig)
+	return nil
+}
+
+// VerifyMu checks whether the given signature by pk on the message with
+// representative mu is valid.
+func (pk *PublicKey) VerifyMu(mu *[MuSize]byte, sig []byte) bool {
+	return internal.VerifyMu((*internal.PublicKey)(pk), mu, sig)
+}
+
+// Hashes msg with the function selected by ph for use with HashML-DSA.
+//
+// Panics if ph is not supported.
+func preHash(ph sign.PreHash, msg []byte) []byte {
+	h, err := common.NewPreHasher(ph)
+	if err != nil {
+		panic(err)
+	}
+	_, _ = h.Write(msg)
+	return h.Sum()
+}
+
 // Do not use. Implements ML-DSA.Sign_internal used for compatibility tests.
 func (sk *PrivateKey) unsafeSignInternal(msg []byte, rnd [32]byte) []byte {
 	var ret [SignatureSize]byte
@@ -247,9 +396,17 @@ func (sk *PrivateKey) Seed() []byte {
 
 // Sign signs the given message.
 //
+{{- if .NIST }}
+// If opts.HashFunc() is non-zero, msg must be the digest of the message
+// computed with that hash function, and a HashML-DSA signature is created.
+// Otherwise, which can be achieved by passing crypto.Hash(0) or nil for opts,
+// msg is signed directly. rand is ignored. Will only return an error if
+// the hash function is not supported or msg is not of its output size.
+{{- else }}
 // opts.HashFunc() must return zero, which can be achieved by passing
 // crypto.Hash(0) or nil for opts.  rand is ignored.  Will only return an error
 // if opts.HashFunc() is non-zero.
+{{- end }}
 //
 // This function is used to make PrivateKey implement the crypto.Signer
 // interface.  The package-level SignTo function might be more convenient
@@ -258,15 +415,21 @@ func (sk *PrivateKey) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) (
 	sig []byte, err error) {
 	var ret [SignatureSize]byte
 
+	{{- if .NIST }}
 	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
-		return nil, errors.New("dilithium: cannot sign hashed message")
+		ph := sign.PreHash{Hash: opts.HashFunc()}
+		err = SignPreHashTo(sk, ph, msg, nil, false, ret[:])
+	} else {
+		err = SignTo(sk, msg, nil, false, ret[:])
 	}
-
-	{{- if .NIST }}
-	if err = SignTo(sk, msg, nil, false, ret[:]); err != nil {
+	if err != nil {
 		return nil, err
 	}
 	{{- else }}
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("dilithium: cannot sign hashed message")
+	}
+
 	SignTo(sk, msg, ret[:])
 	{{- end }}
 
@@ -303,10 +466,20 @@ func (pk *PublicKey) Equal(other crypto.PublicKey) bool {
 // Boilerplate for generic signatures API
 
 type scheme struct{}
+
+{{- if .NIST }}
+var sch sign.StreamingScheme = &scheme{}
+
+// Scheme returns a generic signature interface for {{ .Name }}.
+//
+// The scheme also implements sign.StreamingScheme.
+func Scheme() sign.Scheme { return sch }
+{{- else }}
 var sch sign.Scheme = &scheme{}
 
 // Scheme returns a generic signature interface for {{ .Name }}.
 func Scheme() sign.Scheme { return sch }
+{{- end }}
 
 func (*scheme) Name() string { return "{{ .Name }}" }
 func (*scheme) PublicKeySize() int { return PublicKeySize }
@@ -356,11 +529,20 @@ func (*scheme) Sign(
 	}
 
 	{{- if .NIST }}
-	err := SignTo(priv, msg, ctx, false, sig)
+	var err error
+	if opts != nil && !opts.PreHash.IsZero() {
+		digest := preHash(opts.PreHash, msg)
+		err = SignPreHashTo(priv, opts.PreHash, digest, ctx, false, sig)
+	} else {
+		err = SignTo(priv, msg, ctx, false, sig)
+	}
 	if err != nil {
 		panic(err)
 	}
 	{{- else }}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	SignTo(priv, msg, sig)
 	{{ end }}
 
@@ -387,12 +569,117 @@ func (*scheme) Verify(
 		{{- end }}
 	}
 	{{- if .NIST }}
+	if opts != nil && !opts.PreHash.IsZero() {
+		digest := preHash(opts.PreHash, msg)
+		return VerifyPreHash(pub, opts.PreHash, digest, ctx, sig)
+	}
 	return Verify(pub, msg, ctx, sig)
 	{{- else }}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	return Verify(pub, msg, sig)
 	{{- end }}
 }
 
+{{- if .NIST }}
+
+// NewStreamSigner returns a signer for ML-DSA with the context of opts or,
+// if opts selects a pre-hash function, for HashML-DSA. The message is hashed
+// as it is written, so it is not buffered in memory. Like the ones of Sign,
+// the signatures are deterministic.
+//
+// Panics if sk is not a [PrivateKey], if the context is longer than 255
+// bytes, or if the pre-hash function is not supported.
+func (*scheme) NewStreamSigner(
+	sk sign.PrivateKey,
+	opts *sign.SignatureOpts,
+) sign.StreamSigner {
+	var ctx []byte
+	priv, ok := sk.(*PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		ctx = []byte(opts.Context)
+	}
+	if len(ctx) > 255 {
+		panic(sign.ErrContextTooLong)
+	}
+
+	if opts != nil && !opts.PreHash.IsZero() {
+		ph := opts.PreHash
+		h, err := common.NewPreHasher(ph)
+		if err != nil {
+			panic(err)
+		}
+		return &stream.Signer{Writer: h, SignFunc: func() []byte {
+			sig := make([]byte, SignatureSize)
+			err := SignPreHashTo(priv, ph, h.Sum(), ctx, false, sig)
+			if err != nil {
+				panic(err)
+			}
+			return sig
+		}}
+	}
+
+	h := (*internal.PrivateKey)(priv).NewMuHash()
+	_, _ = h.Write([]byte{0, byte(len(ctx))})
+	_, _ = h.Write(ctx)
+	return &stream.Signer{Writer: h, SignFunc: func() []byte {
+		var mu [MuSize]byte
+		_, _ = h.Read(mu[:])
+		sig := make([]byte, SignatureSize)
+		internal.SignMuTo((*internal.PrivateKey)(priv), &mu, [32]byte{}, sig)
+		return sig
+	}}
+}
+
+// NewStreamVerifier returns a verifier for ML-DSA signatures with the
+// context of opts or, if opts selects a pre-hash function, for HashML-DSA
+// signatures. The message is hashed as it is written, so it is not buffered
+// in memory.
+//
+// Panics if pk is not a [PublicKey], if the context is longer than 255
+// bytes, or if the pre-hash function is not supported.
+func (*scheme) NewStreamVerifier(
+	pk sign.PublicKey,
+	opts *sign.SignatureOpts,
+) sign.StreamVerifier {
+	var ctx []byte
+	pub, ok := pk.(*PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if opts != nil && opts.Context != "" {
+		ctx = []byte(opts.Context)
+	}
+	if len(ctx) > 255 {
+		panic(sign.ErrContextTooLong)
+	}
+
+	if opts != nil && !opts.PreHash.IsZero() {
+		ph := opts.PreHash
+		h, err := common.NewPreHasher(ph)
+		if err != nil {
+			panic(err)
+		}
+		return &stream.Verifier{Writer: h, VerifyFunc: func(sig []byte) bool {
+			return VerifyPreHash(pub, ph, h.Sum(), ctx, sig)
+		}}
+	}
+
+	h := (*internal.PublicKey)(pub).NewMuHash()
+	_, _ = h.Write([]byte{0, byte(len(ctx))})
+	_, _ = h.Write(ctx)
+	return &stream.Verifier{Writer: h, VerifyFunc: func(sig []byte) bool {
+		var mu [MuSize]byte
+		_, _ = h.Read(mu[:])
+		return internal.VerifyMu((*internal.PublicKey)(pub), &mu, sig)
+	}}
+}
+{{- end }}
+
 func (*scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
 	if len(seed) != SeedSize {
 		panic(sign.ErrSeedSize)
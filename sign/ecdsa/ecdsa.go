@@ -0,0 +1,183 @@
+// Package ecdsa provides ECDSA over the NIST curves P-256, P-384 and P-521
+// as signature schemes of the sign package.
+//
+// Each instance pairs a curve with a hash function, as the signature
+// algorithms of TLS 1.3 do: P-256 with SHA-256, P-384 with SHA-384, and
+// P-521 with SHA-512. The arithmetic is done by crypto/ecdsa, and keys can
+// be converted from and to its types with NewPublicKey, NewPrivateKey and
+// the ECDSA methods.
+//
+// Signatures are encoded in ASN.1 DER, as in X.509 and TLS. Nonces are
+// random by default, and are derived deterministically as in RFC 6979 when
+// signing with a nil random source or with the scheme returned by
+// DeterministicScheme.
+//
+// References:
+//   - FIPS 186-5: https://doi.org/10.6028/NIST.FIPS.186-5
+//   - RFC 6979: https://www.rfc-editor.org/rfc/rfc6979
+//   - RFC 5758: https://www.rfc-editor.org/rfc/rfc5758
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptoRand "crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/xof"
+)
+
+// ID identifies an instance of ECDSA.
+type ID byte
+
+const (
+	// P256_SHA256 is ECDSA over P-256 with SHA-256.
+	P256_SHA256 ID = iota + 1 //nolint:stylecheck
+	// P384_SHA384 is ECDSA over P-384 with SHA-384.
+	P384_SHA384 //nolint:stylecheck
+	// P521_SHA512 is ECDSA over P-521 with SHA-512.
+	P521_SHA512 //nolint:stylecheck
+)
+
+var (
+	// ErrID is returned for an unsupported ID or curve.
+	ErrID = errors.New("sign/ecdsa: unsupported curve")
+
+	// ErrKey is returned when a key is not valid.
+	ErrKey = errors.New("sign/ecdsa: invalid key")
+)
+
+type params struct {
+	id       ID
+	name     string
+	curve    elliptic.Curve
+	ecdh     ecdh.Curve
+	hash     crypto.Hash
+	size     int // Size of field elements and scalars in bytes.
+	seedSize int
+	oid      asn1.ObjectIdentifier
+	tls      uint
+}
+
+var allParams = [...]params{
+	{
+		id:       P256_SHA256,
+		name:     "ECDSA-P256-SHA256",
+		curve:    elliptic.P256(),
+		ecdh:     ecdh.P256(),
+		hash:     crypto.SHA256,
+		size:     32,
+		seedSize: 32,
+		oid:      asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2},
+		tls:      0x0403,
+	},
+	{
+		id:       P384_SHA384,
+		name:     "ECDSA-P384-SHA384",
+		curve:    elliptic.P384(),
+		ecdh:     ecdh.P384(),
+		hash:     crypto.SHA384,
+		size:     48,
+		seedSize: 48,
+		oid:      asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3},
+		tls:      0x0503,
+	},
+	{
+		id:       P521_SHA512,
+		name:     "ECDSA-P521-SHA512",
+		curve:    elliptic.P521(),
+		ecdh:     ecdh.P521(),
+		hash:     crypto.SHA512,
+		size:     66,
+		seedSize: 64,
+		oid:      asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4},
+		tls:      0x0603,
+	},
+}
+
+func (id ID) params() *params {
+	if id < P256_SHA256 || id > P521_SHA512 {
+		panic(ErrID)
+	}
+	return &allParams[id-1]
+}
+
+// String returns the name of the instance.
+func (id ID) String() string { return id.params().name }
+
+// idByCurve returns the ID of the instance over the given curve.
+func idByCurve(curve elliptic.Curve) (ID, error) {
+	for i := range allParams {
+		if allParams[i].curve == curve {
+			return allParams[i].id, nil
+		}
+	}
+	return 0, ErrID
+}
+
+// GenerateKey generates a pair of keys for the given instance using entropy
+// from rand. If rand is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader, id ID) (*PublicKey, *PrivateKey, error) {
+	p := id.params()
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+	key, err := ecdsa.GenerateKey(p.curve, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv := &PrivateKey{id, key}
+	return priv.PublicKey(), priv, nil
+}
+
+// NewKeyFromSeed deterministically derives a pair of keys from a seed. The
+// seed is expanded with SHAKE256, and 64 extra bits are reduced modulo the
+// order of the curve, so that the bias is negligible.
+//
+// Panics if the seed does not have the size given by the scheme.
+func NewKeyFromSeed(seed []byte, id ID) (*PublicKey, *PrivateKey) {
+	p := id.params()
+	if len(seed) != p.seedSize {
+		panic(sign.ErrSeedSize)
+	}
+
+	x := xof.SHAKE256.New()
+	_, _ = x.Write(seed)
+	b := make([]byte, p.size+8)
+	_, _ = io.ReadFull(x, b)
+
+	one := big.NewInt(1)
+	nm1 := new(big.Int).Sub(p.curve.Params().N, one)
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, nm1).Add(d, one)
+
+	priv, err := p.newPrivateKey(d.FillBytes(make([]byte, p.size)))
+	if err != nil {
+		panic(err)
+	}
+	return priv.PublicKey(), priv
+}
+
+// Sign returns the DER-encoded signature of the message, which is hashed
+// with the hash function of the key's instance. If rand is nil, the nonce
+// is derived deterministically as in RFC 6979.
+func Sign(rand io.Reader, priv *PrivateKey, message []byte) ([]byte, error) {
+	p := priv.id.params()
+	h := p.hash.New()
+	_, _ = h.Write(message)
+	return priv.key.Sign(rand, h.Sum(nil), p.hash)
+}
+
+// Verify returns whether signature is a valid DER-encoded signature of
+// the message.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	h := pub.id.params().hash.New()
+	_, _ = h.Write(message)
+	return ecdsa.VerifyASN1(pub.key, h.Sum(nil), signature)
+}
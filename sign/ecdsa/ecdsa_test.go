@@ -0,0 +1,189 @@
+package ecdsa_test
+
+import (
+	"bytes"
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/ecdsa"
+)
+
+var allIDs = []ecdsa.ID{
+	ecdsa.P256_SHA256,
+	ecdsa.P384_SHA384,
+	ecdsa.P521_SHA512,
+}
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestRFC6979 checks the deterministic signatures of the message "sample"
+// given in Appendix A.2 of RFC 6979.
+func TestRFC6979(t *testing.T) {
+	for _, v := range []struct {
+		id      ecdsa.ID
+		x, r, s string
+	}{
+		{
+			ecdsa.P256_SHA256,
+			"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			"efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716",
+			"f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8",
+		},
+		{
+			ecdsa.P384_SHA384,
+			"6b9d3dad2e1b8c1c05b19875b6659f4de23c3b667bf297ba9aa47740787137d8" +
+				"96d5724e4c70a825f872c9ea60d2edf5",
+			"94edbb92a5ecb8aad4736e56c691916b3f88140666ce9fa73d64c4ea95ad133c" +
+				"81a648152e44acf96e36dd1e80fabe46",
+			"99ef4aeb15f178cea1fe40db2603138f130e740a19624526203b6351d0a3a94f" +
+				"a329c145786e679e7b82c71a38628ac8",
+		},
+		{
+			ecdsa.P521_SHA512,
+			"00fad06daa62ba3b25d2fb40133da757205de67f5bb0018fee8c86e1b68c7e75" +
+				"caa896eb32f1f47c70855836a6d16fcc1466f6d8fbec67db89ec0c08b0e996b8" +
+				"3538",
+			"00c328fafcbd79dd77850370c46325d987cb525569fb63c5d3bc53950e6d4c5f" +
+				"174e25a1ee9017b5d450606add152b534931d7d4e8455cc91f9b15bf05ec36e3" +
+				"77fa",
+			"00617cce7cf5064806c467f678d3b4080d6f1cc50af26ca209417308281b68af" +
+				"282623eaa63e5b5c0723d8b8c37ff0777b1a20f8ccb1dccc43997f1ee0e44da4" +
+				"a67a",
+		},
+	} {
+		t.Run(v.id.String(), func(t *testing.T) {
+			scheme := v.id.DeterministicScheme()
+			sk, err := scheme.UnmarshalBinaryPrivateKey(fromHex(v.x))
+			test.CheckNoErr(t, err, "UnmarshalBinaryPrivateKey failed")
+
+			want, _ := asn1.Marshal(struct{ R, S *big.Int }{
+				new(big.Int).SetBytes(fromHex(v.r)),
+				new(big.Int).SetBytes(fromHex(v.s)),
+			})
+			msg := []byte("sample")
+			got := scheme.Sign(sk, msg, nil)
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, got, want)
+			}
+
+			got, err = ecdsa.Sign(nil, sk.(*ecdsa.PrivateKey), msg)
+			test.CheckNoErr(t, err, "Sign failed")
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, got, want)
+			}
+
+			got, err = sk.Sign(nil, msg, crypto.Hash(0))
+			test.CheckNoErr(t, err, "Sign failed")
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, got, want)
+			}
+
+			pk := sk.(*ecdsa.PrivateKey).PublicKey()
+			test.CheckOk(scheme.Verify(pk, msg, want, nil), "Verify failed", t)
+			test.CheckOk(v.id.Scheme().Verify(pk, msg, want, nil), "Verify failed", t)
+		})
+	}
+}
+
+func TestECDSA(t *testing.T) {
+	for _, id := range allIDs {
+		t.Run(id.String(), func(t *testing.T) {
+			scheme := id.Scheme()
+			seed := make([]byte, scheme.SeedSize())
+			pk, sk := scheme.DeriveKey(seed)
+			pk2, sk2 := scheme.DeriveKey(seed)
+			test.CheckOk(pk.Equal(pk2) && sk.Equal(sk2), "DeriveKey is not deterministic", t)
+
+			msg := []byte("message")
+			sig1 := scheme.Sign(sk, msg, nil)
+			sig2 := scheme.Sign(sk, msg, nil)
+			test.CheckOk(!bytes.Equal(sig1, sig2), "signatures should be randomized", t)
+
+			det := id.DeterministicScheme()
+			sig3 := det.Sign(sk, msg, nil)
+			sig4 := det.Sign(sk, msg, nil)
+			test.CheckOk(bytes.Equal(sig3, sig4), "signatures should be deterministic", t)
+
+			for _, sig := range [][]byte{sig1, sig2, sig3} {
+				test.CheckOk(scheme.Verify(pk, msg, sig, nil), "Verify failed", t)
+				test.CheckOk(!scheme.Verify(pk, msg[1:], sig, nil), "Verify should fail", t)
+
+				// Interoperability with crypto/ecdsa.
+				pub := pk.(*ecdsa.PublicKey).ECDSA()
+				test.CheckOk(ecdsa.Verify(pk.(*ecdsa.PublicKey), msg, sig), "Verify failed", t)
+				test.CheckOk(stdVerify(id, pub, msg, sig), "crypto/ecdsa rejects signature", t)
+			}
+
+			pk3, err := ecdsa.NewPublicKey(pk.(*ecdsa.PublicKey).ECDSA())
+			test.CheckNoErr(t, err, "NewPublicKey failed")
+			sk3, err := ecdsa.NewPrivateKey(sk.(*ecdsa.PrivateKey).ECDSA())
+			test.CheckNoErr(t, err, "NewPrivateKey failed")
+			test.CheckOk(pk.Equal(pk3) && sk.Equal(sk3), "keys differ", t)
+			test.CheckOk(pk3.Scheme() == scheme, "wrong scheme", t)
+
+			func() {
+				defer func() {
+					if recover() != sign.ErrContextNotSupported {
+						t.Fatal("expected ErrContextNotSupported")
+					}
+				}()
+				scheme.Sign(sk, msg, &sign.SignatureOpts{Context: "A context"})
+			}()
+		})
+	}
+}
+
+// stdVerify verifies a signature with crypto/ecdsa.
+func stdVerify(id ecdsa.ID, pub *stdecdsa.PublicKey, msg, sig []byte) bool {
+	var digest []byte
+	switch id {
+	case ecdsa.P256_SHA256:
+		h := sha256.Sum256(msg)
+		digest = h[:]
+	case ecdsa.P384_SHA384:
+		h := sha512.Sum384(msg)
+		digest = h[:]
+	case ecdsa.P521_SHA512:
+		h := sha512.Sum512(msg)
+		digest = h[:]
+	}
+	return stdecdsa.VerifyASN1(pub, digest, sig)
+}
+
+func TestInvalidKeys(t *testing.T) {
+	for _, id := range allIDs {
+		t.Run(id.String(), func(t *testing.T) {
+			scheme := id.Scheme()
+			pk, sk, err := scheme.GenerateKey()
+			test.CheckNoErr(t, err, "GenerateKey failed")
+
+			b, _ := pk.MarshalBinary()
+			b[len(b)-1] ^= 1
+			_, err = scheme.UnmarshalBinaryPublicKey(b)
+			test.CheckIsErr(t, err, "point not on the curve accepted")
+
+			b, _ = sk.MarshalBinary()
+			_, err = scheme.UnmarshalBinaryPrivateKey(make([]byte, len(b)))
+			test.CheckIsErr(t, err, "zero scalar accepted")
+			for i := range b {
+				b[i] = 0xff
+			}
+			_, err = scheme.UnmarshalBinaryPrivateKey(b)
+			test.CheckIsErr(t, err, "scalar larger than the order accepted")
+		})
+	}
+}
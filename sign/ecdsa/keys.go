@@ -0,0 +1,140 @@
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"io"
+	"math/big"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// PublicKey is an ECDSA public key. It is encoded as an uncompressed point
+// as in SEC 1.
+type PublicKey struct {
+	id  ID
+	key *ecdsa.PublicKey
+}
+
+// PrivateKey is an ECDSA private key. It is encoded as a big-endian scalar
+// of the size of the field elements, as in SEC 1.
+type PrivateKey struct {
+	id  ID
+	key *ecdsa.PrivateKey
+}
+
+// NewPublicKey returns the public key of the instance over the curve of
+// pub.
+func NewPublicKey(pub *ecdsa.PublicKey) (*PublicKey, error) {
+	id, err := idByCurve(pub.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pub.ECDH(); err != nil {
+		return nil, ErrKey
+	}
+	return &PublicKey{id, pub}, nil
+}
+
+// NewPrivateKey returns the private key of the instance over the curve of
+// priv.
+func NewPrivateKey(priv *ecdsa.PrivateKey) (*PrivateKey, error) {
+	id, err := idByCurve(priv.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := priv.ECDH(); err != nil {
+		return nil, ErrKey
+	}
+	return &PrivateKey{id, priv}, nil
+}
+
+func (p *params) newPublicKey(b []byte) (*PublicKey, error) {
+	if _, err := p.ecdh.NewPublicKey(b); err != nil || b[0] != 4 {
+		return nil, ErrKey
+	}
+	return &PublicKey{p.id, &ecdsa.PublicKey{
+		Curve: p.curve,
+		X:     new(big.Int).SetBytes(b[1 : 1+p.size]),
+		Y:     new(big.Int).SetBytes(b[1+p.size:]),
+	}}, nil
+}
+
+func (p *params) newPrivateKey(b []byte) (*PrivateKey, error) {
+	k, err := p.ecdh.NewPrivateKey(b)
+	if err != nil {
+		return nil, ErrKey
+	}
+	pub, err := p.newPublicKey(k.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{p.id, &ecdsa.PrivateKey{
+		PublicKey: *pub.key,
+		D:         new(big.Int).SetBytes(b),
+	}}, nil
+}
+
+// ID returns the instance of the key.
+func (pk *PublicKey) ID() ID { return pk.id }
+
+// ECDSA returns the key as used by crypto/ecdsa.
+func (pk *PublicKey) ECDSA() *ecdsa.PublicKey { return pk.key }
+
+func (pk *PublicKey) Scheme() sign.Scheme { return pk.id.Scheme() }
+
+// Equal reports whether pk and other are the same key.
+func (pk *PublicKey) Equal(other crypto.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+	return ok && pk.id == o.id && pk.key.Equal(o.key)
+}
+
+// MarshalBinary returns the uncompressed point of the key.
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	k, err := pk.key.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	return k.Bytes(), nil
+}
+
+// ID returns the instance of the key.
+func (sk *PrivateKey) ID() ID { return sk.id }
+
+// ECDSA returns the key as used by crypto/ecdsa.
+func (sk *PrivateKey) ECDSA() *ecdsa.PrivateKey { return sk.key }
+
+func (sk *PrivateKey) Scheme() sign.Scheme { return sk.id.Scheme() }
+
+// PublicKey returns the public key corresponding to sk.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{sk.id, &sk.key.PublicKey}
+}
+
+// Public returns the public key corresponding to sk.
+func (sk *PrivateKey) Public() crypto.PublicKey { return sk.PublicKey() }
+
+// Equal reports whether sk and other are the same key.
+func (sk *PrivateKey) Equal(other crypto.PrivateKey) bool {
+	o, ok := other.(*PrivateKey)
+	return ok && sk.id == o.id && sk.key.Equal(o.key)
+}
+
+// MarshalBinary returns the scalar of the key.
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	return sk.key.D.FillBytes(make([]byte, sk.id.params().size)), nil
+}
+
+// Sign implements crypto.Signer. If opts.HashFunc() is zero, digest is
+// the message itself and is hashed with the hash function of the key's
+// instance. Otherwise, digest must be the output of opts.HashFunc(), as
+// for crypto/ecdsa. If rand is nil, the nonce is derived deterministically
+// as in RFC 6979.
+func (sk *PrivateKey) Sign(
+	rand io.Reader, digest []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	if opts.HashFunc() == crypto.Hash(0) {
+		return Sign(rand, sk, digest)
+	}
+	return sk.key.Sign(rand, digest, opts)
+}
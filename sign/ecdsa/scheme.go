@@ -0,0 +1,131 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// Scheme returns a generic signature interface for the instance. It signs
+// with random nonces.
+func (id ID) Scheme() sign.Scheme { return &allSchemes[id.params().id-1][0] }
+
+// DeterministicScheme returns a generic signature interface for the
+// instance that signs with nonces derived as in RFC 6979. It has the same
+// name, keys and identifiers as the one returned by Scheme, as signatures
+// cannot be told apart.
+func (id ID) DeterministicScheme() sign.Scheme {
+	return &allSchemes[id.params().id-1][1]
+}
+
+var allSchemes = [len(allParams)][2]scheme{
+	{{&allParams[0], false}, {&allParams[0], true}},
+	{{&allParams[1], false}, {&allParams[1], true}},
+	{{&allParams[2], false}, {&allParams[2], true}},
+}
+
+type scheme struct {
+	*params
+	deterministic bool
+}
+
+func (s *scheme) Name() string               { return s.name }
+func (s *scheme) PublicKeySize() int         { return 1 + 2*s.size }
+func (s *scheme) PrivateKeySize() int        { return s.size }
+func (s *scheme) SeedSize() int              { return s.seedSize }
+func (s *scheme) SupportsContext() bool      { return false }
+func (s *scheme) TLSIdentifier() uint        { return s.tls }
+func (s *scheme) Oid() asn1.ObjectIdentifier { return s.oid }
+
+// SignatureSize returns the maximum size of a DER-encoded signature.
+func (s *scheme) SignatureSize() int {
+	return derSize(2 * derSize(s.size+1))
+}
+
+// derSize returns the size of a DER element with content of the given size.
+func derSize(n int) int {
+	switch {
+	case n < 0x80:
+		return 2 + n
+	case n < 0x100:
+		return 3 + n
+	default:
+		return 4 + n
+	}
+}
+
+func (s *scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	return GenerateKey(rand.Reader, s.id)
+}
+
+func (s *scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	return NewKeyFromSeed(seed, s.id)
+}
+
+// Sign returns a DER-encoded signature of the message.
+//
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, or when
+// options sets a context or a pre-hash function, which are not supported.
+func (s *scheme) Sign(
+	sk sign.PrivateKey, message []byte, opts *sign.SignatureOpts,
+) []byte {
+	priv, ok := sk.(*PrivateKey)
+	if !ok || priv.id != s.id {
+		panic(sign.ErrTypeMismatch)
+	}
+	checkOpts(opts)
+
+	random := rand.Reader
+	if s.deterministic {
+		random = nil
+	}
+	sig, err := Sign(random, priv, message)
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+// Verify returns true if the signature of the message is valid.
+//
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, or when
+// options sets a context or a pre-hash function, which are not supported.
+func (s *scheme) Verify(
+	pk sign.PublicKey, message, signature []byte, opts *sign.SignatureOpts,
+) bool {
+	pub, ok := pk.(*PublicKey)
+	if !ok || pub.id != s.id {
+		panic(sign.ErrTypeMismatch)
+	}
+	checkOpts(opts)
+
+	return Verify(pub, message, signature)
+}
+
+// checkOpts panics if options sets a context or a pre-hash function.
+func checkOpts(opts *sign.SignatureOpts) {
+	if opts == nil {
+		return
+	}
+	if opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+	if !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
+}
+
+func (s *scheme) UnmarshalBinaryPublicKey(buf []byte) (sign.PublicKey, error) {
+	if len(buf) != s.PublicKeySize() {
+		return nil, sign.ErrPubKeySize
+	}
+	return s.newPublicKey(buf)
+}
+
+func (s *scheme) UnmarshalBinaryPrivateKey(buf []byte) (sign.PrivateKey, error) {
+	if len(buf) != s.PrivateKeySize() {
+		return nil, sign.ErrPrivKeySize
+	}
+	return s.newPrivateKey(buf)
+}
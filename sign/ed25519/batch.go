@@ -0,0 +1,254 @@
+package ed25519
+
+import (
+	cryptoRand "crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/cloudflare/circl/internal/conv"
+	"github.com/cloudflare/circl/math"
+)
+
+// batchSize is the maximum number of signatures checked by a single
+// multi-scalar multiplication.
+const batchSize = 128
+
+// VerifyBatch returns true if the signatures[i] of messages[i] under
+// publicKeys[i] are all valid for the Ed25519 variant. It returns false
+// if the slices have different lengths, and true for empty slices.
+//
+// The signatures are checked together with a randomized multi-scalar
+// multiplication, which is faster than calling Verify for each of them.
+// The batch equation is cofactored, so, unlike Verify, it accepts
+// signatures that only differ from a valid one by a point of small order.
+// Such signatures can only be crafted by the signer.
+func VerifyBatch(publicKeys []PublicKey, messages, signatures [][]byte) bool {
+	entries, ok := newBatch(publicKeys, messages, signatures)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < len(entries); i += batchSize {
+		for _, e := range entries[i:min(i+batchSize, len(entries))] {
+			if !e.ok {
+				return false
+			}
+		}
+		if !verifyBatch(entries[i:min(i+batchSize, len(entries))]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VerifyBatchEach returns whether the signatures[i] of messages[i] under
+// publicKeys[i] are valid for the Ed25519 variant, as VerifyBatch does, and
+// reports which ones are invalid.
+// When a batch fails, it is split in halves, which are checked
+// recursively, so the cost grows with the number of invalid signatures.
+// It returns nil if the slices have different lengths.
+func VerifyBatchEach(publicKeys []PublicKey, messages, signatures [][]byte) []bool {
+	entries, ok := newBatch(publicKeys, messages, signatures)
+	if !ok {
+		return nil
+	}
+
+	valid := make([]bool, len(entries))
+	for i := 0; i < len(entries); i += batchSize {
+		j := min(i+batchSize, len(entries))
+		bisectBatch(entries[i:j], valid[i:j])
+	}
+
+	return valid
+}
+
+// bisectBatch sets valid[i] to whether entries[i] is valid.
+func bisectBatch(entries []batchEntry, valid []bool) {
+	// Entries that cannot be parsed are invalid.
+	for i := range entries {
+		if !entries[i].ok {
+			if len(entries) > 1 {
+				bisectBatch(entries[:i], valid[:i])
+				bisectBatch(entries[i+1:], valid[i+1:])
+			}
+			return
+		}
+	}
+
+	if verifyBatch(entries) {
+		for i := range valid {
+			valid[i] = true
+		}
+		return
+	}
+
+	if n := len(entries); n > 1 {
+		bisectBatch(entries[:n/2], valid[:n/2])
+		bisectBatch(entries[n/2:], valid[n/2:])
+	}
+}
+
+// batchEntry stores a parsed signature.
+type batchEntry struct {
+	negA, negR pointR1      // -A and -R
+	s, k       [paramB]byte // S and k = H(R || A || M) mod order
+	ok         bool         // Whether the signature was parsed.
+}
+
+func newBatch(publicKeys []PublicKey, messages, signatures [][]byte) ([]batchEntry, bool) {
+	if len(publicKeys) != len(messages) || len(publicKeys) != len(signatures) {
+		return nil, false
+	}
+
+	entries := make([]batchEntry, len(publicKeys))
+	for i := range entries {
+		entries[i].ok = entries[i].parse(publicKeys[i], messages[i], signatures[i])
+	}
+	return entries, true
+}
+
+func (e *batchEntry) parse(public PublicKey, message, signature []byte) bool {
+	if len(public) != PublicKeySize ||
+		len(signature) != SignatureSize ||
+		!isLessThanOrder(signature[paramB:]) {
+		return false
+	}
+
+	R := signature[:paramB]
+	if !e.negA.FromBytes(public) || !e.negR.FromBytes(R) {
+		return false
+	}
+	e.negA.neg()
+	e.negR.neg()
+
+	H := sha512.New()
+	_, _ = H.Write(R)
+	_, _ = H.Write(public)
+	_, _ = H.Write(message)
+	hRAM := H.Sum(nil)
+	reduceModOrder(hRAM, true)
+	copy(e.k[:], hRAM[:paramB])
+	copy(e.s[:], signature[paramB:])
+	return true
+}
+
+// verifyBatch returns true if
+//
+//	[8]([Σ z_i*s_i]B - Σ [z_i]R_i - Σ [z_i*k_i]A_i) = 0
+//
+// for random 128-bit scalars z_i.
+func verifyBatch(entries []batchEntry) bool {
+	if len(entries) == 0 {
+		return true
+	}
+
+	z := make([]byte, 16*len(entries))
+	_, _ = cryptoRand.Read(z)
+
+	var sumZS, zi, zk [paramB]byte
+	zero := make([]byte, 2*paramB)
+	nafs := make([][]int32, 2*len(entries))
+	points := make([]pointR1, 2*len(entries))
+	for i := range entries {
+		copy(zi[:16], z[16*i:])
+		calculateS(sumZS[:], sumZS[:], zi[:], entries[i].s[:])
+		calculateS(zk[:], zero, zi[:], entries[i].k[:])
+
+		nafs[2*i] = omegaNAF(&zi, omegaVar)
+		nafs[2*i+1] = omegaNAF(&zk, omegaVar)
+		points[2*i] = entries[i].negR
+		points[2*i+1] = entries[i].negA
+	}
+
+	var P pointR1
+	P.multiMult(sumZS[:], nafs, points)
+	P.double()
+	P.double()
+	P.double()
+
+	var O pointR1
+	O.SetIdentity()
+	return P.isEqual(&O)
+}
+
+// multiMult calculates P = mG + Σ [n_i]Q_i, where n_i is given in
+// omegaVar-NAF form, with Straus' method. It overwrites the points Q_i.
+func (P *pointR1) multiMult(m []byte, nafVar [][]int32, Q []pointR1) {
+	nafFix := math.OmegaNAF(conv.BytesLe2BigInt(m), omegaFix)
+	l := len(nafFix)
+	for _, naf := range nafVar {
+		l = max(l, len(naf))
+	}
+
+	TabQ := make([][1 << (omegaVar - 2)]pointR2, len(Q))
+	for i := range Q {
+		Q[i].oddMultiples(TabQ[i][:])
+	}
+
+	P.SetIdentity()
+	for i := l - 1; i >= 0; i-- {
+		P.double()
+		// Generator point
+		if i < len(nafFix) && nafFix[i] != 0 {
+			idxM := absolute(nafFix[i]) >> 1
+			R := tabVerif[idxM]
+			if nafFix[i] < 0 {
+				R.neg()
+			}
+			P.mixAdd(&R)
+		}
+		// Variable input points
+		for j, naf := range nafVar {
+			if i < len(naf) && naf[i] != 0 {
+				idxN := absolute(naf[i]) >> 1
+				S := TabQ[j][idxN]
+				if naf[i] < 0 {
+					S.neg()
+				}
+				P.add(&S)
+			}
+		}
+	}
+}
+
+// omegaNAF returns the w-NAF representation of a scalar, as math.OmegaNAF
+// does, without using big integers.
+func omegaNAF(k *[paramB]byte, w uint) []int32 {
+	var n [numWords64 + 1]uint64
+	for i := range numWords64 {
+		n[i] = binary.LittleEndian.Uint64(k[8*i:])
+	}
+
+	naf := make([]int32, 0, 8*paramB+1)
+	for n != [numWords64 + 1]uint64{} {
+		var d int32
+		if n[0]&1 == 1 {
+			d = int32(n[0] & (1<<w - 1))
+			if d >= 1<<(w-1) {
+				d -= 1 << w
+			}
+
+			var c uint64
+			if d > 0 {
+				n[0], c = bits.Sub64(n[0], uint64(d), 0)
+				for i := 1; i < len(n); i++ {
+					n[i], c = bits.Sub64(n[i], 0, c)
+				}
+			} else {
+				n[0], c = bits.Add64(n[0], uint64(-d), 0)
+				for i := 1; i < len(n); i++ {
+					n[i], c = bits.Add64(n[i], 0, c)
+				}
+			}
+		}
+		naf = append(naf, d)
+
+		for i := 0; i < len(n)-1; i++ {
+			n[i] = n[i]>>1 | n[i+1]<<63
+		}
+		n[len(n)-1] >>= 1
+	}
+	return naf
+}
@@ -0,0 +1,130 @@
+package ed25519
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/conv"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/math"
+)
+
+func newBatchInputs(t testing.TB, n int) (pubs []PublicKey, msgs, sigs [][]byte) {
+	for i := range n {
+		pub, priv, err := GenerateKey(rand.Reader)
+		test.CheckNoErr(t, err, "GenerateKey failed")
+		msg := []byte(fmt.Sprintf("message %v", i))
+		pubs = append(pubs, pub)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, Sign(priv, msg))
+	}
+	return
+}
+
+func TestVerifyBatch(t *testing.T) {
+	pubs, msgs, sigs := newBatchInputs(t, 2*batchSize+3)
+	test.CheckOk(VerifyBatch(nil, nil, nil), "empty batch must be valid", t)
+	test.CheckOk(VerifyBatch(pubs, msgs, sigs), "VerifyBatch failed", t)
+	test.CheckOk(!VerifyBatch(pubs, msgs[1:], sigs), "VerifyBatch should fail", t)
+	test.CheckOk(VerifyBatchEach(pubs[1:], msgs, sigs) == nil, "VerifyBatchEach should fail", t)
+
+	for _, v := range VerifyBatchEach(pubs, msgs, sigs) {
+		test.CheckOk(v, "VerifyBatchEach failed", t)
+	}
+
+	invalid := map[int]bool{0: true, 5: true, 6: true, batchSize: true, 2*batchSize + 2: true}
+	sigs[0][3] ^= 1                                // Wrong R.
+	sigs[5][SignatureSize-1] |= 0xf0               // S out of range.
+	msgs[6] = []byte("another message")            // Wrong message.
+	pubs[batchSize] = pubs[batchSize+1]            // Wrong public key.
+	sigs[2*batchSize+2] = sigs[2*batchSize+2][:10] // Wrong size.
+
+	test.CheckOk(!VerifyBatch(pubs, msgs, sigs), "VerifyBatch should fail", t)
+	valid := VerifyBatchEach(pubs, msgs, sigs)
+	for i, v := range valid {
+		if v == invalid[i] || v != Verify(pubs[i], msgs[i], sigs[i]) {
+			test.ReportError(t, v, !invalid[i], i)
+		}
+	}
+}
+
+// A signature made with a public key having a component of order 8 is
+// rejected by Verify, but accepted by the cofactored batch equation.
+func TestVerifyBatchTorsion(t *testing.T) {
+	var T, O pointR1
+	b, _ := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	test.CheckOk(T.FromBytes(b), "FromBytes failed", t)
+	O.SetIdentity()
+	Q := T
+	Q.double()
+	Q.double()
+	test.CheckOk(!Q.isEqual(&O), "point of order less than 8", t)
+	Q.double()
+	test.CheckOk(Q.isEqual(&O), "point of order not 8", t)
+
+	var a, r [2 * paramB]byte
+	_, _ = rand.Read(a[:])
+	_, _ = rand.Read(r[:])
+	reduceModOrder(a[:], true)
+	reduceModOrder(r[:], true)
+
+	var A, R pointR1
+	var T2 pointR2
+	A.fixedMult(a[:paramB])
+	T2.fromR1(&T)
+	A.add(&T2)
+	R.fixedMult(r[:paramB])
+
+	pub := make(PublicKey, PublicKeySize)
+	sig := make([]byte, SignatureSize)
+	_ = A.ToBytes(pub)
+	_ = R.ToBytes(sig[:paramB])
+
+	for i := 0; ; i++ {
+		msg := []byte(fmt.Sprintf("message %v", i))
+		h := sha512.New()
+		_, _ = h.Write(sig[:paramB])
+		_, _ = h.Write(pub)
+		_, _ = h.Write(msg)
+		k := h.Sum(nil)
+		reduceModOrder(k, true)
+		if k[0]%8 == 0 {
+			continue
+		}
+
+		calculateS(sig[paramB:], r[:paramB], k[:paramB], a[:paramB])
+		test.CheckOk(!Verify(pub, msg, sig), "Verify should fail", t)
+		test.CheckOk(VerifyBatch([]PublicKey{pub}, [][]byte{msg}, [][]byte{sig}), "VerifyBatch failed", t)
+		break
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	for _, n := range []int{1, 8, 64, 256} {
+		pubs, msgs, sigs := newBatchInputs(b, n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				VerifyBatch(pubs, msgs, sigs)
+			}
+		})
+	}
+}
+
+func TestOmegaNAF(t *testing.T) {
+	var k [paramB]byte
+	for range 100 {
+		_, _ = rand.Read(k[:])
+		got := omegaNAF(&k, omegaVar)
+		want := math.OmegaNAF(conv.BytesLe2BigInt(k[:]), omegaVar)
+		for len(want) > 0 && want[len(want)-1] == 0 {
+			want = want[:len(want)-1]
+		}
+		if !slices.Equal(got, want) {
+			test.ReportError(t, got, want, k)
+		}
+	}
+}
@@ -15,11 +15,19 @@
 // which is implemented by the PrivateKey type. A correspond all-in-one
 // verification method is provided by the VerifyAny function.
 //
+// By default, verification follows RFC-8032 with canonical encodings and
+// the cofactorless equation, as crypto/ed25519 does. The VerifyWithOptions
+// function, or the Policy field of SignerOptions, selects the ZIP-215 rules
+// instead.
+//
 // Signing with Ed25519Ph or Ed25519Ctx requires a context string for domain
 // separation. This parameter is passed using a SignerOptions struct defined
 // in this package. While Ed25519Ph accepts an empty context, Ed25519Ctx
 // enforces non-empty context strings.
 //
+// Many Ed25519 signatures can be verified at once, faster than one by one,
+// with the VerifyBatch and VerifyBatchEach functions.
+//
 // # Compatibility with crypto.ed25519
 //
 // These functions are compatible with the “Ed25519” function defined in
@@ -33,6 +41,7 @@
 //   - RFC-8032: https://rfc-editor.org/rfc/rfc8032.txt
 //   - Ed25519: https://ed25519.cr.yp.to/
 //   - EdDSA: High-speed high-security signatures. https://doi.org/10.1007/s13389-012-0027-1
+//   - ZIP-215: https://zips.z.cash/zip-0215
 package ed25519
 
 import (
@@ -80,6 +89,10 @@ type SignerOptions struct {
 	// Scheme is an identifier for choosing a signature scheme. The zero value
 	// is ED25519.
 	Scheme SchemeID
+
+	// Policy selects the rules applied by VerifyAny and VerifyWithOptions.
+	// It is ignored for signing. The zero value is RFC8032.
+	Policy VerifyPolicy
 }
 
 // SchemeID is an identifier for each signature scheme.
@@ -91,6 +104,26 @@ const (
 	ED25519Ctx
 )
 
+// VerifyPolicy is an identifier for the rules used to accept a signature.
+// Policies only differ on edge cases that honest signers never produce, so
+// they matter when several parties must agree on the validity of every
+// signature, as in consensus protocols.
+type VerifyPolicy uint
+
+const (
+	// RFC8032 requires canonical encodings of the public key and of R, and
+	// S < L, and checks the cofactorless equation [S]B = R + [k]A. This is
+	// the behavior of Verify. It differs from crypto/ed25519, which accepts
+	// non-canonical encodings of the public key.
+	RFC8032 VerifyPolicy = iota
+	// ZIP215 follows ZIP-215: non-canonical encodings of the public key and
+	// of R are accepted, S < L is required, and the cofactored equation
+	// [8][S]B = [8]R + [8][k]A is checked. VerifyBatch checks the same
+	// equation, but requires canonical encodings, so it rejects some
+	// signatures that ZIP215 accepts.
+	ZIP215
+)
+
 // PrivateKey is the type of Ed25519 private keys. It implements crypto.Signer.
 type PrivateKey []byte
 
@@ -216,20 +249,22 @@ func newKeyFromSeed(privateKey, seed []byte) {
 }
 
 func signAll(signature []byte, privateKey PrivateKey, message, ctx []byte, preHash bool) {
+	PHM := message
+	if preHash {
+		h := sha512.Sum512(message)
+		PHM = h[:]
+	}
+
+	signPHM(signature, privateKey, PHM, ctx, preHash)
+}
+
+// signPHM signs PHM, which is PH(M) for Ed25519ph and M otherwise.
+func signPHM(signature []byte, privateKey PrivateKey, PHM, ctx []byte, preHash bool) {
 	if l := len(privateKey); l != PrivateKeySize {
 		panic("ed25519: bad private key length: " + strconv.Itoa(l))
 	}
 
 	H := sha512.New()
-	var PHM []byte
-
-	if preHash {
-		_, _ = H.Write(message)
-		PHM = H.Sum(nil)
-		H.Reset()
-	} else {
-		PHM = message
-	}
 
 	// 1.  Hash the 32-byte private key using SHA-512.
 	_, _ = H.Write(privateKey[:SeedSize])
@@ -319,29 +354,42 @@ func SignWithCtx(privateKey PrivateKey, message []byte, ctx string) []byte {
 	return signature
 }
 
-func verify(public PublicKey, message, signature, ctx []byte, preHash bool) bool {
+func verify(
+	public PublicKey,
+	message, signature, ctx []byte,
+	preHash bool,
+	policy VerifyPolicy,
+) bool {
+	PHM := message
+	if preHash {
+		h := sha512.Sum512(message)
+		PHM = h[:]
+	}
+
+	return verifyPHM(public, PHM, signature, ctx, preHash, policy)
+}
+
+// verifyPHM verifies a signature on PHM, which is PH(M) for Ed25519ph and M
+// otherwise.
+func verifyPHM(
+	public PublicKey,
+	PHM, signature, ctx []byte,
+	preHash bool,
+	policy VerifyPolicy,
+) bool {
 	if len(public) != PublicKeySize ||
 		len(signature) != SignatureSize ||
-		!isLessThanOrder(signature[paramB:]) {
+		!isLessThanOrder(signature[paramB:]) ||
+		policy > ZIP215 {
 		return false
 	}
 
 	var P pointR1
-	if ok := P.FromBytes(public); !ok {
+	if ok := P.fromBytes(public, policy == RFC8032); !ok {
 		return false
 	}
 
 	H := sha512.New()
-	var PHM []byte
-
-	if preHash {
-		_, _ = H.Write(message)
-		PHM = H.Sum(nil)
-		H.Reset()
-	} else {
-		PHM = message
-	}
-
 	R := signature[:paramB]
 
 	writeDom(H, ctx, preHash)
@@ -353,11 +401,31 @@ func verify(public PublicKey, message, signature, ctx []byte, preHash bool) bool
 	reduceModOrder(hRAM[:], true)
 
 	var Q pointR1
-	encR := (&[paramB]byte{})[:]
 	P.neg()
 	Q.doubleMult(&P, signature[paramB:], hRAM[:paramB])
-	_ = Q.ToBytes(encR)
-	return bytes.Equal(R, encR)
+
+	if policy == RFC8032 {
+		encR := (&[paramB]byte{})[:]
+		_ = Q.ToBytes(encR)
+		return bytes.Equal(R, encR)
+	}
+
+	// ZIP-215: checks that [8]([S]B - [k]A - R) is the identity.
+	var negR pointR1
+	if ok := negR.fromBytes(R, false); !ok {
+		return false
+	}
+	negR.neg()
+	var R2 pointR2
+	R2.fromR1(&negR)
+	Q.add(&R2)
+	Q.double()
+	Q.double()
+	Q.double()
+
+	var O pointR1
+	O.SetIdentity()
+	return Q.isEqual(&O)
 }
 
 // VerifyAny returns true if the signature is valid. Failure cases are invalid
@@ -368,22 +436,30 @@ func verify(public PublicKey, message, signature, ctx []byte, preHash bool) bool
 // variant. This can be achieved by passing crypto.Hash(0) as the value for opts.
 // The opts.HashFunc() must return SHA512 to specify the Ed25519Ph variant.
 // This can be achieved by passing crypto.SHA512 as the value for opts.
-// Use a SignerOptions struct to pass a context string for signing.
+// Use a SignerOptions struct to pass a context string for signing, or to
+// select a verification policy.
 func VerifyAny(public PublicKey, message, signature []byte, opts crypto.SignerOpts) bool {
-	var ctx string
-	var scheme SchemeID
-	if o, ok := opts.(SignerOptions); ok {
-		ctx = o.Context
-		scheme = o.Scheme
+	o, ok := opts.(SignerOptions)
+	if !ok {
+		o = SignerOptions{Hash: opts.HashFunc()}
 	}
 
+	return VerifyWithOptions(public, message, signature, o)
+}
+
+// VerifyWithOptions returns true if the signature is valid under the rules
+// selected by opts.Policy. The signature variant is selected by opts as for
+// VerifyAny.
+func VerifyWithOptions(public PublicKey, message, signature []byte, opts SignerOptions) bool {
+	ctx := []byte(opts.Context)
 	switch true {
-	case scheme == ED25519 && opts.HashFunc() == crypto.Hash(0):
-		return Verify(public, message, signature)
-	case scheme == ED25519Ph && opts.HashFunc() == crypto.SHA512:
-		return VerifyPh(public, message, signature, ctx)
-	case scheme == ED25519Ctx && opts.HashFunc() == crypto.Hash(0) && len(ctx) > 0:
-		return VerifyWithCtx(public, message, signature, ctx)
+	case opts.Scheme == ED25519 && opts.Hash == crypto.Hash(0):
+		return verify(public, message, signature, nil, false, opts.Policy)
+	case opts.Scheme == ED25519Ph && opts.Hash == crypto.SHA512:
+		return verify(public, message, signature, ctx, true, opts.Policy)
+	case opts.Scheme == ED25519Ctx && opts.Hash == crypto.Hash(0) &&
+		len(ctx) > 0 && len(ctx) <= ContextMaxSize:
+		return verify(public, message, signature, ctx, false, opts.Policy)
 	default:
 		return false
 	}
@@ -394,7 +470,7 @@ func VerifyAny(public PublicKey, message, signature []byte, opts crypto.SignerOp
 // This function supports the signature variant defined in RFC-8032: Ed25519,
 // also known as the pure version of EdDSA.
 func Verify(public PublicKey, message, signature []byte) bool {
-	return verify(public, message, signature, []byte(""), false)
+	return verify(public, message, signature, []byte(""), false, RFC8032)
 }
 
 // VerifyPh returns true if the signature is valid. Failure cases are invalid
@@ -404,7 +480,7 @@ func Verify(public PublicKey, message, signature []byte) bool {
 // Context could be passed to this function, which length should be no more than
 // 255. It can be empty.
 func VerifyPh(public PublicKey, message, signature []byte, ctx string) bool {
-	return verify(public, message, signature, []byte(ctx), true)
+	return verify(public, message, signature, []byte(ctx), true, RFC8032)
 }
 
 // VerifyWithCtx returns true if the signature is valid. Failure cases are invalid
@@ -418,7 +494,7 @@ func VerifyWithCtx(public PublicKey, message, signature []byte, ctx string) bool
 		return false
 	}
 
-	return verify(public, message, signature, []byte(ctx), false)
+	return verify(public, message, signature, []byte(ctx), false, RFC8032)
 }
 
 func clamp(k []byte) {
@@ -51,7 +51,12 @@ func (P *pointR1) ToBytes(k []byte) error {
 	return nil
 }
 
-func (P *pointR1) FromBytes(k []byte) bool {
+func (P *pointR1) FromBytes(k []byte) bool { return P.fromBytes(k, true) }
+
+// fromBytes decodes a point. If canonical is false, it also accepts the
+// non-canonical encodings allowed by ZIP-215, that is, y >= p, and x = 0
+// with the sign bit set.
+func (P *pointR1) fromBytes(k []byte, canonical bool) bool {
 	if len(k) != paramB {
 		panic("wrong size")
 	}
@@ -60,7 +65,10 @@ func (P *pointR1) FromBytes(k []byte) bool {
 	P.y[fp.Size-1] &= 0x7F
 	p := fp.P()
 	if !isLessThan(P.y[:], p[:]) {
-		return false
+		if canonical {
+			return false
+		}
+		fp.Modp(&P.y)
 	}
 
 	one, u, v := &fp.Elt{}, &fp.Elt{}, &fp.Elt{}
@@ -74,7 +82,7 @@ func (P *pointR1) FromBytes(k []byte) bool {
 		return false
 	}
 	fp.Modp(&P.x) // x = x mod p
-	if fp.IsZero(&P.x) && signX == 1 {
+	if fp.IsZero(&P.x) && signX == 1 && canonical {
 		return false
 	}
 	if signX != (P.x[0] & 1) {
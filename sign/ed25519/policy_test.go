@@ -0,0 +1,220 @@
+package ed25519
+
+import (
+	"crypto"
+	cryptoEd25519 "crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+)
+
+// smallOrder lists the encodings of points of small order: the eight
+// canonical ones, followed by the six non-canonical ones.
+var smallOrder = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"0000000000000000000000000000000000000000000000000000000000000080",
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a",
+	"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05",
+	"26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85",
+	// Non-canonical encodings.
+	"0100000000000000000000000000000000000000000000000000000000000080",
+	"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+}
+
+const numCanonical = 8
+
+// TestZIP215 checks the test vectors of ZIP-215: every pair of small-order
+// encodings (A, R) with S = 0 is a valid signature of "Zcash". Batch
+// verification only accepts the pairs of canonical encodings.
+func TestZIP215(t *testing.T) {
+	msg := []byte("Zcash")
+	zip215 := SignerOptions{Policy: ZIP215}
+	rfc8032 := SignerOptions{Policy: RFC8032}
+	for i, a := range smallOrder {
+		pub, _ := hex.DecodeString(a)
+
+		var P pointR1
+		test.CheckOk(P.FromBytes(pub) == (i < numCanonical), "FromBytes failed", t)
+		test.CheckOk(P.fromBytes(pub, false), "fromBytes failed", t)
+		P.double()
+		P.double()
+		P.double()
+		var O pointR1
+		O.SetIdentity()
+		test.CheckOk(P.isEqual(&O), "point is not of small order", t)
+
+		for j, r := range smallOrder {
+			sig, _ := hex.DecodeString(r + "0000000000000000000000000000000000000000000000000000000000000000")
+			if !VerifyWithOptions(pub, msg, sig, zip215) {
+				test.ReportError(t, false, true, i, j)
+			}
+			if (i >= numCanonical || j >= numCanonical) &&
+				VerifyWithOptions(pub, msg, sig, rfc8032) {
+				test.ReportError(t, true, false, i, j)
+			}
+			want := i < numCanonical && j < numCanonical
+			got := VerifyBatch([]PublicKey{pub}, [][]byte{msg}, [][]byte{sig})
+			if got != want {
+				test.ReportError(t, got, want, i, j)
+			}
+		}
+	}
+}
+
+// TestCryptoEd25519 checks that RFC8032 agrees with crypto/ed25519 on the
+// ZIP-215 test vectors, except for the non-canonical public keys, which
+// crypto/ed25519 accepts.
+func TestCryptoEd25519(t *testing.T) {
+	msg := []byte("Zcash")
+	rfc8032 := SignerOptions{Policy: RFC8032}
+	accepted := 0
+	for i, a := range smallOrder {
+		pub, _ := hex.DecodeString(a)
+		for j, r := range smallOrder {
+			sig, _ := hex.DecodeString(r + "0000000000000000000000000000000000000000000000000000000000000000")
+			got := VerifyWithOptions(pub, msg, sig, rfc8032)
+			want := cryptoEd25519.Verify(cryptoEd25519.PublicKey(pub), msg, sig)
+			if i >= numCanonical {
+				if want {
+					accepted++
+				}
+				want = false
+			}
+			if got != want {
+				test.ReportError(t, got, want, i, j)
+			}
+		}
+	}
+	test.CheckOk(accepted > 0, "crypto/ed25519 rejects non-canonical public keys", t)
+}
+
+// TestVerifyPolicy checks the edge cases where the policies differ.
+func TestVerifyPolicy(t *testing.T) {
+	var T pointR1
+	b, _ := hex.DecodeString(smallOrder[4])
+	test.CheckOk(T.FromBytes(b), "FromBytes failed", t)
+	var T2 pointR2
+	T2.fromR1(&T)
+
+	// sign creates a signature of msg, adding a point of order 8 to the
+	// public key and to R if requested.
+	sign := func(msg []byte, torsionA, torsionR bool) (PublicKey, []byte) {
+		for {
+			var a, r [2 * paramB]byte
+			_, _ = rand.Read(a[:])
+			_, _ = rand.Read(r[:])
+			reduceModOrder(a[:], true)
+			reduceModOrder(r[:], true)
+
+			var A, R pointR1
+			A.fixedMult(a[:paramB])
+			R.fixedMult(r[:paramB])
+			if torsionA {
+				A.add(&T2)
+			}
+			if torsionR {
+				R.add(&T2)
+			}
+
+			pub := make(PublicKey, PublicKeySize)
+			sig := make([]byte, SignatureSize)
+			_ = A.ToBytes(pub)
+			_ = R.ToBytes(sig[:paramB])
+
+			h := sha512.New()
+			_, _ = h.Write(sig[:paramB])
+			_, _ = h.Write(pub)
+			_, _ = h.Write(msg)
+			k := h.Sum(nil)
+			reduceModOrder(k, true)
+			if torsionA && !torsionR && k[0]%8 == 0 {
+				continue
+			}
+			calculateS(sig[paramB:], r[:paramB], k[:paramB], a[:paramB])
+			return pub, sig
+		}
+	}
+
+	// addOrder sets S to S+L, which is less than 2^253.
+	addOrder := func(sig []byte) {
+		var c uint16
+		for i := range order {
+			c += uint16(sig[paramB+i]) + uint16(order[i])
+			sig[paramB+i] = byte(c)
+			c >>= 8
+		}
+	}
+
+	msg := []byte("message")
+	for _, v := range []struct {
+		name            string
+		torsionA        bool
+		torsionR        bool
+		highS           bool
+		rfc8032, zip215 bool
+	}{
+		{"honest", false, false, false, true, true},
+		{"mixed-order A", true, false, false, false, true},
+		{"mixed-order R", false, true, false, false, true},
+		{"S >= L", false, false, true, false, false},
+	} {
+		t.Run(v.name, func(t *testing.T) {
+			pub, sig := sign(msg, v.torsionA, v.torsionR)
+			if v.highS {
+				addOrder(sig)
+			}
+			for _, opts := range []SignerOptions{
+				{Policy: RFC8032},
+				{Policy: ZIP215},
+			} {
+				want := v.rfc8032
+				if opts.Policy == ZIP215 {
+					want = v.zip215
+				}
+				got := VerifyWithOptions(pub, msg, sig, opts)
+				if got != want {
+					test.ReportError(t, got, want, opts.Policy)
+				}
+				got = VerifyAny(pub, msg, sig, opts)
+				if got != want {
+					test.ReportError(t, got, want, opts.Policy)
+				}
+			}
+			want := v.rfc8032
+			got := Verify(pub, msg, sig)
+			if got != want {
+				test.ReportError(t, got, want)
+			}
+		})
+	}
+
+	t.Run("variants", func(t *testing.T) {
+		_, priv, _ := GenerateKey(nil)
+		pub := priv.Public().(PublicKey)
+		for _, opts := range []SignerOptions{
+			{Hash: crypto.Hash(0), Scheme: ED25519},
+			{Hash: crypto.SHA512, Scheme: ED25519Ph, Context: "ph"},
+			{Hash: crypto.Hash(0), Scheme: ED25519Ctx, Context: "ctx"},
+		} {
+			sig, err := priv.Sign(nil, msg, opts)
+			test.CheckNoErr(t, err, "Sign failed")
+			for _, policy := range []VerifyPolicy{RFC8032, ZIP215} {
+				opts.Policy = policy
+				test.CheckOk(VerifyWithOptions(pub, msg, sig, opts), fmt.Sprintf("verify failed: %+v", opts), t)
+			}
+			opts.Policy = ZIP215 + 1
+			test.CheckOk(!VerifyWithOptions(pub, msg, sig, opts), "unknown policy accepted", t)
+		}
+	})
+}
@@ -1,15 +1,23 @@
 package ed25519
 
 import (
+	"crypto"
 	"crypto/rand"
+	"crypto/sha512"
 	"encoding/asn1"
 
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/internal/stream"
 )
 
-var sch sign.Scheme = &scheme{}
+var sch sign.StreamingScheme = &scheme{}
 
 // Scheme returns a signature interface.
+//
+// Its methods produce and check Ed25519ph signatures, with an empty
+// context, when the options select SHA-512 as pre-hash function. Other
+// pre-hash functions, and contexts, are not supported. The scheme also
+// implements sign.StreamingScheme.
 func Scheme() sign.Scheme { return sch }
 
 type scheme struct{}
@@ -29,6 +37,8 @@ func (*scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
 	return GenerateKey(rand.Reader)
 }
 
+// Sign returns an Ed25519 signature of the message, or an Ed25519ph
+// signature if opts selects SHA-512 as pre-hash function.
 func (*scheme) Sign(
 	sk sign.PrivateKey,
 	message []byte,
@@ -38,12 +48,14 @@ func (*scheme) Sign(
 	if !ok {
 		panic(sign.ErrTypeMismatch)
 	}
-	if opts != nil && opts.Context != "" {
-		panic(sign.ErrContextNotSupported)
+	if checkOpts(opts) {
+		return SignPh(priv, message, "")
 	}
 	return Sign(priv, message)
 }
 
+// Verify checks an Ed25519 signature of the message, or an Ed25519ph
+// signature if opts selects SHA-512 as pre-hash function.
 func (*scheme) Verify(
 	pk sign.PublicKey,
 	message, signature []byte,
@@ -53,14 +65,80 @@ func (*scheme) Verify(
 	if !ok {
 		panic(sign.ErrTypeMismatch)
 	}
-	if opts != nil {
-		if opts.Context != "" {
-			panic(sign.ErrContextNotSupported)
-		}
+	if checkOpts(opts) {
+		return VerifyPh(pub, message, signature, "")
 	}
 	return Verify(pub, message, signature)
 }
 
+// NewStreamSigner returns a signer for Ed25519 or, if opts selects SHA-512
+// as pre-hash function, for Ed25519ph. The message is buffered in memory
+// for Ed25519.
+func (*scheme) NewStreamSigner(
+	sk sign.PrivateKey,
+	opts *sign.SignatureOpts,
+) sign.StreamSigner {
+	priv, ok := sk.(PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if !checkOpts(opts) {
+		return stream.NewBufferedSigner(func(msg []byte) []byte {
+			return Sign(priv, msg)
+		})
+	}
+
+	h := sha512.New()
+	return &stream.Signer{Writer: h, SignFunc: func() []byte {
+		signature := make([]byte, SignatureSize)
+		signPHM(signature, priv, h.Sum(nil), nil, true)
+		return signature
+	}}
+}
+
+// NewStreamVerifier returns a verifier for Ed25519 or, if opts selects
+// SHA-512 as pre-hash function, for Ed25519ph. The message is buffered in
+// memory for Ed25519.
+func (*scheme) NewStreamVerifier(
+	pk sign.PublicKey,
+	opts *sign.SignatureOpts,
+) sign.StreamVerifier {
+	pub, ok := pk.(PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	if !checkOpts(opts) {
+		return stream.NewBufferedVerifier(func(msg, signature []byte) bool {
+			return Verify(pub, msg, signature)
+		})
+	}
+
+	h := sha512.New()
+	return &stream.Verifier{Writer: h, VerifyFunc: func(signature []byte) bool {
+		return verifyPHM(pub, h.Sum(nil), signature, nil, true, RFC8032)
+	}}
+}
+
+// checkOpts returns whether opts selects Ed25519ph.
+//
+// Panics if a context or a pre-hash function other than SHA-512 is set.
+func checkOpts(opts *sign.SignatureOpts) bool {
+	if opts == nil {
+		return false
+	}
+	if opts.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+	switch opts.PreHash {
+	case sign.PreHash{}:
+		return false
+	case sign.PreHash{Hash: crypto.SHA512}:
+		return true
+	default:
+		panic(sign.ErrPreHashNotSupported)
+	}
+}
+
 func (*scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
 	privateKey := NewKeyFromSeed(seed)
 	publicKey := make(PublicKey, PublicKeySize)
@@ -0,0 +1,174 @@
+package ed448
+
+import (
+	cryptoRand "crypto/rand"
+
+	"github.com/cloudflare/circl/ecc/goldilocks"
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// batchSize is the maximum number of signatures checked by a single
+// multi-scalar multiplication.
+const batchSize = 128
+
+// VerifyBatch returns true if the signatures[i] of messages[i] under
+// publicKeys[i] are all valid for the Ed448 variant with the given context.
+// It returns false if the slices have different lengths, and true for
+// empty slices.
+//
+// The signatures are checked together with a randomized multi-scalar
+// multiplication, which is faster than calling Verify for each of them.
+// The batch equation is cofactored, so it may accept signatures that only
+// differ from a valid one by a point of small order. Such signatures can
+// only be crafted by the signer.
+func VerifyBatch(publicKeys []PublicKey, messages, signatures [][]byte, ctx string) bool {
+	entries, ok := newBatch(publicKeys, messages, signatures, []byte(ctx))
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < len(entries); i += batchSize {
+		for _, e := range entries[i:min(i+batchSize, len(entries))] {
+			if !e.ok {
+				return false
+			}
+		}
+		if !verifyBatch(entries[i:min(i+batchSize, len(entries))]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VerifyBatchEach returns whether the signatures[i] of messages[i] under
+// publicKeys[i] are valid for the Ed448 variant with the given context, as
+// VerifyBatch does, and reports which ones are invalid.
+// When a batch fails, it is split in halves, which are checked
+// recursively, so the cost grows with the number of invalid signatures.
+// It returns nil if the slices have different lengths.
+func VerifyBatchEach(publicKeys []PublicKey, messages, signatures [][]byte, ctx string) []bool {
+	entries, ok := newBatch(publicKeys, messages, signatures, []byte(ctx))
+	if !ok {
+		return nil
+	}
+
+	valid := make([]bool, len(entries))
+	for i := 0; i < len(entries); i += batchSize {
+		j := min(i+batchSize, len(entries))
+		bisectBatch(entries[i:j], valid[i:j])
+	}
+
+	return valid
+}
+
+// bisectBatch sets valid[i] to whether entries[i] is valid.
+func bisectBatch(entries []batchEntry, valid []bool) {
+	// Entries that cannot be parsed are invalid.
+	for i := range entries {
+		if !entries[i].ok {
+			if len(entries) > 1 {
+				bisectBatch(entries[:i], valid[:i])
+				bisectBatch(entries[i+1:], valid[i+1:])
+			}
+			return
+		}
+	}
+
+	if verifyBatch(entries) {
+		for i := range valid {
+			valid[i] = true
+		}
+		return
+	}
+
+	if n := len(entries); n > 1 {
+		bisectBatch(entries[:n/2], valid[:n/2])
+		bisectBatch(entries[n/2:], valid[n/2:])
+	}
+}
+
+// batchEntry stores a parsed signature.
+type batchEntry struct {
+	negA, negR goldilocks.Point  // -A and -R
+	s, k       goldilocks.Scalar // S and k = H(dom4 || R || A || M) mod order
+	ok         bool              // Whether the signature was parsed.
+}
+
+func newBatch(
+	publicKeys []PublicKey, messages, signatures [][]byte, ctx []byte,
+) ([]batchEntry, bool) {
+	if len(publicKeys) != len(messages) || len(publicKeys) != len(signatures) {
+		return nil, false
+	}
+
+	entries := make([]batchEntry, len(publicKeys))
+	if len(ctx) > ContextMaxSize {
+		return entries, true
+	}
+
+	for i := range entries {
+		entries[i].ok = entries[i].parse(publicKeys[i], messages[i], signatures[i], ctx)
+	}
+	return entries, true
+}
+
+func (e *batchEntry) parse(public PublicKey, message, signature, ctx []byte) bool {
+	if len(public) != PublicKeySize ||
+		len(signature) != SignatureSize ||
+		!isLessThanOrder(signature[paramB:]) {
+		return false
+	}
+
+	R := signature[:paramB]
+	A, errA := goldilocks.FromBytes(public)
+	negR, errR := goldilocks.FromBytes(R)
+	if errA != nil || errR != nil {
+		return false
+	}
+	e.negA, e.negR = *A, *negR
+	e.negA.Neg()
+	e.negR.Neg()
+
+	H := sha3.NewShake256()
+	var hRAM [hashSize]byte
+	writeDom(&H, ctx, false)
+	_, _ = H.Write(R)
+	_, _ = H.Write(public)
+	_, _ = H.Write(message)
+	_, _ = H.Read(hRAM[:])
+	e.k.FromBytes(hRAM[:])
+	e.s.FromBytes(signature[paramB:])
+	return true
+}
+
+// verifyBatch returns true if
+//
+//	[Σ z_i*s_i]B - Σ [z_i]R_i - Σ [z_i*k_i]A_i = 0
+//
+// for random 128-bit scalars z_i. The equation is cofactored, as the
+// multi-scalar multiplication maps points through a 4-isogeny and back.
+func verifyBatch(entries []batchEntry) bool {
+	if len(entries) == 0 {
+		return true
+	}
+
+	z := make([]byte, 16*len(entries))
+	_, _ = cryptoRand.Read(z)
+
+	var sumZS, zs goldilocks.Scalar
+	scalars := make([]goldilocks.Scalar, 2*len(entries))
+	points := make([]goldilocks.Point, 2*len(entries))
+	for i := range entries {
+		zi := &scalars[2*i]
+		zi.FromBytes(z[16*i : 16*(i+1)])
+		zs.Mul(zi, &entries[i].s)
+		sumZS.Add(&sumZS, &zs)
+		scalars[2*i+1].Mul(zi, &entries[i].k)
+
+		points[2*i] = entries[i].negR
+		points[2*i+1] = entries[i].negA
+	}
+
+	return goldilocks.Curve{}.MultiScalarMult(&sumZS, scalars, points).IsIdentity()
+}
@@ -0,0 +1,62 @@
+package ed448_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+func newBatchInputs(t testing.TB, n int, ctx string) (pubs []ed448.PublicKey, msgs, sigs [][]byte) {
+	for i := range n {
+		pub, priv, err := ed448.GenerateKey(rand.Reader)
+		test.CheckNoErr(t, err, "GenerateKey failed")
+		msg := []byte(fmt.Sprintf("message %v", i))
+		pubs = append(pubs, pub)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, ed448.Sign(priv, msg, ctx))
+	}
+	return
+}
+
+func TestVerifyBatch(t *testing.T) {
+	const ctx = "batch"
+	pubs, msgs, sigs := newBatchInputs(t, 140, ctx)
+	test.CheckOk(ed448.VerifyBatch(nil, nil, nil, ""), "empty batch must be valid", t)
+	test.CheckOk(ed448.VerifyBatch(pubs, msgs, sigs, ctx), "VerifyBatch failed", t)
+	test.CheckOk(!ed448.VerifyBatch(pubs, msgs, sigs, ""), "VerifyBatch should fail", t)
+	test.CheckOk(!ed448.VerifyBatch(pubs, msgs[1:], sigs, ctx), "VerifyBatch should fail", t)
+	test.CheckOk(ed448.VerifyBatchEach(pubs[1:], msgs, sigs, ctx) == nil, "VerifyBatchEach should fail", t)
+
+	for _, v := range ed448.VerifyBatchEach(pubs, msgs, sigs, ctx) {
+		test.CheckOk(v, "VerifyBatchEach failed", t)
+	}
+
+	invalid := map[int]bool{0: true, 5: true, 6: true, 128: true, 139: true}
+	sigs[0][3] ^= 1                     // Wrong R.
+	sigs[5][ed448.SignatureSize-1] = 1  // S out of range.
+	msgs[6] = []byte("another message") // Wrong message.
+	pubs[128] = pubs[129]               // Wrong public key.
+	sigs[139] = sigs[139][:10]          // Wrong size.
+
+	test.CheckOk(!ed448.VerifyBatch(pubs, msgs, sigs, ctx), "VerifyBatch should fail", t)
+	valid := ed448.VerifyBatchEach(pubs, msgs, sigs, ctx)
+	for i, v := range valid {
+		if v == invalid[i] || v != ed448.Verify(pubs[i], msgs[i], sigs[i], ctx) {
+			test.ReportError(t, v, !invalid[i], i)
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	for _, n := range []int{1, 8, 64} {
+		pubs, msgs, sigs := newBatchInputs(b, n, "")
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ed448.VerifyBatch(pubs, msgs, sigs, "")
+			}
+		})
+	}
+}
@@ -16,6 +16,9 @@
 // Both schemes require a context string for domain separation. This parameter
 // is passed using a SignerOptions struct defined in this package.
 //
+// Many Ed448 signatures can be verified at once, faster than one by one,
+// with the VerifyBatch and VerifyBatchEach functions.
+//
 // References:
 //
 //   - RFC8032: https://rfc-editor.org/rfc/rfc8032.txt
@@ -205,22 +208,23 @@ func newKeyFromSeed(privateKey, seed []byte) {
 }
 
 func signAll(signature []byte, privateKey PrivateKey, message, ctx []byte, preHash bool) {
+	PHM := message
+	if preHash {
+		var h [64]byte
+		sha3.ShakeSum256(h[:], message)
+		PHM = h[:]
+	}
+
+	signPHM(signature, privateKey, PHM, ctx, preHash)
+}
+
+// signPHM signs PHM, which is PH(M) for Ed448ph and M otherwise.
+func signPHM(signature []byte, privateKey PrivateKey, PHM, ctx []byte, preHash bool) {
 	if len(ctx) > ContextMaxSize {
 		panic(fmt.Errorf("ed448: bad context length: %v", len(ctx)))
 	}
 
 	H := sha3.NewShake256()
-	var PHM []byte
-
-	if preHash {
-		var h [64]byte
-		_, _ = H.Write(message)
-		_, _ = H.Read(h[:])
-		PHM = h[:]
-		H.Reset()
-	} else {
-		PHM = message
-	}
 
 	// 1.  Hash the 57-byte private key using SHAKE256(x, 114).
 	var h [hashSize]byte
@@ -292,6 +296,19 @@ func SignPh(priv PrivateKey, message []byte, ctx string) []byte {
 }
 
 func verify(public PublicKey, message, signature, ctx []byte, preHash bool) bool {
+	PHM := message
+	if preHash {
+		var h [64]byte
+		sha3.ShakeSum256(h[:], message)
+		PHM = h[:]
+	}
+
+	return verifyPHM(public, PHM, signature, ctx, preHash)
+}
+
+// verifyPHM verifies a signature on PHM, which is PH(M) for Ed448ph and M
+// otherwise.
+func verifyPHM(public PublicKey, PHM, signature, ctx []byte, preHash bool) bool {
 	if len(public) != PublicKeySize ||
 		len(signature) != SignatureSize ||
 		len(ctx) > ContextMaxSize ||
@@ -305,18 +322,6 @@ func verify(public PublicKey, message, signature, ctx []byte, preHash bool) bool
 	}
 
 	H := sha3.NewShake256()
-	var PHM []byte
-
-	if preHash {
-		var h [64]byte
-		_, _ = H.Write(message)
-		_, _ = H.Read(h[:])
-		PHM = h[:]
-		H.Reset()
-	} else {
-		PHM = message
-	}
-
 	var hRAM [hashSize]byte
 	R := signature[:paramB]
 
@@ -4,12 +4,17 @@ import (
 	"crypto/rand"
 	"encoding/asn1"
 
+	"github.com/cloudflare/circl/internal/sha3"
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/internal/stream"
+	"github.com/cloudflare/circl/xof"
 )
 
-var sch sign.Scheme = &scheme{}
+var sch sign.StreamingScheme = &scheme{}
 
 // Scheme returns a signature interface.
+//
+// The scheme also implements sign.StreamingScheme.
 func Scheme() sign.Scheme { return sch }
 
 type scheme struct{}
@@ -29,6 +34,8 @@ func (*scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
 	return GenerateKey(rand.Reader)
 }
 
+// Sign returns an Ed448 signature of the message, or an Ed448ph signature
+// if opts selects SHAKE256 as pre-hash function.
 func (*scheme) Sign(
 	sk sign.PrivateKey,
 	message []byte,
@@ -38,13 +45,15 @@ func (*scheme) Sign(
 	if !ok {
 		panic(sign.ErrTypeMismatch)
 	}
-	ctx := ""
-	if opts != nil {
-		ctx = opts.Context
+	ctx, preHash := checkOpts(opts)
+	if preHash {
+		return SignPh(priv, message, ctx)
 	}
 	return Sign(priv, message, ctx)
 }
 
+// Verify checks an Ed448 signature of the message, or an Ed448ph signature
+// if opts selects SHAKE256 as pre-hash function.
 func (*scheme) Verify(
 	pk sign.PublicKey,
 	message, signature []byte,
@@ -54,13 +63,84 @@ func (*scheme) Verify(
 	if !ok {
 		panic(sign.ErrTypeMismatch)
 	}
-	ctx := ""
-	if opts != nil {
-		ctx = opts.Context
+	ctx, preHash := checkOpts(opts)
+	if preHash {
+		return VerifyPh(pub, message, signature, ctx)
 	}
 	return Verify(pub, message, signature, ctx)
 }
 
+// NewStreamSigner returns a signer for Ed448 or, if opts selects SHAKE256
+// as pre-hash function, for Ed448ph. The message is buffered in memory for
+// Ed448.
+func (*scheme) NewStreamSigner(
+	sk sign.PrivateKey,
+	opts *sign.SignatureOpts,
+) sign.StreamSigner {
+	priv, ok := sk.(PrivateKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	ctx, preHash := checkOpts(opts)
+	if !preHash {
+		return stream.NewBufferedSigner(func(msg []byte) []byte {
+			return Sign(priv, msg, ctx)
+		})
+	}
+
+	h := sha3.NewShake256()
+	return &stream.Signer{Writer: &h, SignFunc: func() []byte {
+		var PHM [64]byte
+		_, _ = h.Read(PHM[:])
+		signature := make([]byte, SignatureSize)
+		signPHM(signature, priv, PHM[:], []byte(ctx), true)
+		return signature
+	}}
+}
+
+// NewStreamVerifier returns a verifier for Ed448 or, if opts selects
+// SHAKE256 as pre-hash function, for Ed448ph. The message is buffered in
+// memory for Ed448.
+func (*scheme) NewStreamVerifier(
+	pk sign.PublicKey,
+	opts *sign.SignatureOpts,
+) sign.StreamVerifier {
+	pub, ok := pk.(PublicKey)
+	if !ok {
+		panic(sign.ErrTypeMismatch)
+	}
+	ctx, preHash := checkOpts(opts)
+	if !preHash {
+		return stream.NewBufferedVerifier(func(msg, signature []byte) bool {
+			return Verify(pub, msg, signature, ctx)
+		})
+	}
+
+	h := sha3.NewShake256()
+	return &stream.Verifier{Writer: &h, VerifyFunc: func(signature []byte) bool {
+		var PHM [64]byte
+		_, _ = h.Read(PHM[:])
+		return verifyPHM(pub, PHM[:], signature, []byte(ctx), true)
+	}}
+}
+
+// checkOpts returns the context in opts and whether opts selects Ed448ph.
+//
+// Panics if a pre-hash function other than SHAKE256 is set.
+func checkOpts(opts *sign.SignatureOpts) (ctx string, preHash bool) {
+	if opts == nil {
+		return "", false
+	}
+	switch opts.PreHash {
+	case sign.PreHash{}:
+		return opts.Context, false
+	case sign.PreHash{Xof: xof.SHAKE256}:
+		return opts.Context, true
+	default:
+		panic(sign.ErrPreHashNotSupported)
+	}
+}
+
 func (*scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
 	privateKey := NewKeyFromSeed(seed)
 	publicKey := make(PublicKey, PublicKeySize)
@@ -41,6 +41,9 @@ func (*scheme) Sign(
 	if opts != nil && opts.Context != "" {
 		panic(sign.ErrContextNotSupported)
 	}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	var sig [SignatureSize]byte
 	SignTo(priv, message, sig[:])
 	return sig[:]
@@ -58,6 +61,9 @@ func (*scheme) Verify(
 	if opts != nil && opts.Context != "" {
 		panic(sign.ErrContextNotSupported)
 	}
+	if opts != nil && !opts.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
 	return Verify(pub, message, signature)
 }
 
@@ -0,0 +1,188 @@
+package fndsa
+
+// Encodings of keys and signatures, see the Falcon specification,
+// Section 3.11.
+
+// Headers of the encodings, the low nibble being logn.
+const (
+	headerPublicKey  = 0x00
+	headerPrivateKey = 0x50
+	headerSignature  = 0x30
+)
+
+// encodeModq packs the coefficients of h with 14 bits each.
+func encodeModq(out []byte, h []uint16) {
+	var acc uint32
+	accLen := 0
+	for _, x := range h {
+		acc = acc<<14 | uint32(x)
+		accLen += 14
+		for accLen >= 8 {
+			accLen -= 8
+			out[0] = byte(acc >> accLen)
+			out = out[1:]
+		}
+	}
+}
+
+// decodeModq unpacks coefficients of 14 bits each into h. It returns
+// false if a coefficient is not reduced.
+func decodeModq(h []uint16, in []byte) bool {
+	var acc uint32
+	accLen := 0
+	ok := true
+	for i := range h {
+		for accLen < 14 {
+			acc = acc<<8 | uint32(in[0])
+			in = in[1:]
+			accLen += 8
+		}
+		accLen -= 14
+		h[i] = uint16(acc>>accLen) & 0x3FFF
+		ok = ok && h[i] < Q
+	}
+	return ok
+}
+
+// encodeSmall packs the coefficients of f with the given number of bits
+// each, in two's complement.
+func encodeSmall(out []byte, f []int16, bits uint) {
+	var acc uint32
+	var accLen uint
+	mask := uint32(1)<<bits - 1
+	for _, x := range f {
+		acc = acc<<bits | uint32(x)&mask
+		accLen += bits
+		for accLen >= 8 {
+			accLen -= 8
+			out[0] = byte(acc >> accLen)
+			out = out[1:]
+		}
+	}
+}
+
+// decodeSmall unpacks coefficients of the given number of bits each into f.
+// It returns false if a coefficient is -2^(bits-1), which is not allowed.
+func decodeSmall(f []int16, in []byte, bits uint) bool {
+	var acc uint32
+	var accLen uint
+	mask := uint32(1)<<bits - 1
+	minValue := uint32(1) << (bits - 1)
+	ok := true
+	for i := range f {
+		for accLen < bits {
+			acc = acc<<8 | uint32(in[0])
+			in = in[1:]
+			accLen += 8
+		}
+		accLen -= bits
+		w := (acc >> accLen) & mask
+		ok = ok && w != minValue
+		// Sign extension.
+		f[i] = int16(int32(w<<(32-bits)) >> (32 - bits))
+	}
+	return ok
+}
+
+// compress encodes s2 into out with the compressed format, see the Falcon
+// specification, Algorithm 17. It returns false if s2 does not fit in out.
+func compress(out []byte, s2 []int16) bool {
+	var acc uint32
+	var accLen uint
+	for _, x := range s2 {
+		if x < -2047 || x > 2047 {
+			return false
+		}
+
+		// Sign bit, 7 low bits of the absolute value, then the high bits in
+		// unary: as many zeros, followed by a one.
+		t := uint32(x)
+		s := t >> 31
+		t = (t ^ -s) + s
+		acc = acc<<8 | s<<7 | t&0x7F
+		accLen += 8
+		w := uint(t>>7) + 1
+		acc = acc<<w | 1
+		accLen += w
+
+		for accLen >= 8 {
+			if len(out) == 0 {
+				return false
+			}
+			accLen -= 8
+			out[0] = byte(acc >> accLen)
+			out = out[1:]
+		}
+	}
+
+	if accLen > 0 {
+		if len(out) == 0 {
+			return false
+		}
+		out[0] = byte(acc << (8 - accLen))
+		out = out[1:]
+	}
+
+	clear(out)
+	return true
+}
+
+// decompress decodes s2 from in, see the Falcon specification,
+// Algorithm 18. It returns false if the encoding is not canonical,
+// including if the padding is not made of zeros.
+func decompress(s2 []int16, in []byte) bool {
+	var acc uint32
+	var accLen uint
+	for i := range s2 {
+		// Sign bit and 7 low bits of the absolute value.
+		if len(in) == 0 {
+			return false
+		}
+		acc = acc<<8 | uint32(in[0])
+		in = in[1:]
+		b := acc >> accLen
+		s := b & 0x80
+		m := b & 0x7F
+
+		// High bits in unary.
+		for {
+			if accLen == 0 {
+				if len(in) == 0 {
+					return false
+				}
+				acc = acc<<8 | uint32(in[0])
+				in = in[1:]
+				accLen = 8
+			}
+			accLen--
+			if (acc>>accLen)&1 != 0 {
+				break
+			}
+			m += 128
+			if m > 2047 {
+				return false
+			}
+		}
+
+		// Reject negative zero.
+		if s != 0 && m == 0 {
+			return false
+		}
+
+		s2[i] = int16(m)
+		if s != 0 {
+			s2[i] = -s2[i]
+		}
+	}
+
+	// Unused bits and the padding must be zero.
+	if acc&(1<<accLen-1) != 0 {
+		return false
+	}
+	for _, b := range in {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package fndsa
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFFTRoots(t *testing.T) {
+	const prec = 256
+	pi, _ := new(big.Float).SetPrec(prec).SetString(
+		"3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803",
+	)
+
+	// cosSin evaluates the Taylor series of cos(a) and sin(a).
+	cosSin := func(a *big.Float) (c, s float64) {
+		sum := [2]*big.Float{new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)}
+		term := new(big.Float).SetPrec(prec).SetInt64(1)
+		for i := range 100 {
+			if i > 0 {
+				term.Mul(term, a)
+				term.Quo(term, new(big.Float).SetInt64(int64(i)))
+			}
+			if i%4 < 2 {
+				sum[i%2].Add(sum[i%2], term)
+			} else {
+				sum[i%2].Sub(sum[i%2], term)
+			}
+		}
+		c, _ = sum[0].Float64()
+		s, _ = sum[1].Float64()
+		return
+	}
+
+	// Node k has value exp(iπr[k]), where r[1] = 1/2, r[2k] = r[k]/2 and
+	// r[2k+1] = r[k]/2 + 1.
+	r := make([]*big.Rat, len(fftRoots))
+	r[1] = big.NewRat(1, 2)
+	for k := 2; k < len(r); k++ {
+		r[k] = new(big.Rat).Mul(r[k/2], big.NewRat(1, 2))
+		if k%2 == 1 {
+			r[k].Add(r[k], big.NewRat(1, 1))
+		}
+	}
+
+	for k := 1; k < len(fftRoots); k++ {
+		a := new(big.Float).SetPrec(prec).SetRat(r[k])
+		a.Mul(a, pi).Quo(a, big.NewFloat(2))
+		c, s := cosSin(a)
+		if fftRoots[k] != [2]fpr{fprConst(c), fprConst(s)} {
+			t.Fatalf("fftRoots[%v] = %v, want (%v, %v)", k, fftRoots[k], c, s)
+		}
+	}
+}
+
+func randPoly(r *rand.Rand, logn uint) []fpr {
+	f := make([]fpr, 1<<logn)
+	for i := range f {
+		f[i] = fprOf(r.Int64N(1<<16) - 1<<15)
+	}
+	return f
+}
+
+func checkClose(t *testing.T, got, want []fpr) {
+	t.Helper()
+	for i := range got {
+		if d := math.Abs(got[i].float64() - want[i].float64()); d > 1e-3 {
+			t.Fatalf("got[%v] = %v, want %v", i, got[i].float64(), want[i].float64())
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for logn := uint(1); logn <= 10; logn++ {
+		n := 1 << logn
+		a, b := randPoly(r, logn), randPoly(r, logn)
+
+		// Negacyclic product computed in coefficient representation.
+		want := make([]fpr, n)
+		for i := range n {
+			for j := range n {
+				p := a[i].mul(b[j])
+				if i+j < n {
+					want[i+j] = want[i+j].add(p)
+				} else {
+					want[i+j-n] = want[i+j-n].sub(p)
+				}
+			}
+		}
+
+		c := append([]fpr{}, a...)
+		d := append([]fpr{}, b...)
+		fft(c, logn)
+		fft(d, logn)
+		polyMulFFT(c, d)
+
+		// Splitting in FFT representation must be the same as splitting
+		// the coefficients into even and odd ones.
+		c0, c1 := make([]fpr, n/2), make([]fpr, n/2)
+		splitFFT(c0, c1, c, logn)
+		e := make([]fpr, n)
+		mergeFFT(e, c0, c1, logn)
+		ifft(c0, logn-1)
+		ifft(c1, logn-1)
+		for i := range n / 2 {
+			if math.Abs(c0[i].float64()-want[2*i].float64()) > 1e-3 ||
+				math.Abs(c1[i].float64()-want[2*i+1].float64()) > 1e-3 {
+				t.Fatalf("logn=%v: wrong split at %v", logn, i)
+			}
+		}
+
+		ifft(c, logn)
+		checkClose(t, c, want)
+		ifft(e, logn)
+		checkClose(t, e, want)
+	}
+}
@@ -0,0 +1,117 @@
+// Package fndsa provides the FN-DSA signature scheme, also known as Falcon.
+//
+// This package implements Falcon as described in the [round 3
+// specification], v1.2, which is the basis of the upcoming FIPS 206 standard
+// (FN-DSA). As FIPS 206 is not yet final, keys and signatures may not be
+// compatible with the standard once published. The [ID] represents the
+// following parameter sets:
+//   - [Falcon512] with security category 1.
+//   - [Falcon1024] with security category 5.
+//
+// Signatures use the padded format of fixed size.
+//
+// Signing relies on floating-point arithmetic, which is emulated with
+// integer operations so that it runs in constant time. Key generation
+// solves the NTRU equation with arbitrary-precision integers, so it is not
+// constant-time; keys should be generated where timing is not observable.
+//
+// Randomness of signing is expanded with the ChaCha20-based generator of the
+// reference implementation. The sampling of the secret polynomials f and g
+// in key generation differs from the reference one, so keys and signatures
+// differ from the reference ones for the same random source, though
+// signatures verify the same.
+//
+// [round 3 specification]: https://falcon-sign.info/falcon.pdf
+package fndsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+var (
+	ErrParam   = errors.New("sign/fndsa: invalid FN-DSA parameter")
+	ErrPreHash = errors.New("sign/fndsa: pre-hashed messages are not supported")
+)
+
+// [GenerateKey] returns a pair of keys using the parameter set specified.
+// It returns an error if it fails reading from the random source.
+func GenerateKey(
+	random io.Reader, id ID,
+) (pub PublicKey, priv PrivateKey, err error) {
+	var seed [SeedSize]byte
+	err = readRandom(random, seed[:])
+	if err != nil {
+		return
+	}
+
+	pub, priv = NewKeyFromSeed(id, &seed)
+	return
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of keys from a seed.
+func NewKeyFromSeed(id ID, seed *[SeedSize]byte) (PublicKey, PrivateKey) {
+	// See the Falcon specification -- Section 3.8 -- Algorithm 4.
+	params := id.params()
+	f, g, bigF, _ := params.keyGen(seed[:])
+	priv, ok := params.newPrivateKey(f, g, bigF)
+	if !ok {
+		panic("sign/fndsa: invalid generated key")
+	}
+
+	return priv.PublicKey(), priv
+}
+
+// [Sign] returns a randomized signature of the message.
+// It returns an error if it fails reading from the random source.
+func Sign(
+	priv *PrivateKey, random io.Reader, message []byte,
+) (signature []byte, err error) {
+	// See the Falcon specification -- Section 3.9 -- Algorithm 10.
+	params := priv.ID.params()
+	signature = make([]byte, params.SignatureSize())
+	nonce := signature[1 : 1+NonceSize]
+	err = readRandom(random, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed [SeedSize]byte
+	err = readRandom(random, seed[:])
+	if err != nil {
+		return nil, err
+	}
+
+	signature[0] = headerSignature + byte(params.logn)
+	hm := hashToPoint(params.n(), nonce, message)
+	priv.signInternal(signature[1+NonceSize:], hm, seed[:])
+	return signature, nil
+}
+
+// [Verify] returns true if the signature of the message is valid.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	// See the Falcon specification -- Section 3.10 -- Algorithm 16.
+	params := pub.ID.params()
+	if len(signature) != params.SignatureSize() ||
+		signature[0] != headerSignature+byte(params.logn) {
+		return false
+	}
+
+	s2 := make([]int16, params.n())
+	if !decompress(s2, signature[1+NonceSize:]) {
+		return false
+	}
+
+	nonce := signature[1 : 1+NonceSize]
+	hm := hashToPoint(params.n(), nonce, message)
+	return pub.verifyInternal(hm, s2)
+}
+
+func readRandom(random io.Reader, out []byte) (err error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	_, err = io.ReadFull(random, out)
+	return
+}
@@ -0,0 +1,112 @@
+package fndsa_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/sha3"
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign/fndsa"
+)
+
+var allIDs = [...]fndsa.ID{fndsa.Falcon512, fndsa.Falcon1024}
+
+func TestFndsa(t *testing.T) {
+	for _, id := range allIDs {
+		t.Run(id.String(), func(t *testing.T) {
+			t.Run("Keys", func(t *testing.T) { testKeys(t, id) })
+			t.Run("Sign", func(t *testing.T) { testSign(t, id) })
+		})
+	}
+}
+
+func testKeys(t *testing.T, id fndsa.ID) {
+	reader := sha3.NewShake128()
+
+	reader.Reset()
+	pub0, priv0, err := fndsa.GenerateKey(&reader, id)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+
+	reader.Reset()
+	pub1, priv1, err := fndsa.GenerateKey(&reader, id)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+
+	test.CheckOk(pub0.Equal(pub1), "public key not equal", t)
+	test.CheckOk(priv0.Equal(priv1), "private key not equal", t)
+	test.CheckOk(pub0.Equal(priv0.Public()), "public key mismatch", t)
+
+	test.CheckMarshal(t, &priv0, &priv1)
+	test.CheckMarshal(t, &pub0, &pub1)
+
+	scheme := id.Scheme()
+	seed := make([]byte, scheme.SeedSize())
+	pub2, priv2 := scheme.DeriveKey(seed)
+	pub3, priv3 := scheme.DeriveKey(seed)
+
+	test.CheckOk(priv2.Equal(priv3), "private key not equal", t)
+	test.CheckOk(pub2.Equal(pub3), "public key not equal", t)
+	test.CheckOk(!pub0.Equal(pub2), "public keys must differ", t)
+
+	// Headers must match the parameter set.
+	b, err := pub0.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	b[0]++
+	_, err = scheme.UnmarshalBinaryPublicKey(b)
+	test.CheckIsErr(t, err, "UnmarshalBinary should fail")
+
+	b, err = priv0.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	b[0]++
+	_, err = scheme.UnmarshalBinaryPrivateKey(b)
+	test.CheckIsErr(t, err, "UnmarshalBinary should fail")
+}
+
+func testSign(t *testing.T, id fndsa.ID) {
+	pub, priv, err := fndsa.GenerateKey(rand.Reader, id)
+	test.CheckNoErr(t, err, "GenerateKey failed")
+
+	msg := []byte("Alice and Bob")
+	sig, err := priv.Sign(rand.Reader, msg, crypto.Hash(0))
+	test.CheckNoErr(t, err, "Sign failed")
+	test.CheckOk(len(sig) == id.Scheme().SignatureSize(), "wrong signature size", t)
+	test.CheckOk(fndsa.Verify(&pub, msg, sig), "Verify failed", t)
+
+	_, err = priv.Sign(rand.Reader, msg, crypto.SHA256)
+	test.CheckIsErr(t, err, "Sign should fail with pre-hash")
+
+	test.CheckOk(!fndsa.Verify(&pub, msg[1:], sig), "Verify should fail", t)
+	test.CheckOk(!fndsa.Verify(&pub, msg, sig[:len(sig)-1]), "Verify should fail", t)
+
+	// Any change to the header, nonce, or signature value is detected,
+	// including non-zero padding.
+	for _, i := range []int{0, 1, fndsa.NonceSize + 1, len(sig) - 1} {
+		sig[i] ^= 1
+		test.CheckOk(!fndsa.Verify(&pub, msg, sig), "Verify should fail", t)
+		sig[i] ^= 1
+	}
+}
+
+func BenchmarkFndsa(b *testing.B) {
+	for _, id := range allIDs {
+		pub, priv, _ := fndsa.GenerateKey(rand.Reader, id)
+		msg := []byte("Alice and Bob")
+		sig, _ := fndsa.Sign(&priv, rand.Reader, msg)
+
+		b.Run(id.String()+"/GenerateKey", func(b *testing.B) {
+			for range b.N {
+				_, _, _ = fndsa.GenerateKey(rand.Reader, id)
+			}
+		})
+		b.Run(id.String()+"/Sign", func(b *testing.B) {
+			for range b.N {
+				_, _ = fndsa.Sign(&priv, rand.Reader, msg)
+			}
+		})
+		b.Run(id.String()+"/Verify", func(b *testing.B) {
+			for range b.N {
+				_ = fndsa.Verify(&pub, msg, sig)
+			}
+		})
+	}
+}
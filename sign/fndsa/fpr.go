@@ -0,0 +1,346 @@
+package fndsa
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fpr is an IEEE-754 binary64 value whose arithmetic is emulated with
+// integer operations, so that it runs in constant time regardless of the
+// operands. Subnormals are not supported and flushed to zero; infinities
+// and NaNs are never produced by Falcon, hence not handled either.
+// Results are rounded to the nearest, ties to even, so they match the
+// native float64 operations bit for bit on normal values.
+type fpr uint64
+
+func fprConst(x float64) fpr { return fpr(math.Float64bits(x)) }
+
+var (
+	fprZero         = fprConst(0)
+	fprOne          = fprConst(1)
+	fprTwo          = fprConst(2)
+	fprQ            = fprConst(Q)
+	fprInvQ         = fprConst(1.0 / Q)
+	fprLog2         = fprConst(math.Ln2)
+	fprInvLog2      = fprConst(1 / math.Ln2)
+	fprPTwo63       = fprConst(1 << 63)
+	fprInvSqrSigma0 = fprConst(1 / (2 * sigma0 * sigma0))
+)
+
+// float64 returns the native value of x, only used in tests.
+func (x fpr) float64() float64 { return math.Float64frombits(uint64(x)) }
+
+// fprPack assembles a value from its sign bit s, its exponent e and its
+// mantissa m, which is either zero or in [2^54, 2^55). The two lowest bits
+// of m are used for rounding, the last one being sticky.
+func fprPack(s uint64, e int, m uint64) fpr {
+	// Values below the smallest normal value are flushed to zero.
+	e += 1076
+	t := uint64(uint32(e) >> 31)
+	m &= t - 1
+
+	// If m is zero, e is set to zero too, but the sign is kept.
+	t = m >> 54
+	e &= -int(t)
+
+	// The top bit of m increments the exponent by one, except if m is zero.
+	x := (s<<63 | m>>2) + uint64(uint32(e))<<52
+
+	// Round to nearest: the low three bits being 011, 110 or 111 means an
+	// increment. A carry may spill into the exponent as expected.
+	x += (0xC8 >> (m & 7)) & 1
+	return fpr(x)
+}
+
+// fprNorm64 shifts m until its top bit is set and adjusts e accordingly.
+// If m is zero, it stays zero.
+func fprNorm64(m uint64, e int) (uint64, int) {
+	e -= 63
+	m, e = fprNormStep(m, e, 32)
+	m, e = fprNormStep(m, e, 16)
+	m, e = fprNormStep(m, e, 8)
+	m, e = fprNormStep(m, e, 4)
+	m, e = fprNormStep(m, e, 2)
+	m, e = fprNormStep(m, e, 1)
+	return m, e
+}
+
+// fprNormStep shifts m left by s bits if its top s bits are zero, or adds
+// s to e otherwise.
+func fprNormStep(m uint64, e int, s uint) (uint64, int) {
+	nt := m >> (64 - s)
+	nt = (nt | -nt) >> 63
+	m ^= (m ^ (m << s)) & (nt - 1)
+	return m, e + int(nt)*int(s)
+}
+
+// fprScaled returns i*2^sc.
+func fprScaled(i int64, sc int) fpr {
+	s := uint64(i) >> 63
+	u := (uint64(i) ^ -s) + s
+
+	m, e := fprNorm64(u, 9+sc)
+
+	// Shift m down to [2^54, 2^55) keeping a sticky bit.
+	m |= (m & 0x1FF) + 0x1FF
+	m >>= 9
+
+	// If i is zero, all the above was wrong, clamp to zero.
+	t := (u | -u) >> 63
+	m &= -t
+	e &= -int(t)
+
+	return fprPack(s, e, m)
+}
+
+func fprOf(i int64) fpr { return fprScaled(i, 0) }
+
+func (x fpr) neg() fpr { return x ^ (1 << 63) }
+
+func (x fpr) half() fpr {
+	x -= 1 << 52
+	t := ((uint32(x>>52) & 0x7FF) + 1) >> 11
+	return x & fpr(uint64(t)-1)
+}
+
+func (x fpr) double() fpr {
+	return x + fpr(uint64((uint32(x>>52)&0x7FF+0x7FF)>>11)<<52)
+}
+
+func (x fpr) add(y fpr) fpr {
+	// Swap so that |x| >= |y|. If |x| = |y| and the signs differ, the
+	// positive one is taken as x, so the result is +0.
+	const mask = 1<<63 - 1
+	za := uint64(x&mask) - uint64(y&mask)
+	cs := za>>63 | ((1 - (-za)>>63) & uint64(x>>63))
+	m := (x ^ y) & fpr(-cs)
+	x ^= m
+	y ^= m
+
+	// Mantissas are scaled up to [2^55, 2^56) and exponents unbiased.
+	ex := int(x >> 52)
+	sx := uint64(ex >> 11)
+	ex &= 0x7FF
+	xu := (uint64(x)&(1<<52-1) | uint64((ex+0x7FF)>>11)<<52) << 3
+	ex -= 1078
+	ey := int(y >> 52)
+	sy := uint64(ey >> 11)
+	ey &= 0x7FF
+	yu := (uint64(y)&(1<<52-1) | uint64((ey+0x7FF)>>11)<<52) << 3
+	ey -= 1078
+
+	// Right-shift y; a shift of 60 bits or more clears it.
+	cc := ex - ey
+	yu &= -uint64(uint32(cc-60) >> 31)
+	cc &= 63
+
+	// The lowest bit of yu is sticky.
+	ms := uint64(1)<<cc - 1
+	yu |= (yu & ms) + ms
+	yu >>= cc
+
+	// Add or subtract the mantissas according to the signs.
+	xu += yu - ((yu << 1) & -(sx ^ sy))
+
+	xu, ex = fprNorm64(xu, ex)
+	xu |= (xu & 0x1FF) + 0x1FF
+	xu >>= 9
+	ex += 9
+
+	return fprPack(sx, ex, xu)
+}
+
+func (x fpr) sub(y fpr) fpr { return x.add(y.neg()) }
+
+func (x fpr) mul(y fpr) fpr {
+	xu := uint64(x)&(1<<52-1) | 1<<52
+	yu := uint64(y)&(1<<52-1) | 1<<52
+
+	// The product is in [2^104, 2^106), keep its top part in
+	// [2^54, 2^56) with a sticky bit.
+	hi, lo := bits.Mul64(xu, yu)
+	zu := hi<<14 | lo>>50
+	zu |= ((lo & (1<<50 - 1)) + (1<<50 - 1)) >> 50
+
+	// Normalize to [2^54, 2^55).
+	zv := zu>>1 | zu&1
+	w := zu >> 55
+	zu ^= (zu ^ zv) & -w
+
+	ex := int(x>>52) & 0x7FF
+	ey := int(y>>52) & 0x7FF
+	e := ex + ey - 2100 + int(w)
+	s := uint64(x^y) >> 63
+
+	// If either operand is zero, so is the result.
+	d := ((ex + 0x7FF) & (ey + 0x7FF)) >> 11
+	zu &= -uint64(d)
+
+	return fprPack(s, e, zu)
+}
+
+func (x fpr) sqr() fpr { return x.mul(x) }
+
+// div returns x/y. The divisor must not be zero.
+func (x fpr) div(y fpr) fpr {
+	xu := uint64(x)&(1<<52-1) | 1<<52
+	yu := uint64(y)&(1<<52-1) | 1<<52
+
+	// Bit-by-bit division producing 55 bits of quotient.
+	var q uint64
+	for range 55 {
+		b := ((xu - yu) >> 63) - 1
+		xu -= b & yu
+		q |= b & 1
+		xu <<= 1
+		q <<= 1
+	}
+
+	// The extra 56th bit is sticky.
+	q |= (xu | -xu) >> 63
+
+	// Normalize to [2^54, 2^55).
+	q2 := q>>1 | q&1
+	w := q >> 55
+	q ^= (q ^ q2) & -w
+
+	ex := int(x>>52) & 0x7FF
+	ey := int(y>>52) & 0x7FF
+	e := ex - ey - 55 + int(w)
+	s := uint64(x^y) >> 63
+
+	// If x is zero, so is the result.
+	d := (ex + 0x7FF) >> 11
+	s &= uint64(d)
+	e &= -d
+	q &= -uint64(d)
+
+	return fprPack(s, e, q)
+}
+
+func (x fpr) inv() fpr { return fprOne.div(x) }
+
+// sqrt returns the square root of x, which must not be negative.
+func (x fpr) sqrt() fpr {
+	xu := uint64(x)&(1<<52-1) | 1<<52
+	ex := int(x>>52) & 0x7FF
+	e := ex - 1023
+
+	// Make the exponent even, then halve it.
+	xu += xu & -uint64(e&1)
+	e >>= 1
+	xu <<= 1
+
+	// Bit-by-bit square root of xu, a fixed-point value in [1, 4).
+	var q, s uint64
+	r := uint64(1) << 53
+	for range 54 {
+		t := s + r
+		b := ((xu - t) >> 63) - 1
+		s += (r << 1) & b
+		xu -= t & b
+		q += r & b
+		xu <<= 1
+		r >>= 1
+	}
+
+	q <<= 1
+	q |= (xu | -xu) >> 63
+	e -= 54
+
+	// If x is zero, so is the result.
+	q &= -uint64((ex + 0x7FF) >> 11)
+
+	return fprPack(0, e, q)
+}
+
+// lt returns 1 if x < y, and 0 otherwise.
+func (x fpr) lt(y fpr) int {
+	sx := int64(x)
+	sy := int64(y)
+	sy &^= (sx ^ sy) >> 63
+
+	cc0 := int((sx-sy)>>63) & 1
+	cc1 := int((sy-sx)>>63) & 1
+
+	return cc0 ^ ((cc0 ^ cc1) & int(uint64(x&y)>>63))
+}
+
+// rint rounds x to the nearest integer, ties to even.
+// The value must fit in an int64.
+func (x fpr) rint() int64 {
+	m := (uint64(x)<<10 | 1<<62) & (1<<63 - 1)
+	e := 1085 - int(x>>52)&0x7FF
+
+	// A shift of 64 bits or more gives zero, which also covers x = 0.
+	m &= -uint64(uint32(e-64) >> 31)
+	e &= 63
+
+	// Collect the dropped bits and the lowest kept bit into three bits,
+	// the lowest being sticky, to apply rounding.
+	d := m << (63 - e)
+	dd := uint32(d) | (uint32(d>>32) & 0x1FFFFFFF)
+	f := uint32(d>>61) | ((dd | -dd) >> 31)
+	m = m>>e + uint64((0xC8>>f)&1)
+
+	s := uint64(x) >> 63
+	return int64((m ^ -s) + s)
+}
+
+// floor rounds x towards minus infinity.
+// The value must fit in an int64.
+func (x fpr) floor() int64 {
+	e := int(x>>52) & 0x7FF
+	t := int64(uint64(x) >> 63)
+	xi := int64((uint64(x)<<10 | 1<<62) & (1<<63 - 1))
+	xi = (xi ^ -t) + t
+	cc := 1085 - e
+
+	xi >>= cc & 63
+
+	// A shift of 64 bits or more gives 0 or -1 depending on the sign.
+	xi ^= (xi ^ -t) & -int64(uint32(63-cc)>>31)
+	return xi
+}
+
+// trunc rounds x towards zero.
+// The value must fit in an int64.
+func (x fpr) trunc() int64 {
+	e := int(x>>52) & 0x7FF
+	xu := (uint64(x)<<10 | 1<<62) & (1<<63 - 1)
+	cc := 1085 - e
+	xu >>= cc & 63
+
+	// A shift of 64 bits or more gives zero, which also covers x = 0.
+	xu &= -uint64(uint32(cc-64) >> 31)
+
+	t := uint64(x) >> 63
+	return int64((xu ^ -t) + t)
+}
+
+// expmP63 returns 2^63*ccs*exp(-x) for 0 <= x < log(2) and 0 <= ccs <= 1,
+// rounded down to an integer.
+//
+// The polynomial approximation of exp(-x) is from "FACCT: FAst, Compact,
+// and Constant-Time Discrete Gaussian Sampler over Integers" by Zhao, Steinfeld
+// and Sakzad, as used by the Falcon reference implementation.
+func expmP63(x, ccs fpr) uint64 {
+	c := [...]uint64{
+		0x00000004741183A3, 0x00000036548CFC06, 0x0000024FDCBF140A,
+		0x0000171D939DE045, 0x0000D00CF58F6F84, 0x000680681CF796E3,
+		0x002D82D8305B0FEA, 0x011111110E066FD0, 0x0555555555070F00,
+		0x155555555581FF00, 0x400000000002B400, 0x7FFFFFFFFFFF4800,
+		0x8000000000000000,
+	}
+
+	y := c[0]
+	z := uint64(x.mul(fprPTwo63).trunc()) << 1
+	for _, ci := range c[1:] {
+		hi, _ := bits.Mul64(z, y)
+		y = ci - hi
+	}
+
+	z = uint64(ccs.mul(fprPTwo63).trunc()) << 1
+	y, _ = bits.Mul64(z, y)
+	return y
+}
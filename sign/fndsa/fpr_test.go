@@ -0,0 +1,72 @@
+package fndsa
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func randFloat(r *rand.Rand) float64 {
+	switch r.IntN(4) {
+	case 0:
+		return 0
+	case 1:
+		return float64(r.Int64N(1<<20)-1<<19) / 2
+	default:
+		return math.Ldexp(r.Float64()-0.5, r.IntN(120)-60)
+	}
+}
+
+func TestFpr(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 1 << 18 {
+		a, b := randFloat(r), randFloat(r)
+		x, y := fprConst(a), fprConst(b)
+		check := func(op string, got fpr, want float64) {
+			// Zeros may differ in sign, which does not matter for Falcon.
+			if got != fprConst(want) && (want != 0 || got.float64() != 0) {
+				t.Fatalf("%v(%v, %v) = %v, want %v", op, a, b, got.float64(), want)
+			}
+		}
+		check("add", x.add(y), a+b)
+		check("sub", x.sub(y), a-b)
+		check("mul", x.mul(y), a*b)
+		check("half", x.half(), a/2)
+		check("double", x.double(), a*2)
+		if b != 0 {
+			check("div", x.div(y), a/b)
+		}
+		check("sqrt", fprConst(math.Abs(a)).sqrt(), math.Sqrt(math.Abs(a)))
+		if got, want := x.lt(y), a < b; (got == 1) != want {
+			t.Fatalf("lt(%v, %v) = %v", a, b, got)
+		}
+		if math.Abs(a) < 1<<62 {
+			if got, want := x.rint(), int64(math.RoundToEven(a)); got != want {
+				t.Fatalf("rint(%v) = %v, want %v", a, got, want)
+			}
+			if got, want := x.floor(), int64(math.Floor(a)); got != want {
+				t.Fatalf("floor(%v) = %v, want %v", a, got, want)
+			}
+			if got, want := x.trunc(), int64(math.Trunc(a)); got != want {
+				t.Fatalf("trunc(%v) = %v, want %v", a, got, want)
+			}
+		}
+		i := r.Int64() >> r.IntN(64)
+		if got, want := fprOf(i), fprConst(float64(i)); got != want {
+			t.Fatalf("of(%v) = %v, want %v", i, got.float64(), want.float64())
+		}
+	}
+}
+
+func TestExpmP63(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 1 << 12 {
+		x := r.Float64() * math.Ln2
+		ccs := r.Float64()
+		got := float64(expmP63(fprConst(x), fprConst(ccs)))
+		want := math.Ldexp(ccs*math.Exp(-x), 63)
+		if math.Abs(got-want) > math.Ldexp(1, 63-45) {
+			t.Fatalf("expmP63(%v, %v) = %v, want %v", x, ccs, got, want)
+		}
+	}
+}
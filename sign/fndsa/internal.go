@@ -0,0 +1,216 @@
+package fndsa
+
+import (
+	"encoding/binary"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// hashToPoint returns the hash of the nonce and the message as a
+// polynomial of Z_q[x]/(x^n+1), see the Falcon specification, Algorithm 3.
+// It is not constant-time, which is fine as the message is public.
+func hashToPoint(n int, nonce, message []byte) []uint16 {
+	h := sha3.NewShake256()
+	_, _ = h.Write(nonce)
+	_, _ = h.Write(message)
+
+	c := make([]uint16, n)
+	var buf [2]byte
+	for i := 0; i < n; {
+		_, _ = h.Read(buf[:])
+		t := binary.BigEndian.Uint16(buf[:])
+		if t < 5*Q {
+			c[i] = t % Q
+			i++
+		}
+	}
+	return c
+}
+
+// treeSize returns the number of values in a Falcon tree of degree 2^logn.
+func treeSize(logn uint) int { return (int(logn) + 1) << logn }
+
+// ffLDL builds the Falcon tree of the Gram matrix [[g00, g01], [adj(g01),
+// g11]] in FFT representation, whose leaves are normalized to hold
+// sqrt(d)/sigma, see the Falcon specification, Algorithms 9 and 10.
+// The inputs are overwritten.
+func ffLDL(tree, g00, g01, g11 []fpr, logn uint, invSigma fpr) {
+	if logn == 0 {
+		tree[0] = g00[0].sqrt().mul(invSigma)
+		return
+	}
+
+	// LDL decomposition: l10 = adj(g01)/g00 and d11 = g11 - l10*g01.
+	n := 1 << logn
+	l10 := tree[:n]
+	copy(l10, g01)
+	polyAdjFFT(l10)
+	polyDivRealFFT(l10, g00)
+	polyMulFFT(g01, l10)
+	polySub(g11, g01)
+
+	// Both d00 = g00 and d11 are self-adjoint, so their halves define the
+	// Gram matrices of the children.
+	hn := n >> 1
+	a0, a1 := make([]fpr, hn), make([]fpr, hn)
+	splitFFT(a0, a1, g00, logn)
+	b0, b1 := make([]fpr, hn), make([]fpr, hn)
+	splitFFT(b0, b1, g11, logn)
+
+	ts := treeSize(logn - 1)
+	ffLDL(tree[n:n+ts], a0, a1, append([]fpr{}, a0...), logn-1, invSigma)
+	ffLDL(tree[n+ts:], b0, b1, append([]fpr{}, b0...), logn-1, invSigma)
+}
+
+// ffSampling samples (z0, z1) close to (t0, t1) using the Falcon tree,
+// see the Falcon specification, Algorithm 11. All the polynomials are in
+// FFT representation, and tmp must have room for 4*2^logn values.
+func (s *sampler) ffSampling(z0, z1, tree, t0, t1 []fpr, logn uint, tmp []fpr) {
+	if logn == 0 {
+		z0[0] = fprOf(s.sample(t0[0], tree[0]))
+		z1[0] = fprOf(s.sample(t1[0], tree[0]))
+		return
+	}
+
+	n := 1 << logn
+	hn := n >> 1
+	ts := treeSize(logn - 1)
+	l10, left, right := tree[:n], tree[n:n+ts], tree[n+ts:]
+	a, b, rest := tmp[:n], tmp[n:2*n], tmp[2*n:]
+
+	// z1 is sampled from t1 with the right subtree.
+	splitFFT(a[:hn], a[hn:], t1, logn)
+	s.ffSampling(b[:hn], b[hn:], right, a[:hn], a[hn:], logn-1, rest)
+	mergeFFT(z1, b[:hn], b[hn:], logn)
+
+	// z0 is sampled from t0 + (t1 - z1)*l10 with the left subtree.
+	copy(a, t1)
+	polySub(a, z1)
+	polyMulFFT(a, l10)
+	polyAdd(a, t0)
+	splitFFT(b[:hn], b[hn:], a, logn)
+	s.ffSampling(a[:hn], a[hn:], left, b[:hn], b[hn:], logn-1, rest)
+	mergeFFT(z0, a[:hn], a[hn:], logn)
+}
+
+// fprPolyOf returns the FFT representation of a, possibly negated.
+func fprPolyOf(a []int16, logn uint, neg bool) []fpr {
+	r := make([]fpr, len(a))
+	for i := range a {
+		r[i] = fprOf(int64(a[i]))
+	}
+	if neg {
+		polyNeg(r)
+	}
+	fft(r, logn)
+	return r
+}
+
+// gram returns a*adj(a) + b*adj(b), or a*adj(c) + b*adj(d) if given.
+func gram(a, b, c, d []fpr) []fpr {
+	x := append([]fpr{}, a...)
+	y := append([]fpr{}, b...)
+	polyMulAdjFFT(x, c)
+	polyMulAdjFFT(y, d)
+	polyAdd(x, y)
+	return x
+}
+
+// signInternal returns the signature s2 of the hashed message hm, so that
+// s1 = hm - s2*h is short, see the Falcon specification, Algorithm 10.
+// Randomness is drawn from the seed. It loops until the signature is
+// short enough and fits in the signature size.
+func (k *PrivateKey) signInternal(out []byte, hm []uint16, seed []byte) {
+	p := k.ID.params()
+	logn := p.logn
+	n := p.n()
+
+	// Basis B = [[g, -f], [G, -F]] and its Gram matrix in FFT representation.
+	b00 := fprPolyOf(k.g, logn, false)
+	b01 := fprPolyOf(k.f, logn, true)
+	b10 := fprPolyOf(k.bigG, logn, false)
+	b11 := fprPolyOf(k.bigF, logn, true)
+	g00 := gram(b00, b01, b00, b01)
+	g01 := gram(b00, b01, b10, b11)
+	g11 := gram(b10, b11, b10, b11)
+
+	tree := make([]fpr, treeSize(logn))
+	ffLDL(tree, g00, g01, g11, logn, fprConst(1/p.sigma))
+
+	// Target vector (t0, t1) = (hm, 0)*B^-1 = (-hm*F, hm*f)/q.
+	t0 := make([]fpr, n)
+	for i := range hm {
+		t0[i] = fprOf(int64(hm[i]))
+	}
+	fft(t0, logn)
+	t1 := append([]fpr{}, t0...)
+	polyMulFFT(t1, b01)
+	polyMulConst(t1, fprInvQ.neg())
+	polyMulFFT(t0, b11)
+	polyMulConst(t0, fprInvQ)
+
+	// Each attempt seeds a new generator from SHAKE256(seed), as in the
+	// reference implementation.
+	src := newPRNG(seed)
+	z0, z1 := make([]fpr, n), make([]fpr, n)
+	v := make([]fpr, n)
+	tmp := make([]fpr, 4*n)
+	s2 := make([]int16, n)
+	for {
+		s := sampler{newChachaPRNG(src), fprConst(p.sigmaMin)}
+		s.ffSampling(z0, z1, tree, t0, t1, logn, tmp)
+
+		// Lattice point (v0, v1) = (z0, z1)*B, which is close to (hm, 0).
+		copy(v, z0)
+		polyMulFFT(v, b00)
+		copy(tmp, z1)
+		polyMulFFT(tmp[:n], b10)
+		polyAdd(v, tmp[:n])
+		ifft(v, logn)
+
+		polyMulFFT(z0, b01)
+		polyMulFFT(z1, b11)
+		polyAdd(z1, z0)
+		ifft(z1, logn)
+
+		// The signature is (s1, s2) = (hm, 0) - (v0, v1).
+		var sqn int64
+		for i := range hm {
+			s1 := int64(hm[i]) - v[i].rint()
+			x := -z1[i].rint()
+			sqn += s1*s1 + x*x
+			s2[i] = int16(x)
+		}
+
+		if sqn <= int64(p.bound) && compress(out, s2) {
+			return
+		}
+	}
+}
+
+// verifyInternal returns true if s2 is a valid signature of the hashed
+// message hm, see the Falcon specification, Algorithm 16.
+func (k *PublicKey) verifyInternal(hm []uint16, s2 []int16) bool {
+	p := k.ID.params()
+
+	// s1 = hm - s2*h mod q.
+	s1 := modqOf(s2)
+	h := make([]uint32, len(k.h))
+	for i := range k.h {
+		h[i] = uint32(k.h[i])
+	}
+	ntt(s1, p.logn)
+	ntt(h, p.logn)
+	for i := range s1 {
+		s1[i] = modqMul(s1[i], h[i])
+	}
+	invNTT(s1, p.logn)
+
+	var sqn int64
+	for i := range s1 {
+		x := int64(modqCenter(modqSub(uint32(hm[i]), s1[i])))
+		y := int64(s2[i])
+		sqn += x*x + y*y
+	}
+	return sqn <= int64(p.bound)
+}
@@ -0,0 +1,89 @@
+package fndsa
+
+// Code to generate test vectors in the format of the NIST "PQCsignKAT"
+// files, using the NIST DRBG as random source for both key generation and
+// signing.
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/nist"
+	"github.com/cloudflare/circl/internal/test"
+)
+
+func TestPQCgenKATSign(t *testing.T) {
+	for _, tc := range []struct {
+		id   ID
+		want string
+	}{
+		// Generated by this package. Signing uses the generator of the
+		// reference implementation, but the sampling of f and g and the
+		// padded signature format differ, so the PQCsignKAT_*.rsp files of
+		// round 3 cannot be reproduced.
+		{Falcon512, "7358fc4bd82c235a64d22c47c88816cd81245636edca6080218579bd0eeb0eef"},
+		{Falcon1024, "7f2a3ddf1d858b46c8773c1a0664c34201061afe4ad072e9571e6703e9e1c313"},
+	} {
+		t.Run(tc.id.String(), func(t *testing.T) {
+			var seed [48]byte
+			var kseed [SeedSize]byte
+			for i := 0; i < 48; i++ {
+				seed[i] = byte(i)
+			}
+			f := sha256.New()
+			g := nist.NewDRBG(&seed)
+			mustWrite(t, f, "# %s\n\n", tc.id)
+			for i := 0; i < 100; i++ {
+				mlen := 33 * (i + 1)
+				g.Fill(seed[:])
+				msg := make([]byte, mlen)
+				g.Fill(msg[:])
+
+				mustWrite(t, f, "count = %d\n", i)
+				mustWrite(t, f, "seed = %X\n", seed)
+				mustWrite(t, f, "mlen = %d\n", mlen)
+				mustWrite(t, f, "msg = %X\n", msg)
+
+				g2 := nist.NewDRBG(&seed)
+				g2.Fill(kseed[:])
+				pk, sk := NewKeyFromSeed(tc.id, &kseed)
+
+				ppk, err := pk.MarshalBinary()
+				test.CheckNoErr(t, err, "MarshalBinary failed")
+				psk, err := sk.MarshalBinary()
+				test.CheckNoErr(t, err, "MarshalBinary failed")
+
+				mustWrite(t, f, "pk = %X\n", ppk)
+				mustWrite(t, f, "sk = %X\n", psk)
+				mustWrite(t, f, "smlen = %d\n", mlen+tc.id.params().SignatureSize())
+
+				sig, err := Sign(&sk, drbgReader{&g2}, msg)
+				test.CheckNoErr(t, err, "Sign failed")
+
+				mustWrite(t, f, "sm = %X%X\n\n", sig, msg)
+
+				if !Verify(&pk, msg, sig) {
+					t.Fatal()
+				}
+			}
+			if got := fmt.Sprintf("%x", f.Sum(nil)); got != tc.want {
+				t.Fatal(got)
+			}
+		})
+	}
+}
+
+// drbgReader reads random bytes from a DRBG.
+type drbgReader struct{ *nist.DRBG }
+
+func (r drbgReader) Read(b []byte) (int, error) {
+	r.Fill(b)
+	return len(b), nil
+}
+
+func mustWrite(t *testing.T, f io.Writer, format string, data ...any) {
+	_, err := fmt.Fprintf(f, format, data...)
+	test.CheckNoErr(t, err, "fprintf failed")
+}
@@ -0,0 +1,168 @@
+package fndsa
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"io"
+	"slices"
+
+	"github.com/cloudflare/circl/internal/conv"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// [PrivateKey] stores a private key of the FN-DSA scheme.
+// It implements the [crypto.Signer] and [crypto.PrivateKey] interfaces.
+// For serialization, it also implements [cryptobyte.MarshalingValue],
+// [encoding.BinaryMarshaler], and [encoding.BinaryUnmarshaler].
+type PrivateKey struct {
+	f, g, bigF, bigG []int16
+	publicKey        PublicKey
+	ID
+}
+
+// Marshal serializes the key using a [cryptobyte.Builder].
+func (k PrivateKey) Marshal(b *cryptobyte.Builder) error {
+	params := k.ID.params()
+	n := params.n()
+	fgSize := int(params.fgBits) * n / 8
+	buf := make([]byte, params.PrivateKeySize())
+	buf[0] = headerPrivateKey + byte(params.logn)
+	encodeSmall(buf[1:], k.f, params.fgBits)
+	encodeSmall(buf[1+fgSize:], k.g, params.fgBits)
+	encodeSmall(buf[1+2*fgSize:], k.bigF, 8)
+	b.AddBytes(buf)
+	return nil
+}
+
+// Unmarshal recovers a [PrivateKey] from a [cryptobyte.String].
+// Caller must specify the private key's [ID] in advance.
+// Example:
+//
+//	key := PrivateKey{ID: Falcon512}
+//	key.Unmarshal(str) // returns true
+func (k *PrivateKey) Unmarshal(s *cryptobyte.String) bool {
+	params := k.ID.params()
+	b := make([]byte, params.PrivateKeySize())
+	if !s.CopyBytes(b) || b[0] != headerPrivateKey+byte(params.logn) {
+		return false
+	}
+
+	n := params.n()
+	fgSize := int(params.fgBits) * n / 8
+	f, g, bigF := make([]int16, n), make([]int16, n), make([]int16, n)
+	if !decodeSmall(f, b[1:], params.fgBits) ||
+		!decodeSmall(g, b[1+fgSize:], params.fgBits) ||
+		!decodeSmall(bigF, b[1+2*fgSize:], 8) {
+		return false
+	}
+
+	key, ok := params.newPrivateKey(f, g, bigF)
+	if ok {
+		*k = key
+	}
+	return ok
+}
+
+// newPrivateKey completes the basis and computes the public key,
+// it returns false if f is not invertible.
+func (p *params) newPrivateKey(f, g, bigF []int16) (PrivateKey, bool) {
+	bigG, okG := p.completeBasis(f, g, bigF)
+	h, okH := p.publicKey(f, g)
+	return PrivateKey{
+		f: f, g: g, bigF: bigF, bigG: bigG,
+		publicKey: PublicKey{h: h, ID: p.ID},
+		ID:        p.ID,
+	}, okG && okH
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes.
+// Caller must specify the private key's [ID] in advance.
+// Example:
+//
+//	key := PrivateKey{ID: Falcon512}
+//	key.UnmarshalBinary(bytes) // returns nil
+func (k *PrivateKey) UnmarshalBinary(b []byte) error { return conv.UnmarshalBinary(k, b) }
+func (k PrivateKey) MarshalBinary() ([]byte, error)  { return conv.MarshalBinary(k) }
+func (k PrivateKey) Public() crypto.PublicKey        { return k.PublicKey() }
+func (k PrivateKey) PublicKey() PublicKey {
+	return PublicKey{h: slices.Clone(k.publicKey.h), ID: k.ID}
+}
+
+func (k PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(PrivateKey)
+	if !ok || k.ID != other.ID {
+		return false
+	}
+
+	// The private key is determined by f, g and F.
+	a, _ := k.MarshalBinary()
+	b, _ := other.MarshalBinary()
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// [PrivateKey.Sign] returns a randomized signature of the message.
+// Pre-hashed messages are not supported, so opts.HashFunc() must be zero.
+// It returns an error if it fails reading from the random source.
+func (k PrivateKey) Sign(
+	random io.Reader, message []byte, opts crypto.SignerOpts,
+) (signature []byte, err error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, ErrPreHash
+	}
+
+	return Sign(&k, random, message)
+}
+
+// [PublicKey] stores a public key of the FN-DSA scheme.
+// It implements the [crypto.PublicKey] interface.
+// For serialization, it also implements [cryptobyte.MarshalingValue],
+// [encoding.BinaryMarshaler], and [encoding.BinaryUnmarshaler].
+type PublicKey struct {
+	h []uint16
+	ID
+}
+
+// Marshal serializes the key using a [cryptobyte.Builder].
+func (k PublicKey) Marshal(b *cryptobyte.Builder) error {
+	params := k.ID.params()
+	buf := make([]byte, params.PublicKeySize())
+	buf[0] = headerPublicKey + byte(params.logn)
+	encodeModq(buf[1:], k.h)
+	b.AddBytes(buf)
+	return nil
+}
+
+// Unmarshal recovers a [PublicKey] from a [cryptobyte.String].
+// Caller must specify the public key's [ID] in advance.
+// Example:
+//
+//	key := PublicKey{ID: Falcon512}
+//	key.Unmarshal(str) // returns true
+func (k *PublicKey) Unmarshal(s *cryptobyte.String) bool {
+	params := k.ID.params()
+	b := make([]byte, params.PublicKeySize())
+	if !s.CopyBytes(b) || b[0] != headerPublicKey+byte(params.logn) {
+		return false
+	}
+
+	h := make([]uint16, params.n())
+	if !decodeModq(h, b[1:]) {
+		return false
+	}
+
+	k.h = h
+	return true
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+// Caller must specify the public key's [ID] in advance.
+// Example:
+//
+//	key := PublicKey{ID: Falcon512}
+//	key.UnmarshalBinary(bytes) // returns nil
+func (k *PublicKey) UnmarshalBinary(b []byte) error { return conv.UnmarshalBinary(k, b) }
+func (k PublicKey) MarshalBinary() ([]byte, error)  { return conv.MarshalBinary(k) }
+func (k PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(PublicKey)
+	return ok && k.ID == other.ID && slices.Equal(k.h, other.h)
+}
@@ -0,0 +1,114 @@
+package fndsa
+
+// Arithmetic of polynomials of Z_q[x]/(x^n+1), with coefficients in [0, q).
+// The NTT evaluates a polynomial at the n primitive 2n-th roots of unity
+// modulo q, which exist as 2n divides q-1 for n <= 1024.
+
+// nttTables stores the powers of a primitive 2n-th root of unity used by
+// the NTT in bit-reversed order, and their inverses.
+type nttTables struct {
+	zetas, invZetas []uint32
+	invN            uint32
+}
+
+var nttByLogn [11]nttTables
+
+func init() {
+	// 11 is a generator of the multiplicative group of Z_q, see TestNTT.
+	for logn := range nttByLogn {
+		n := 1 << logn
+		psi := modqPow(11, (Q-1)/uint32(2*n))
+		t := nttTables{
+			zetas:    make([]uint32, n),
+			invZetas: make([]uint32, n),
+			invN:     modqPow(uint32(n), Q-2),
+		}
+		for k := range n {
+			e := uint32(0)
+			for b := range logn {
+				e |= uint32((k>>b)&1) << (logn - 1 - b)
+			}
+			t.zetas[k] = modqPow(psi, e)
+			t.invZetas[k] = modqPow(t.zetas[k], Q-2)
+		}
+		nttByLogn[logn] = t
+	}
+}
+
+func modqAdd(a, b uint32) uint32 { return (a + b) % Q }
+func modqSub(a, b uint32) uint32 { return (a + Q - b) % Q }
+func modqMul(a, b uint32) uint32 { return (a * b) % Q }
+
+func modqPow(a, e uint32) uint32 {
+	r := uint32(1)
+	for i := 31; i >= 0; i-- {
+		r = modqMul(r, r)
+		// Constant time with respect to a, as e is always public.
+		if (e>>i)&1 == 1 {
+			r = modqMul(r, a)
+		}
+	}
+	return r
+}
+
+func ntt(a []uint32, logn uint) {
+	n := 1 << logn
+	z := nttByLogn[logn].zetas
+	k := 0
+	for l := n >> 1; l >= 1; l >>= 1 {
+		for start := 0; start < n; start += 2 * l {
+			k++
+			for j := start; j < start+l; j++ {
+				t := modqMul(z[k], a[j+l])
+				a[j+l] = modqSub(a[j], t)
+				a[j] = modqAdd(a[j], t)
+			}
+		}
+	}
+}
+
+func invNTT(a []uint32, logn uint) {
+	n := 1 << logn
+	tab := &nttByLogn[logn]
+	for l := 1; l < n; l <<= 1 {
+		k := n / (2 * l)
+		for start := 0; start < n; start, k = start+2*l, k+1 {
+			for j := start; j < start+l; j++ {
+				t := a[j]
+				a[j] = modqAdd(t, a[j+l])
+				a[j+l] = modqMul(tab.invZetas[k], modqSub(t, a[j+l]))
+			}
+		}
+	}
+	for i := range a {
+		a[i] = modqMul(a[i], tab.invN)
+	}
+}
+
+// modqOf returns the coefficients of a reduced modulo q.
+func modqOf(a []int16) []uint32 {
+	r := make([]uint32, len(a))
+	for i := range a {
+		r[i] = uint32(int32(a[i])+Q) % Q
+	}
+	return r
+}
+
+// modqCenter returns the representative of a in [-q/2, q/2].
+func modqCenter(a uint32) int32 {
+	x := int32(a)
+	return x - Q&((Q/2-x)>>31)
+}
+
+// modqDivNTT sets a to a/b in NTT representation. It returns false if b is
+// not invertible.
+func modqDivNTT(a, b []uint32) bool {
+	ok := true
+	for i := range a {
+		if b[i] == 0 {
+			ok = false
+		}
+		a[i] = modqMul(a[i], modqPow(b[i], Q-2))
+	}
+	return ok
+}
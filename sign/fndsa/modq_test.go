@@ -0,0 +1,47 @@
+package fndsa
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestNTT(t *testing.T) {
+	// 11 generates Z_q^*, as q-1 = 2^12*3.
+	if modqPow(11, (Q-1)/2) == 1 || modqPow(11, (Q-1)/3) == 1 {
+		t.Fatal("11 is not a generator")
+	}
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for logn := uint(1); logn <= 10; logn++ {
+		n := 1 << logn
+		a, b := make([]uint32, n), make([]uint32, n)
+		for i := range a {
+			a[i], b[i] = r.Uint32N(Q), r.Uint32N(Q)
+		}
+
+		// Negacyclic product computed in coefficient representation.
+		want := make([]uint32, n)
+		for i := range n {
+			for j := range n {
+				p := modqMul(a[i], b[j])
+				if i+j < n {
+					want[i+j] = modqAdd(want[i+j], p)
+				} else {
+					want[i+j-n] = modqSub(want[i+j-n], p)
+				}
+			}
+		}
+
+		ntt(a, logn)
+		ntt(b, logn)
+		for i := range a {
+			a[i] = modqMul(a[i], b[i])
+		}
+		invNTT(a, logn)
+		for i := range a {
+			if a[i] != want[i] {
+				t.Fatalf("logn=%v: got[%v] = %v, want %v", logn, i, a[i], want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package fndsa
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// cdtFG is the cumulative distribution table of the coefficients of f and g
+// for n = 1024, which follow a discrete Gaussian distribution of standard
+// deviation 1.17*sqrt(q/2048): cdtFG[i] is 2^64 times the probability of a
+// coefficient to be larger than i in absolute value. For n = 512, the
+// coefficients are the sum of two such samples. See TestCDT.
+var cdtFG = [...]uint64{
+	0xDC5D904FA41AA94F, 0x994E26F3A1A89CE9, 0x617026F7B2D799EC,
+	0x383B05618C3F6DB7, 0x1D51E345D1501AB2, 0x0DC29C83A037E08E,
+	0x05CB7C01C7F81F70, 0x022F434D7A8E0830, 0x00BC617E6CF2CD82,
+	0x00389BD417AB2436, 0x000F2844B9A2943E, 0x00039CD2C2FBE255,
+	0x0000C40BE5BD75F4, 0x000024F00C7ED8A0, 0x0000062EA8F5F077,
+	0x000000EB321E3BB6, 0x0000001F0589077F, 0x00000003A15E7330,
+	0x000000006078A3B8, 0x0000000008E15D42, 0x0000000000B989A3,
+	0x00000000000D6C4A, 0x000000000000DC57, 0x0000000000000C85,
+	0x00000000000000A1, 0x0000000000000007,
+}
+
+// keyGen deterministically generates the polynomials f, g, F and G of a
+// private key, see the Falcon specification, Algorithm 5. The generation
+// of f and g is constant-time, but solving the NTRU equation is done with
+// math/big, which is not.
+func (p *params) keyGen(seed []byte) (f, g, F []int16, h []uint16) {
+	rng := newPRNG(seed)
+	lim := int16(1)<<(p.fgBits-1) - 1
+	for {
+		f, g = p.samplePoly(rng), p.samplePoly(rng)
+		if !inRange(f, lim) || !inRange(g, lim) || !p.checkNorm(f, g) {
+			continue
+		}
+
+		var ok bool
+		if h, ok = p.publicKey(f, g); !ok {
+			continue
+		}
+
+		if F, ok = ntruSolve(f, g, p.logn); ok {
+			return f, g, F, h
+		}
+	}
+}
+
+// publicKey returns h = g/f mod q, or false if f is not invertible.
+func (p *params) publicKey(f, g []int16) ([]uint16, bool) {
+	fq, gq := modqOf(f), modqOf(g)
+	ntt(fq, p.logn)
+	ntt(gq, p.logn)
+	ok := modqDivNTT(gq, fq)
+	invNTT(gq, p.logn)
+
+	h := make([]uint16, len(gq))
+	for i := range gq {
+		h[i] = uint16(gq[i])
+	}
+	return h, ok
+}
+
+// completeBasis returns G = g*F/f mod q, which satisfies f*G - g*F = q,
+// or false if f is not invertible.
+func (p *params) completeBasis(f, g, F []int16) ([]int16, bool) {
+	fq, gq, Fq := modqOf(f), modqOf(g), modqOf(F)
+	ntt(fq, p.logn)
+	ntt(gq, p.logn)
+	ntt(Fq, p.logn)
+	for i := range gq {
+		gq[i] = modqMul(gq[i], Fq[i])
+	}
+	ok := modqDivNTT(gq, fq)
+	invNTT(gq, p.logn)
+
+	G := make([]int16, len(gq))
+	for i := range gq {
+		G[i] = int16(modqCenter(gq[i]))
+	}
+	return G, ok
+}
+
+func inRange(a []int16, lim int16) bool {
+	ok := true
+	for _, ai := range a {
+		ok = ok && -lim <= ai && ai <= lim
+	}
+	return ok
+}
+
+func (p *params) samplePoly(rng *prng) []int16 {
+	f := make([]int16, p.n())
+	for i := range f {
+		for range 1024 / len(f) {
+			// Count the entries larger than a random value, then apply
+			// a random sign, in constant time.
+			v := rng.u64()
+			s := int16(rng.u8() & 1)
+			var z int16
+			for _, c := range cdtFG {
+				_, b := bits.Sub64(v, c, 0)
+				z += int16(b)
+			}
+			f[i] += (z ^ -s) + s
+		}
+	}
+	return f
+}
+
+// checkNorm returns true if the Gram-Schmidt norm of the basis is at most
+// 1.17*sqrt(q), see the Falcon specification, Section 3.8.2.
+func (p *params) checkNorm(f, g []int16) bool {
+	bound := fprConst(1.17 * 1.17 * Q)
+
+	// Squared norm of (g, -f).
+	var sqn int64
+	for i := range f {
+		sqn += int64(f[i])*int64(f[i]) + int64(g[i])*int64(g[i])
+	}
+	if fprOf(sqn).lt(bound) == 0 {
+		return false
+	}
+
+	// Squared norm of (q*adj(f), q*adj(g))/(f*adj(f) + g*adj(g)), computed
+	// in FFT representation, where the squared norm of a polynomial is 2/n
+	// times the sum of the squared absolute values of its n/2 evaluations.
+	n := p.n()
+	fa, ga, d := make([]fpr, n), make([]fpr, n), make([]fpr, n)
+	for i := range fa {
+		fa[i], ga[i] = fprOf(int64(f[i])), fprOf(int64(g[i]))
+	}
+	fft(fa, p.logn)
+	fft(ga, p.logn)
+	polyInvNormFFT(d, fa, ga)
+	sum := fprZero
+	for i := range n / 2 {
+		sum = sum.add(d[i])
+	}
+	sqnFG := sum.mul(fprConst(2 * Q * Q)).mul(fprOf(int64(n)).inv())
+
+	return sqnFG.lt(bound) == 1
+}
+
+// bigPoly is a polynomial of Z[x]/(x^n+1).
+type bigPoly []*big.Int
+
+func newBigPoly(n int) bigPoly {
+	a := make(bigPoly, n)
+	for i := range a {
+		a[i] = new(big.Int)
+	}
+	return a
+}
+
+func bigPolyOf(a []int16) bigPoly {
+	r := make(bigPoly, len(a))
+	for i := range a {
+		r[i] = big.NewInt(int64(a[i]))
+	}
+	return r
+}
+
+func (a bigPoly) maxBits() int {
+	m := 0
+	for _, ai := range a {
+		m = max(m, ai.BitLen())
+	}
+	return m
+}
+
+// mul returns the product a*b.
+func (a bigPoly) mul(b bigPoly) bigPoly {
+	n := len(a)
+	if a.maxBits()+b.maxBits()+bits.Len(uint(n)) < 62 {
+		return a.mulSmall(b)
+	}
+
+	r := newBigPoly(n)
+	var t big.Int
+	for i := range a {
+		for j := range b {
+			t.Mul(a[i], b[j])
+			if k := i + j; k < n {
+				r[k].Add(r[k], &t)
+			} else {
+				r[k-n].Sub(r[k-n], &t)
+			}
+		}
+	}
+	return r
+}
+
+// mulSmall returns the product a*b when it does not overflow an int64.
+func (a bigPoly) mulSmall(b bigPoly) bigPoly {
+	n := len(a)
+	x, y := make([]int64, n), make([]int64, n)
+	for i := range a {
+		x[i], y[i] = a[i].Int64(), b[i].Int64()
+	}
+	z := make([]int64, n)
+	for i := range x {
+		for j := range y {
+			if k := i + j; k < n {
+				z[k] += x[i] * y[j]
+			} else {
+				z[k-n] -= x[i] * y[j]
+			}
+		}
+	}
+	r := make(bigPoly, n)
+	for i := range z {
+		r[i] = big.NewInt(z[i])
+	}
+	return r
+}
+
+// fieldNorm returns the polynomial N(a) of degree n/2 such that
+// N(a)(x^2) = a(x)*a(-x).
+func (a bigPoly) fieldNorm() bigPoly {
+	hn := len(a) / 2
+	ae, ao := make(bigPoly, hn), make(bigPoly, hn)
+	for i := range hn {
+		ae[i], ao[i] = a[2*i], a[2*i+1]
+	}
+	r := ae.mul(ae)
+	ao = ao.mul(ao)
+	r[0].Add(r[0], ao[hn-1])
+	for i := range hn - 1 {
+		r[i+1].Sub(r[i+1], ao[i])
+	}
+	return r
+}
+
+// lift returns a(x^2).
+func (a bigPoly) lift() bigPoly {
+	r := newBigPoly(2 * len(a))
+	for i := range a {
+		r[2*i].Set(a[i])
+	}
+	return r
+}
+
+// conj returns a(-x).
+func (a bigPoly) conj() bigPoly {
+	r := make(bigPoly, len(a))
+	for i := range a {
+		r[i] = new(big.Int).Set(a[i])
+		if i%2 == 1 {
+			r[i].Neg(r[i])
+		}
+	}
+	return r
+}
+
+// toFFT returns the FFT representation of a/2^shift, where the coefficients
+// of a have at most shift+53 bits.
+func (a bigPoly) toFFT(shift int, logn uint) []fpr {
+	r := make([]fpr, len(a))
+	var t big.Int
+	for i := range a {
+		r[i] = fprOf(t.Rsh(a[i], uint(shift)).Int64())
+	}
+	fft(r, logn)
+	return r
+}
+
+// ntruSolve returns F such that f*G - g*F = q, for some G, see the Falcon
+// specification, Algorithm 6. It returns false if there is no solution
+// or if the coefficients of F or G are not in [-127, 127].
+func ntruSolve(f, g []int16, logn uint) (F []int16, ok bool) {
+	bF, bG, ok := ntruSolveBig(bigPolyOf(f), bigPolyOf(g), logn)
+	if !ok {
+		return nil, false
+	}
+
+	lim := big.NewInt(127)
+	F = make([]int16, len(f))
+	for i := range F {
+		if bF[i].CmpAbs(lim) > 0 || bG[i].CmpAbs(lim) > 0 {
+			return nil, false
+		}
+		F[i] = int16(bF[i].Int64())
+	}
+	return F, true
+}
+
+func ntruSolveBig(f, g bigPoly, logn uint) (F, G bigPoly, ok bool) {
+	if logn == 0 {
+		var u, v big.Int
+		d := new(big.Int).GCD(&u, &v, f[0], g[0])
+		if d.Cmp(big.NewInt(1)) != 0 {
+			return nil, nil, false
+		}
+		q := big.NewInt(Q)
+		return bigPoly{v.Mul(&v, q).Neg(&v)}, bigPoly{u.Mul(&u, q)}, true
+	}
+
+	Fp, Gp, ok := ntruSolveBig(f.fieldNorm(), g.fieldNorm(), logn-1)
+	if !ok {
+		return nil, nil, false
+	}
+
+	F = Fp.lift().mul(g.conj())
+	G = Gp.lift().mul(f.conj())
+	reduce(f, g, F, G, logn)
+	return F, G, true
+}
+
+// reduce performs a Babai reduction of (F, G) with respect to (f, g). As
+// the coefficients may be larger than what floating-point values hold, it
+// works with their top 53 bits, removing up to 25 bits of (F, G) per
+// iteration, until the norm of (F, G) no longer decreases. Rounding errors
+// could otherwise make it alternate between two values of (F, G).
+func reduce(f, g, F, G bigPoly, logn uint) {
+	size := max(53, f.maxBits(), g.maxBits())
+	fa := f.toFFT(size-53, logn)
+	ga := g.toFFT(size-53, logn)
+	den := make([]fpr, len(f))
+	polyInvNormFFT(den, fa, ga)
+
+	k := make(bigPoly, len(f))
+	norm := sqrNorm(F, G)
+	for {
+		sizeFG := max(53, F.maxBits(), G.maxBits())
+		if sizeFG < size {
+			return
+		}
+
+		// k = round((F*adj(f) + G*adj(g))/(f*adj(f) + g*adj(g))/2^shift)
+		d := min(sizeFG-size, 25)
+		shift := uint(sizeFG - size - d)
+		Fa := F.toFFT(sizeFG-53, logn)
+		Ga := G.toFFT(sizeFG-53, logn)
+		polyMulAdjFFT(Fa, fa)
+		polyMulAdjFFT(Ga, ga)
+		polyAdd(Fa, Ga)
+		polyMulFFT(Fa, den)
+		ifft(Fa, logn)
+		polyMulConst(Fa, fprScaled(1, d))
+
+		isZero := true
+		for i := range Fa {
+			k[i] = big.NewInt(Fa[i].rint())
+			isZero = isZero && k[i].Sign() == 0
+		}
+		if isZero {
+			return
+		}
+
+		fk, gk := f.mul(k), g.mul(k)
+		for i := range F {
+			fk[i].Sub(F[i], fk[i].Lsh(fk[i], shift))
+			gk[i].Sub(G[i], gk[i].Lsh(gk[i], shift))
+		}
+
+		newNorm := sqrNorm(fk, gk)
+		if newNorm.Cmp(norm) >= 0 {
+			return
+		}
+		norm = newNorm
+		for i := range F {
+			F[i].Set(fk[i])
+			G[i].Set(gk[i])
+		}
+	}
+}
+
+// sqrNorm returns the squared norm of (a, b).
+func sqrNorm(a, b bigPoly) *big.Int {
+	r, t := new(big.Int), new(big.Int)
+	for i := range a {
+		r.Add(r, t.Mul(a[i], a[i]))
+		r.Add(r, t.Mul(b[i], b[i]))
+	}
+	return r
+}
@@ -0,0 +1,89 @@
+package fndsa
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNTRUSolve(t *testing.T) {
+	for i := range supportedParams {
+		p := &supportedParams[i]
+		t.Run(p.name, func(t *testing.T) {
+			f, g, bigF, h := p.keyGen([]byte{byte(i)})
+			key, ok := p.newPrivateKey(f, g, bigF)
+			if !ok {
+				t.Fatal("invalid key")
+			}
+
+			// f*G - g*F = q.
+			fG := bigPolyOf(f).mul(bigPolyOf(key.bigG))
+			gF := bigPolyOf(g).mul(bigPolyOf(bigF))
+			for j := range fG {
+				want := int64(0)
+				if j == 0 {
+					want = Q
+				}
+				if d := new(big.Int).Sub(fG[j], gF[j]); d.Int64() != want {
+					t.Fatalf("(fG - gF)[%v] = %v, want %v", j, d, want)
+				}
+			}
+
+			// h*f = g mod q.
+			hf := make([]uint32, len(h))
+			for j := range h {
+				hf[j] = uint32(h[j])
+			}
+			fq, gq := modqOf(f), modqOf(g)
+			ntt(hf, p.logn)
+			ntt(fq, p.logn)
+			for j := range hf {
+				hf[j] = modqMul(hf[j], fq[j])
+			}
+			invNTT(hf, p.logn)
+			for j := range hf {
+				if hf[j] != gq[j] {
+					t.Fatalf("(hf)[%v] = %v, want %v", j, hf[j], gq[j])
+				}
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	p := Falcon512.params()
+	s2 := make([]int16, p.n())
+	for i := range s2 {
+		s2[i] = int16((i*i)%4095 - 2047)
+	}
+	out := make([]byte, 4*p.n())
+	if !compress(out, s2) {
+		t.Fatal("compress failed")
+	}
+	got := make([]int16, p.n())
+	if !decompress(got, out) {
+		t.Fatal("decompress failed")
+	}
+	for i := range s2 {
+		if got[i] != s2[i] {
+			t.Fatalf("got[%v] = %v, want %v", i, got[i], s2[i])
+		}
+	}
+
+	// Out of range values, and values not fitting the output, are rejected.
+	s2[0] = 2048
+	if compress(out, s2) {
+		t.Fatal("compress should fail")
+	}
+	s2[0] = 0
+	if compress(out[:p.n()], s2) {
+		t.Fatal("compress should fail")
+	}
+
+	// Negative zero is not canonical.
+	if decompress(got[:1], []byte{0x80, 0x80}) {
+		t.Fatal("decompress should fail")
+	}
+	if !decompress(got[:1], []byte{0x00, 0x80}) {
+		t.Fatal("decompress failed")
+	}
+}
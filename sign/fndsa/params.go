@@ -0,0 +1,103 @@
+package fndsa
+
+import "strings"
+
+// [ID] identifies the supported parameter sets of FN-DSA.
+// Note that the zero value is not a valid identifier.
+type ID byte
+
+const (
+	Falcon512  ID = iota + 1 // Falcon-512 (FN-DSA-512)
+	Falcon1024               // Falcon-1024 (FN-DSA-1024)
+	_MaxParams
+)
+
+const (
+	// Q is the modulus of the NTRU ring.
+	Q = 12289
+
+	// NonceSize is the size of the random nonce of a signature.
+	NonceSize = 40
+
+	// SeedSize is the size of the seeds used by [NewKeyFromSeed].
+	SeedSize = 48
+
+	// Standard deviation of the base sampler.
+	sigma0 = 1.8205
+)
+
+// [IDByName] returns the [ID] that corresponds to the given name,
+// or an error if no parameter set was found.
+// See [ID] documentation for the specific names of each parameter set.
+// Names are case insensitive.
+//
+// Example:
+//
+//	IDByName("Falcon-512") // returns (Falcon512, nil)
+func IDByName(name string) (ID, error) {
+	v := strings.ToLower(name)
+	for i := range supportedParams {
+		if strings.ToLower(supportedParams[i].name) == v {
+			return supportedParams[i].ID, nil
+		}
+	}
+
+	return ID(0), ErrParam
+}
+
+// IsValid returns true if the parameter set is supported.
+func (id ID) IsValid() bool { return 0 < id && id < _MaxParams }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return supportedParams[id-1].name
+}
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+	return &supportedParams[id-1]
+}
+
+// params contains all the relevant constants of a parameter set.
+type params struct {
+	name     string  // Name of the parameter set.
+	logn     uint    // Degree of the ring is n = 2^logn.
+	fgBits   uint    // Bits per coefficient of f and g in private keys.
+	sigSize  int     // Size of padded signatures.
+	bound    uint32  // Bound of the squared norm of a signature.
+	sigma    float64 // Standard deviation of signatures.
+	sigmaMin float64 // Smallest standard deviation given to SamplerZ.
+	ID               // Identifier of the parameter set.
+}
+
+// Stores all the supported (read-only) parameter sets.
+var supportedParams = [_MaxParams - 1]params{
+	{
+		ID: Falcon512, logn: 9, fgBits: 6, sigSize: 666, bound: 34034726,
+		sigma: 165.7366171829776, sigmaMin: 1.2778336969128337,
+		name: "Falcon-512",
+	},
+	{
+		ID: Falcon1024, logn: 10, fgBits: 5, sigSize: 1280, bound: 70265242,
+		sigma: 168.38857144654395, sigmaMin: 1.298280334344292,
+		name: "Falcon-1024",
+	},
+}
+
+func (p *params) n() int { return 1 << p.logn }
+
+// PublicKeySize is the size of a packed public key: a header byte and
+// 14 bits per coefficient of h.
+func (p *params) PublicKeySize() int { return 1 + 14*p.n()/8 }
+
+// PrivateKeySize is the size of a packed private key: a header byte,
+// the coefficients of f and g with fgBits each and those of F with 8 bits.
+func (p *params) PrivateKeySize() int { return 1 + (2*int(p.fgBits)+8)*p.n()/8 }
+
+// SignatureSize is the size of a padded signature: a header byte, the
+// nonce and the compressed s2 padded with zeros.
+func (p *params) SignatureSize() int { return p.sigSize }
@@ -0,0 +1,227 @@
+package fndsa
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// prng expands a seed with SHAKE256. It is the source of randomness of
+// key generation, and seeds the generator of the sampler.
+type prng struct {
+	state sha3.State
+	buf   [136]byte // The rate of SHAKE256.
+	pos   int
+}
+
+func newPRNG(seed ...[]byte) *prng {
+	p := &prng{state: sha3.NewShake256()}
+	for _, s := range seed {
+		_, _ = p.state.Write(s)
+	}
+	p.pos = len(p.buf)
+	return p
+}
+
+func (p *prng) next(n int) []byte {
+	if p.pos+n > len(p.buf) {
+		rem := copy(p.buf[:], p.buf[p.pos:])
+		_, _ = p.state.Read(p.buf[rem:])
+		p.pos = 0
+	}
+	b := p.buf[p.pos : p.pos+n]
+	p.pos += n
+	return b
+}
+
+func (p *prng) u8() uint32  { return uint32(p.next(1)[0]) }
+func (p *prng) u64() uint64 { return binary.LittleEndian.Uint64(p.next(8)) }
+
+// chachaPRNG is the source of randomness of the sampler. It is the
+// generator of the reference implementation, which runs ChaCha20 on a
+// 56-byte state, and outputs eight interleaved blocks at a time.
+type chachaPRNG struct {
+	state [56]byte // 48 bytes of key and nonce, and a 64-bit counter.
+	buf   [512]byte
+	pos   int
+}
+
+// chachaConstants are the first four words of a ChaCha20 block.
+var chachaConstants = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// newChachaPRNG seeds a chachaPRNG with 56 bytes of src.
+func newChachaPRNG(src *prng) *chachaPRNG {
+	p := new(chachaPRNG)
+	copy(p.state[:], src.next(len(p.state)))
+	p.refill()
+	return p
+}
+
+func (p *chachaPRNG) refill() {
+	var key [12]uint32
+	for i := range key {
+		key[i] = binary.LittleEndian.Uint32(p.state[4*i:])
+	}
+	cc := binary.LittleEndian.Uint64(p.state[48:])
+
+	for u := range 8 {
+		var x [16]uint32
+		copy(x[:4], chachaConstants[:])
+		copy(x[4:], key[:])
+		x[14] ^= uint32(cc)
+		x[15] ^= uint32(cc >> 32)
+		init := x
+
+		for range 10 {
+			quarterRound(&x, 0, 4, 8, 12)
+			quarterRound(&x, 1, 5, 9, 13)
+			quarterRound(&x, 2, 6, 10, 14)
+			quarterRound(&x, 3, 7, 11, 15)
+			quarterRound(&x, 0, 5, 10, 15)
+			quarterRound(&x, 1, 6, 11, 12)
+			quarterRound(&x, 2, 7, 8, 13)
+			quarterRound(&x, 3, 4, 9, 14)
+		}
+
+		// The words of the eight blocks are interleaved.
+		for v := range x {
+			binary.LittleEndian.PutUint32(p.buf[4*u+32*v:], x[v]+init[v])
+		}
+		cc++
+	}
+
+	binary.LittleEndian.PutUint64(p.state[48:], cc)
+	p.pos = 0
+}
+
+func quarterRound(x *[16]uint32, a, b, c, d int) {
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+}
+
+func (p *chachaPRNG) u8() uint32 {
+	v := uint32(p.buf[p.pos])
+	p.pos++
+	if p.pos == len(p.buf) {
+		p.refill()
+	}
+	return v
+}
+
+// u64 reads eight bytes, discarding the end of the buffer if they don't
+// fit, as the reference implementation does.
+func (p *chachaPRNG) u64() uint64 {
+	if p.pos >= len(p.buf)-9 {
+		p.refill()
+	}
+	v := binary.LittleEndian.Uint64(p.buf[p.pos:])
+	p.pos += 8
+	return v
+}
+
+// rcdt is the reverse cumulative distribution table of the half-Gaussian
+// distribution of standard deviation sigma0: rcdt[i] is 2^72 times the
+// probability of a sample to be larger than i, given as three 24-bit limbs
+// from the most significant. See the Falcon specification, Table 3.1.
+var rcdt = [18][3]uint32{
+	{10745844, 3068844, 3741698}, // 3024686241123004913666
+	{5559083, 1580863, 8248194},  // 1564742784480091954050
+	{2260429, 13669192, 2736639}, // 636254429462080897535
+	{708981, 4421575, 10046180},  // 199560484645026482916
+	{169348, 7122675, 4136815},   // 47667343854657281903
+	{30538, 13063405, 7650655},   // 8595902006365044063
+	{4132, 14505003, 7826148},    // 1163297957344668388
+	{417, 16768101, 11363290},    // 117656387352093658
+	{31, 8444042, 8086568},       // 8867391802663976
+	{1, 12844466, 265321},        // 496969357462633
+	{0, 1232676, 13644283},       // 20680885154299
+	{0, 38047, 9111839},          // 638331848991
+	{0, 870, 6138264},            // 14602316184
+	{0, 14, 12545723},            // 247426747
+	{0, 0, 3104126},              // 3104126
+	{0, 0, 28824},                // 28824
+	{0, 0, 198},                  // 198
+	{0, 0, 1},                    // 1
+}
+
+// sampler draws integers from discrete Gaussian distributions, see the
+// Falcon specification, Section 3.9.3.
+type sampler struct {
+	*chachaPRNG
+	sigmaMin fpr
+}
+
+// baseSample returns a sample of the half-Gaussian distribution of standard
+// deviation sigma0, by counting the entries of rcdt larger than a random
+// 72-bit value, in constant time.
+func (s *sampler) baseSample() int64 {
+	lo := s.u64()
+	hi := s.u8()
+	v0 := uint32(lo) & 0xFFFFFF
+	v1 := uint32(lo>>24) & 0xFFFFFF
+	v2 := uint32(lo>>48) | hi<<16
+
+	z := int64(0)
+	for i := range rcdt {
+		cc := (v0 - rcdt[i][2]) >> 31
+		cc = (v1 - rcdt[i][1] - cc) >> 31
+		cc = (v2 - rcdt[i][0] - cc) >> 31
+		z += int64(cc)
+	}
+	return z
+}
+
+// berExp returns true with probability ccs*exp(-x), for x >= 0.
+func (s *sampler) berExp(x, ccs fpr) bool {
+	// Decompose x = s*log(2) + r, with 0 <= r < log(2), so that
+	// exp(-x) = 2^(-s)*exp(-r). The value s is capped to 63.
+	si := x.mul(fprInvLog2).trunc()
+	r := x.sub(fprOf(si).mul(fprLog2))
+	sw := uint32(si)
+	sw ^= (sw ^ 63) & -((63 - sw) >> 31)
+
+	z := ((expmP63(r, ccs) << 1) - 1) >> sw
+
+	// Compare z with a random 64-bit value, lazily byte by byte.
+	var w uint32
+	for i := 56; ; i -= 8 {
+		w = s.u8() - uint32(z>>i)&0xFF
+		if w != 0 || i == 0 {
+			break
+		}
+	}
+	return w>>31 == 1
+}
+
+// sample returns an integer following a discrete Gaussian distribution of
+// center mu and standard deviation 1/isigma, see the Falcon specification,
+// Algorithm 15.
+func (s *sampler) sample(mu, isigma fpr) int64 {
+	// Center is split into an integer part and a fractional part r in [0,1).
+	i := mu.floor()
+	r := mu.sub(fprOf(i))
+
+	dss := isigma.sqr().half()
+	ccs := isigma.mul(s.sigmaMin)
+
+	for {
+		// Sample z from a bimodal Gaussian around 0 and 1, then accept it
+		// with the probability that makes it follow the target distribution.
+		z0 := s.baseSample()
+		b := int64(s.u8() & 1)
+		z := b + ((b<<1)-1)*z0
+
+		x := fprOf(z).sub(r).sqr().mul(dss)
+		x = x.sub(fprOf(z0 * z0).mul(fprInvSqrSigma0))
+		if s.berExp(x, ccs) {
+			return i + z
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package fndsa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"golang.org/x/crypto/chacha20"
+)
+
+// tailProbs returns P(X > i) for the distribution proportional to weight
+// over the non-negative integers.
+func tailProbs(weight func(x float64) float64, size int) []float64 {
+	w := make([]float64, 64)
+	var total float64
+	for x := len(w) - 1; x >= 0; x-- {
+		w[x] = weight(float64(x))
+		total += w[x]
+	}
+
+	tail := make([]float64, size)
+	var sum float64
+	for x := len(w) - 1; x >= 0; x-- {
+		if x < size {
+			tail[x] = sum / total
+		}
+		sum += w[x]
+	}
+	return tail
+}
+
+func checkTable(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	for i := range got {
+		// Entries are integers, and those of the specification are off by
+		// a few units, hence the absolute tolerance.
+		if math.Abs(got[i]-want[i]) > 4+want[i]*1e-12 {
+			t.Fatalf("%v[%v] = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestRCDT(t *testing.T) {
+	want := tailProbs(func(x float64) float64 {
+		return math.Exp(-x * x / (2 * sigma0 * sigma0))
+	}, len(rcdt))
+
+	got := make([]float64, len(rcdt))
+	for i := range rcdt {
+		got[i] = math.Ldexp(float64(rcdt[i][0]), 48) +
+			math.Ldexp(float64(rcdt[i][1]), 24) + float64(rcdt[i][2])
+		want[i] = math.Ldexp(want[i], 72)
+	}
+	checkTable(t, "rcdt", got, want)
+}
+
+func TestCDT(t *testing.T) {
+	// The absolute value of a discrete Gaussian has twice the weight of the
+	// Gaussian on positive values.
+	sigma := 1.17 * math.Sqrt(Q/2048.0)
+	want := tailProbs(func(x float64) float64 {
+		w := math.Exp(-x * x / (2 * sigma * sigma))
+		if x > 0 {
+			w *= 2
+		}
+		return w
+	}, len(cdtFG))
+
+	got := make([]float64, len(cdtFG))
+	for i := range cdtFG {
+		got[i] = float64(cdtFG[i])
+		want[i] = math.Ldexp(want[i], 64)
+	}
+	checkTable(t, "cdtFG", got, want)
+}
+
+func TestSampler(t *testing.T) {
+	// The mean and variance of the samples must match the requested ones.
+	const samples = 1 << 15
+	s := sampler{newChachaPRNG(newPRNG([]byte("sampler"))), fprConst(1.2778336969128337)}
+	for _, tc := range []struct{ mu, sigma float64 }{
+		{0, 1.3}, {0.5, 1.5}, {-13.25, 1.8205}, {100.75, 1.7},
+	} {
+		var sum, sqr float64
+		for range samples {
+			z := float64(s.sample(fprConst(tc.mu), fprConst(1/tc.sigma)))
+			sum += z
+			sqr += (z - tc.mu) * (z - tc.mu)
+		}
+		mean := sum / samples
+		sigma := math.Sqrt(sqr / samples)
+		if math.Abs(mean-tc.mu) > 0.05 || math.Abs(sigma-tc.sigma) > 0.05 {
+			t.Fatalf("sample(%v, %v): mean %v, sigma %v",
+				tc.mu, tc.sigma, mean, sigma)
+		}
+	}
+}
+
+func TestChachaPRNG(t *testing.T) {
+	// Each refill outputs eight ChaCha20 blocks, whose key is the first 32
+	// bytes of the state, and whose counter and nonce are the next 16
+	// bytes, with the last eight XORed with the 64-bit counter.
+	p := newChachaPRNG(newPRNG([]byte("chacha")))
+	for range 2 {
+		state := p.state
+		cc := binary.LittleEndian.Uint64(state[48:]) - 8
+		for u := range 8 {
+			var nonce [12]byte
+			copy(nonce[:], state[36:48])
+			binary.LittleEndian.PutUint64(nonce[4:],
+				binary.LittleEndian.Uint64(state[40:])^(cc+uint64(u)))
+			c, err := chacha20.NewUnauthenticatedCipher(state[:32], nonce[:])
+			test.CheckNoErr(t, err, "NewUnauthenticatedCipher failed")
+			c.SetCounter(binary.LittleEndian.Uint32(state[32:]))
+			want := make([]byte, 64)
+			c.XORKeyStream(want, want)
+
+			got := make([]byte, 64)
+			for v := range 16 {
+				copy(got[4*v:4*v+4], p.buf[4*u+32*v:])
+			}
+			if !bytes.Equal(got, want) {
+				test.ReportError(t, got, want, u)
+			}
+		}
+		p.refill()
+	}
+}
@@ -0,0 +1,107 @@
+package fndsa
+
+import (
+	"crypto/rand"
+
+	"github.com/cloudflare/circl/sign"
+)
+
+// Scheme returns a generic signature interface for the parameter set.
+func (id ID) Scheme() sign.Scheme { return scheme{id.params()} }
+
+func (k PrivateKey) Scheme() sign.Scheme { return k.ID.Scheme() }
+func (k PublicKey) Scheme() sign.Scheme  { return k.ID.Scheme() }
+
+type scheme struct{ *params }
+
+func (s scheme) Name() string          { return s.name }
+func (s scheme) SeedSize() int         { return SeedSize }
+func (s scheme) SupportsContext() bool { return false }
+
+// GenerateKey is similar to [GenerateKey] function, except it always reads
+// random bytes from [rand.Reader].
+func (s scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
+	return GenerateKey(rand.Reader, s.ID)
+}
+
+// Sign returns a randomized signature of the message.
+// It returns an empty slice if the signature generation fails.
+//
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, or when
+// options sets a context or a pre-hash function, which are not supported.
+func (s scheme) Sign(
+	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
+) []byte {
+	k, ok := priv.(PrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+	checkOpts(options)
+
+	sig, err := Sign(&k, rand.Reader, message)
+	if err != nil {
+		return nil
+	}
+
+	return sig
+}
+
+// Verify returns true if the signature of the message is valid.
+//
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, or when
+// options sets a context or a pre-hash function, which are not supported.
+func (s scheme) Verify(
+	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
+) bool {
+	k, ok := pub.(PublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+	checkOpts(options)
+
+	return Verify(&k, message, signature)
+}
+
+// checkOpts panics if options sets a context or a pre-hash function.
+func checkOpts(options *sign.SignatureOpts) {
+	if options == nil {
+		return
+	}
+	if options.Context != "" {
+		panic(sign.ErrContextNotSupported)
+	}
+	if !options.PreHash.IsZero() {
+		panic(sign.ErrPreHashNotSupported)
+	}
+}
+
+// DeriveKey deterministically generates a pair of keys from a seed.
+//
+// Panics if seed is not of length [SeedSize].
+func (s scheme) DeriveKey(seed []byte) (sign.PublicKey, sign.PrivateKey) {
+	if len(seed) != SeedSize {
+		panic(sign.ErrSeedSize)
+	}
+
+	return NewKeyFromSeed(s.ID, (*[SeedSize]byte)(seed))
+}
+
+func (s scheme) UnmarshalBinaryPublicKey(b []byte) (sign.PublicKey, error) {
+	k := PublicKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (s scheme) UnmarshalBinaryPrivateKey(b []byte) (sign.PrivateKey, error) {
+	k := PrivateKey{ID: s.ID}
+	err := k.UnmarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,120 @@
+package dilithium
+
+import (
+	"crypto"
+	_ "crypto/sha256" // register SHA-224 and SHA-256
+	_ "crypto/sha512" // register the SHA-512 family
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/xof"
+	_ "golang.org/x/crypto/sha3" // register the SHA-3 family
+)
+
+// ErrDigestSize is returned if a pre-hashed digest has the wrong length for
+// the selected hash function.
+var ErrDigestSize = errors.New("wrong size for pre-hashed digest")
+
+// Last byte of the DER encoded OIDs 2.16.840.1.101.3.4.2.x of the hash
+// functions approved for HashML-DSA, see FIPS 204, Section 5.4.
+var hash2oid = [...]byte{
+	crypto.SHA256:     1,
+	crypto.SHA384:     2,
+	crypto.SHA512:     3,
+	crypto.SHA224:     4,
+	crypto.SHA512_224: 5,
+	crypto.SHA512_256: 6,
+	crypto.SHA3_224:   7,
+	crypto.SHA3_256:   8,
+	crypto.SHA3_384:   9,
+	crypto.SHA3_512:   10,
+}
+
+// PreHasher computes the digest of a message for a pre-hash signature.
+type PreHasher struct {
+	w    io.Writer
+	size int
+	oid  byte
+}
+
+// NewPreHasher returns a PreHasher for the function selected by ph.
+//
+// Returns sign.ErrPreHashNotSupported if ph is zero or not approved.
+func NewPreHasher(ph sign.PreHash) (*PreHasher, error) {
+	size, oid, err := preHashParams(ph)
+	if err != nil {
+		return nil, err
+	}
+	if ph.Xof != 0 {
+		return &PreHasher{ph.Xof.New(), size, oid}, nil
+	}
+	return &PreHasher{ph.Hash.New(), size, oid}, nil
+}
+
+// DigestSize returns the size of the digest of the function selected by ph.
+//
+// Returns sign.ErrPreHashNotSupported if ph is zero or not approved.
+func DigestSize(ph sign.PreHash) (int, error) {
+	size, _, err := preHashParams(ph)
+	return size, err
+}
+
+func preHashParams(ph sign.PreHash) (size int, oid byte, err error) {
+	switch {
+	case ph.Hash != 0 && ph.Xof != 0:
+	case ph.Xof == xof.SHAKE128:
+		return 32, 11, nil
+	case ph.Xof == xof.SHAKE256:
+		return 64, 12, nil
+	case ph.Xof != 0:
+	case int(ph.Hash) < len(hash2oid) && hash2oid[ph.Hash] != 0 &&
+		ph.Hash.Available():
+		return ph.Hash.Size(), hash2oid[ph.Hash], nil
+	}
+	return 0, 0, sign.ErrPreHashNotSupported
+}
+
+// Write absorbs more of the message.
+func (p *PreHasher) Write(b []byte) (int, error) { return p.w.Write(b) }
+
+// Sum returns the digest of the message written so far.
+func (p *PreHasher) Sum() []byte {
+	switch w := p.w.(type) {
+	case xof.XOF:
+		ret := make([]byte, p.size)
+		_, _ = w.Clone().Read(ret)
+		return ret
+	case hash.Hash:
+		return w.Sum(nil)
+	default:
+		panic("unreachable")
+	}
+}
+
+// PreHashMessage returns M' = 1 ‖ |ctx| ‖ ctx ‖ OID ‖ digest, which is the
+// message that is signed by HashML-DSA, see FIPS 204, Algorithm 4.
+//
+// Returns an error if ph is not supported, if ctx is longer than 255 bytes
+// or if digest is not of the right size.
+func PreHashMessage(ph sign.PreHash, digest, ctx []byte) ([]byte, error) {
+	size, oid, err := preHashParams(ph)
+	if err != nil {
+		return nil, err
+	}
+	if len(digest) != size {
+		return nil, ErrDigestSize
+	}
+	if len(ctx) > 255 {
+		return nil, sign.ErrContextTooLong
+	}
+
+	ret := make([]byte, 0, 2+len(ctx)+11+len(digest))
+	ret = append(ret, 1, byte(len(ctx)))
+	ret = append(ret, ctx...)
+	ret = append(ret,
+		0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, oid,
+	)
+	return append(ret, digest...), nil
+}
@@ -0,0 +1,83 @@
+// Package stateful provides the bookkeeping of one-time indices shared by
+// stateful hash-based signature schemes.
+package stateful
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrExhausted is the error used when no one-time index is left.
+var ErrExhausted = errors.New("one-time signatures exhausted")
+
+// Counter hands out one-time indices in increasing order, so that an index
+// is never handed out twice. It is safe for concurrent use.
+type Counter struct {
+	mu    sync.Mutex
+	next  uint64
+	limit uint64
+}
+
+// Init sets the next index to hand out and the number of indices.
+func (c *Counter) Init(next, limit uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next, c.limit = next, limit
+}
+
+// Next returns the next index to hand out.
+func (c *Counter) Next() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next
+}
+
+// Remaining returns the number of indices left.
+func (c *Counter) Remaining() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit - c.next
+}
+
+// Reserve hands out the next n indices. It returns ErrExhausted if fewer
+// than n indices are left, in which case no index is handed out.
+func (c *Counter) Reserve(n uint64) (*Range, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n == 0 || n > c.limit-c.next {
+		return nil, ErrExhausted
+	}
+
+	r := &Range{next: c.next, end: c.next + n}
+	c.next += n
+	return r, nil
+}
+
+// Range is a set of consecutive indices reserved from a Counter.
+// It is safe for concurrent use.
+type Range struct {
+	mu   sync.Mutex
+	next uint64
+	end  uint64
+}
+
+// Take returns the next index of the range, or ErrExhausted if all of them
+// were already taken.
+func (r *Range) Take() (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next == r.end {
+		return 0, ErrExhausted
+	}
+
+	i := r.next
+	r.next++
+	return i, nil
+}
+
+// Remaining returns the number of indices left in the range.
+func (r *Range) Remaining() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.end - r.next
+}
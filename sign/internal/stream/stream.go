@@ -0,0 +1,40 @@
+// Package stream provides adapters for implementing sign.StreamSigner and
+// sign.StreamVerifier.
+package stream
+
+import (
+	"bytes"
+	"io"
+)
+
+// Signer implements sign.StreamSigner by writing the message to Writer and
+// calling SignFunc on Sign.
+type Signer struct {
+	io.Writer
+	SignFunc func() []byte
+}
+
+func (s *Signer) Sign() []byte { return s.SignFunc() }
+
+// Verifier implements sign.StreamVerifier by writing the message to Writer
+// and calling VerifyFunc on Verify.
+type Verifier struct {
+	io.Writer
+	VerifyFunc func(signature []byte) bool
+}
+
+func (v *Verifier) Verify(signature []byte) bool { return v.VerifyFunc(signature) }
+
+// NewBufferedSigner returns a Signer that buffers the message and passes it
+// to sign, for schemes that process the message more than once.
+func NewBufferedSigner(sign func(msg []byte) []byte) *Signer {
+	buf := new(bytes.Buffer)
+	return &Signer{buf, func() []byte { return sign(buf.Bytes()) }}
+}
+
+// NewBufferedVerifier returns a Verifier that buffers the message and passes
+// it to verify, for schemes that process the message more than once.
+func NewBufferedVerifier(verify func(msg, signature []byte) bool) *Verifier {
+	buf := new(bytes.Buffer)
+	return &Verifier{buf, func(sig []byte) bool { return verify(buf.Bytes(), sig) }}
+}
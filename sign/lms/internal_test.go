@@ -0,0 +1,95 @@
+package lms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+)
+
+func hexStr(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	test.CheckNoErr(t, err, "bad hex")
+	return b
+}
+
+// Private keys of RFC 8554 -- Appendix F, Test Case 2.
+func TestRFC8554(t *testing.T) {
+	for _, v := range []struct {
+		level          Level
+		seed, id, root string
+	}{
+		{
+			Level{LMS_SHA256_M32_H10, LMOTS_SHA256_N32_W4},
+			"558b8966c48ae9cb898b423c83443aae014a72f1b1ab5cc85cf1d892903b5439",
+			"d08fabd4a2091ff0a8cb4ed834e74534",
+			"32a58885cd9ba0431235466bff9651c6c92124404d45fa53cf161c28f1ad5a8e",
+		},
+		{
+			Level{LMS_SHA256_M32_H5, LMOTS_SHA256_N32_W8},
+			"a1c4696e2608035a886100d05cd99945eb3370731884a8235e2fb3d4d71f2547",
+			"215f83b7ccb9acbcd08db97b0d04dc2b",
+			"a1cd035833e0e90059603f26e07ad2aad152338e7a5e5984bcd5f7bb4eba40b7",
+		},
+	} {
+		id := (*identifier)(hexStr(t, v.id))
+		tr := newTree(v.level, hexStr(t, v.seed), id)
+		want := hexStr(t, v.root)
+		if !bytes.Equal(tr.root(), want) {
+			test.ReportError(t, tr.root(), want)
+		}
+
+		msg := []byte("The enumeration in the Constitution, of certain rights, shall not be construed to deny or disparage others retained by the people.\n")
+		for _, q := range []uint32{0, 4, 10, 31} {
+			sig := tr.sign(q, msg)
+			test.CheckOk(verifyLMS(tr.publicKey(), msg, sig), "verifyLMS failed", t)
+		}
+	}
+}
+
+func TestCachedHeight(t *testing.T) {
+	defer func(h int) { maxCachedHeight = h }(maxCachedHeight)
+
+	level := Level{LMS_SHA256_M32_H10, LMOTS_SHA256_N32_W2}
+	seed := make([]byte, 32)
+	var id identifier
+
+	maxCachedHeight = 10
+	want := newTree(level, seed, &id)
+	for _, h := range []int{0, 3, 7} {
+		maxCachedHeight = h
+		tr := newTree(level, seed, &id)
+		test.CheckOk(tr.cut == 10-h, "wrong cut", t)
+		for _, q := range []uint32{0, 1, 2, 500, 1023} {
+			got := tr.sign(q, nil)
+			if !bytes.Equal(got, want.sign(q, nil)) {
+				test.ReportError(t, got, want.sign(q, nil), h, q)
+			}
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	// See RFC 8554 -- Table 1 and NIST SP 800-208 -- Section 4.
+	want := map[OTSType][2]int{
+		LMOTS_SHA256_N32_W1: {265, 7}, LMOTS_SHA256_N32_W2: {133, 6},
+		LMOTS_SHA256_N32_W4: {67, 4}, LMOTS_SHA256_N32_W8: {34, 0},
+		LMOTS_SHA256_N24_W1: {200, 8}, LMOTS_SHA256_N24_W2: {101, 6},
+		LMOTS_SHA256_N24_W4: {51, 4}, LMOTS_SHA256_N24_W8: {26, 0},
+	}
+	for typ, v := range want {
+		p := typ.params()
+		got := [2]int{p.p, p.ls}
+		if got != v {
+			test.ReportError(t, got, v, typ)
+		}
+		p = (typ + 8).params()
+		if got := [2]int{p.p, p.ls}; got != v {
+			test.ReportError(t, got, v, typ+8)
+		}
+	}
+
+	test.CheckOk(LMS_SHAKE_M24_H25.params().h == 25, "wrong height", t)
+	test.CheckOk(LMS_SHA256_M24_H5.params().n == 24, "wrong size", t)
+}
@@ -0,0 +1,305 @@
+package lms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/cloudflare/circl/sign/internal/stateful"
+)
+
+// ErrExhausted is the error returned when no one-time key is left.
+var ErrExhausted = stateful.ErrExhausted
+
+// [PublicKey] stores an HSS public key.
+// It implements the [crypto.PublicKey] interface.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PublicKey struct {
+	levels uint32
+	lms    []byte // Public key of the top-level LMS tree.
+}
+
+// MarshalBinary returns u32str(L) || pub[0], as in RFC 8554 -- Section 6.1.
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	return append(binary.BigEndian.AppendUint32(nil, k.levels), k.lms...), nil
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return errors.New("sign/lms: invalid public key")
+	}
+
+	levels := binary.BigEndian.Uint32(b)
+	if _, _, _, ok := parseLMSPublicKey(b[4:]); !ok || levels == 0 || levels > MaxLevels {
+		return errors.New("sign/lms: invalid public key")
+	}
+
+	k.levels = levels
+	k.lms = append([]byte{}, b[4:]...)
+	return nil
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.levels == other.levels && bytes.Equal(k.lms, other.lms)
+}
+
+// verify implements the algorithm of RFC 8554 -- Section 6.3.
+func (k *PublicKey) verify(message, sig []byte) bool {
+	if len(sig) < 4 || binary.BigEndian.Uint32(sig) != k.levels-1 {
+		return false
+	}
+
+	sig = sig[4:]
+	key := k.lms
+	for i := uint32(0); i < k.levels-1; i++ {
+		n := lmsSignatureLength(sig)
+		if n == 0 || len(sig) < n+8 {
+			return false
+		}
+
+		level := Level{
+			LMSType(binary.BigEndian.Uint32(sig[n:])),
+			OTSType(binary.BigEndian.Uint32(sig[n+4:])),
+		}
+		if !level.IsValid() {
+			return false
+		}
+
+		m := n + 8 + idSize + level.LMS.params().n
+		if len(sig) < m || !verifyLMS(key, sig[n:m], sig[:n]) {
+			return false
+		}
+
+		key, sig = sig[n:m], sig[m:]
+	}
+
+	return verifyLMS(key, message, sig)
+}
+
+// [PrivateKey] stores an HSS private key, including its counter of
+// one-time keys. It is safe for concurrent use.
+// It implements the [crypto.Signer] and [crypto.PrivateKey] interfaces.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PrivateKey struct {
+	levels  []Level
+	seed    []byte
+	id      identifier
+	shifts  []int // shifts[i] is the total height of the levels below i.
+	counter stateful.Counter
+
+	mu      sync.Mutex
+	trees   []*tree
+	parents []uint64 // parents[i] is the index of the parent of trees[i].
+	signed  [][]byte // signed[i] is sig[i] || pub[i+1].
+}
+
+// init sets the levels and the seed of the private key, and computes its
+// top-level tree. It returns the total number of one-time keys, and the
+// caller must initialize the counter.
+func (k *PrivateKey) init(levels []Level, seed []byte, id *identifier) (limit uint64) {
+	k.levels = levels
+	k.seed = seed
+	k.id = *id
+	k.shifts = make([]int, len(levels))
+	k.trees = make([]*tree, len(levels))
+	k.parents = make([]uint64, len(levels))
+	k.signed = make([][]byte, len(levels)-1)
+
+	height := 0
+	for i := len(levels) - 1; i >= 0; i-- {
+		k.shifts[i] = height
+		height += levels[i].LMS.params().h
+	}
+
+	k.trees[0] = newTree(levels[0], seed, id)
+	if height < 64 {
+		return 1 << height
+	}
+	return math.MaxUint64
+}
+
+// leafIndex returns the index of the leaf of level i used to sign with the
+// q-th one-time key of the HSS key.
+func (k *PrivateKey) leafIndex(q uint64, i int) uint32 {
+	return uint32(q>>k.shifts[i]) & (1<<k.levels[i].LMS.params().h - 1)
+}
+
+// prepare updates the trees of the lower levels to sign with the q-th
+// one-time key. The caller must hold k.mu.
+func (k *PrivateKey) prepare(q uint64) {
+	for i := 1; i < len(k.levels); i++ {
+		parent := q >> k.shifts[i-1]
+		if k.trees[i] != nil && k.parents[i] == parent {
+			continue
+		}
+
+		t := k.trees[i-1]
+		leaf := k.leafIndex(q, i-1)
+		seed := t.lms.deriveValue(t.seed, &t.id, leaf, dChildSd)
+		var id identifier
+		copy(id[:], t.lms.deriveValue(t.seed, &t.id, leaf, dChildID))
+
+		k.trees[i] = newTree(k.levels[i], seed, &id)
+		k.parents[i] = parent
+		pub := k.trees[i].publicKey()
+		k.signed[i-1] = append(t.sign(leaf, pub), pub...)
+		for j := i + 1; j < len(k.levels); j++ {
+			k.trees[j] = nil
+		}
+	}
+}
+
+// sign returns the HSS signature of message with the q-th one-time key,
+// as in RFC 8554 -- Section 6.2.
+func (k *PrivateKey) sign(q uint64, message []byte) []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.prepare(q)
+
+	last := len(k.levels) - 1
+	sig := binary.BigEndian.AppendUint32(nil, uint32(last))
+	for _, s := range k.signed {
+		sig = append(sig, s...)
+	}
+	return append(sig, k.trees[last].sign(k.leafIndex(q, last), message)...)
+}
+
+// [PrivateKey.Reserve] hands out the next n one-time keys, advancing the
+// counter of the private key. The private key must be persisted before
+// releasing signatures made with the [Reservation].
+// It returns [ErrExhausted] if fewer than n one-time keys are left.
+func (k *PrivateKey) Reserve(n uint64) (*Reservation, error) {
+	r, err := k.counter.Reserve(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reservation{k, r}, nil
+}
+
+// Remaining returns the number of one-time keys that were not handed out.
+func (k *PrivateKey) Remaining() uint64 { return k.counter.Remaining() }
+
+// [PrivateKey.Sign] returns a signature of the message with the next
+// one-time key. The private key must be persisted before releasing the
+// signature. Pre-hashed messages are not supported, so opts.HashFunc()
+// must be zero. The random source is not used.
+// It returns [ErrExhausted] if no one-time key is left.
+func (k *PrivateKey) Sign(
+	random io.Reader, message []byte, opts crypto.SignerOpts,
+) (signature []byte, err error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, ErrPreHash
+	}
+
+	r, err := k.Reserve(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Sign(message)
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey { return k.PublicKey() }
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{uint32(len(k.levels)), k.trees[0].publicKey()}
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+
+	a, _ := k.MarshalBinary()
+	b, _ := other.MarshalBinary()
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// MarshalBinary returns the levels, the counter, the identifier and the
+// seed of the private key. The format is specific to this package:
+//
+//	u32str(L) || (u32str(lms_type) || u32str(lmots_type))^L ||
+//	u64str(counter) || I || SEED
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, uint32(len(k.levels)))
+	for _, l := range k.levels {
+		b = binary.BigEndian.AppendUint32(b, uint32(l.LMS))
+		b = binary.BigEndian.AppendUint32(b, uint32(l.OTS))
+	}
+	b = binary.BigEndian.AppendUint64(b, k.counter.Next())
+	b = append(b, k.id[:]...)
+	return append(b, k.seed...), nil
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes, including
+// its counter of one-time keys.
+// As it recomputes the top-level tree, it is as slow as key generation.
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	errInvalid := errors.New("sign/lms: invalid private key")
+	if len(b) < 4 {
+		return errInvalid
+	}
+
+	n := binary.BigEndian.Uint32(b)
+	if n == 0 || n > MaxLevels || len(b) < 4+8*int(n)+8+idSize {
+		return errInvalid
+	}
+
+	levels := make([]Level, n)
+	for i := range levels {
+		levels[i].LMS = LMSType(binary.BigEndian.Uint32(b[4+8*i:]))
+		levels[i].OTS = OTSType(binary.BigEndian.Uint32(b[8+8*i:]))
+	}
+	if checkLevels(levels) != nil {
+		return errInvalid
+	}
+
+	b = b[4+8*n:]
+	next := binary.BigEndian.Uint64(b)
+	id := (*identifier)(b[8:])
+	seed := b[8+idSize:]
+	if len(seed) != levels[0].LMS.params().n {
+		return errInvalid
+	}
+
+	limit := k.init(levels, append([]byte{}, seed...), id)
+	if next > limit {
+		return errInvalid
+	}
+
+	k.counter.Init(next, limit)
+	return nil
+}
+
+// [Reservation] is a set of one-time keys handed out by a [PrivateKey].
+// It is safe for concurrent use.
+type Reservation struct {
+	key     *PrivateKey
+	indices *stateful.Range
+}
+
+// [Reservation.Sign] returns a signature of the message with the next
+// one-time key of the reservation.
+// It returns [ErrExhausted] if all the one-time keys of the reservation
+// were used.
+func (r *Reservation) Sign(message []byte) ([]byte, error) {
+	q, err := r.indices.Take()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.key.sign(q, message), nil
+}
+
+// Remaining returns the number of one-time keys left in the reservation.
+func (r *Reservation) Remaining() uint64 { return r.indices.Remaining() }
@@ -0,0 +1,110 @@
+// Package lms provides the Leighton-Micali stateful hash-based signature
+// scheme, and its hierarchical variant HSS, as specified in RFC 8554 and
+// NIST SP 800-208.
+//
+// Each private key contains a finite number of one-time keys, indexed by a
+// counter, and an index must never be used twice. As the counter is part
+// of the private key, the private key must be persisted after handing out
+// indices and before releasing the signatures made with them:
+//
+//	r, err := priv.Reserve(100)      // Advances the counter by 100.
+//	b, err := priv.MarshalBinary()   // Persists the new counter,
+//	err = store(b)                   // before signing.
+//	sig, err := r.Sign(message)      // Up to 100 signatures.
+//
+// Indices handed out by a [Reservation] that are not used are lost.
+// Signing with [PrivateKey.Sign] reserves a single index, so the private
+// key must be persisted before the signature is released.
+//
+// Private keys are made of a seed, from which the one-time keys and the
+// keys of the lower levels are derived, following RFC 8554 -- Appendix A.
+// The derivation of the randomizers and of the lower-level keys is not
+// specified by RFC 8554, so private keys are only interoperable with this
+// package. Signatures and public keys follow RFC 8554.
+package lms
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// MaxLevels is the maximum number of levels of an HSS key.
+const MaxLevels = 8
+
+var (
+	ErrParam   = errors.New("sign/lms: invalid parameters")
+	ErrPreHash = errors.New("sign/lms: pre-hashed messages are not supported")
+)
+
+// [GenerateKey] returns a pair of HSS keys with the levels specified, from
+// top to bottom. A single level is equivalent to an LMS key.
+// It returns an error if the levels are invalid or if it fails reading from
+// the random source.
+func GenerateKey(random io.Reader, levels ...Level) (*PublicKey, *PrivateKey, error) {
+	if err := checkLevels(levels); err != nil {
+		return nil, nil, err
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, levels[0].LMS.params().n)
+	var id identifier
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(random, id[:]); err != nil {
+		return nil, nil, err
+	}
+
+	priv := new(PrivateKey)
+	priv.counter.Init(0, priv.init(levels, seed, &id))
+	return priv.PublicKey(), priv, nil
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of HSS keys with the
+// levels specified from the seed and the identifier of the top-level tree,
+// as in RFC 8554 -- Appendix A.
+// The seed must be as long as the output of the hash function of the
+// levels.
+func NewKeyFromSeed(
+	seed []byte, id [16]byte, levels ...Level,
+) (*PublicKey, *PrivateKey, error) {
+	if err := checkLevels(levels); err != nil {
+		return nil, nil, err
+	}
+	if len(seed) != levels[0].LMS.params().n {
+		return nil, nil, ErrParam
+	}
+
+	priv := new(PrivateKey)
+	priv.counter.Init(0, priv.init(levels, append([]byte{}, seed...), &id))
+	return priv.PublicKey(), priv, nil
+}
+
+// checkLevels returns an error if the levels are invalid. All levels must
+// use the same hash function, and the total height must not exceed 64.
+func checkLevels(levels []Level) error {
+	if len(levels) == 0 || len(levels) > MaxLevels {
+		return ErrParam
+	}
+
+	height := 0
+	for _, l := range levels {
+		if !l.IsValid() || l.LMS.params().hashFn != levels[0].LMS.params().hashFn {
+			return ErrParam
+		}
+		height += l.LMS.params().h
+	}
+	if height > 64 {
+		return ErrParam
+	}
+
+	return nil
+}
+
+// [Verify] returns true if the signature of the message is valid.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	return pub.verify(message, signature)
+}
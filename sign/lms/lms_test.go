@@ -0,0 +1,158 @@
+package lms_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign/lms"
+)
+
+func TestLMS(t *testing.T) {
+	for _, levels := range [][]lms.Level{
+		{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8}},
+		{{lms.LMS_SHA256_M24_H5, lms.LMOTS_SHA256_N24_W4}},
+		{{lms.LMS_SHAKE_M32_H5, lms.LMOTS_SHAKE_N32_W2}},
+		{{lms.LMS_SHAKE_M24_H5, lms.LMOTS_SHAKE_N24_W1}},
+		{
+			{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8},
+			{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W4},
+		},
+		{
+			{lms.LMS_SHAKE_M24_H5, lms.LMOTS_SHAKE_N24_W8},
+			{lms.LMS_SHAKE_M24_H5, lms.LMOTS_SHAKE_N24_W8},
+			{lms.LMS_SHAKE_M24_H5, lms.LMOTS_SHAKE_N24_W8},
+		},
+	} {
+		pub, priv, err := lms.GenerateKey(rand.Reader, levels...)
+		test.CheckNoErr(t, err, "GenerateKey failed")
+		testSign(t, pub, priv)
+		testMarshal(t, pub, priv)
+	}
+}
+
+func testSign(t *testing.T, pub *lms.PublicKey, priv *lms.PrivateKey) {
+	msg := []byte("Alice and Bob")
+	for range min(40, priv.Remaining()) {
+		sig, err := priv.Sign(nil, msg, crypto.Hash(0))
+		test.CheckNoErr(t, err, "Sign failed")
+		test.CheckOk(lms.Verify(pub, msg, sig), "Verify failed", t)
+		test.CheckOk(!lms.Verify(pub, msg[1:], sig), "Verify should fail", t)
+
+		for _, i := range []int{0, 7, len(sig) / 2, len(sig) - 1} {
+			sig[i] ^= 1
+			test.CheckOk(!lms.Verify(pub, msg, sig), "Verify should fail", t)
+			sig[i] ^= 1
+		}
+		test.CheckOk(!lms.Verify(pub, msg, sig[:len(sig)-1]), "Verify should fail", t)
+	}
+
+	_, err := priv.Sign(nil, msg, crypto.SHA256)
+	test.CheckIsErr(t, err, "Sign should fail with pre-hash")
+}
+
+func testMarshal(t *testing.T, pub *lms.PublicKey, priv *lms.PrivateKey) {
+	b, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	pub2 := new(lms.PublicKey)
+	test.CheckNoErr(t, pub2.UnmarshalBinary(b), "UnmarshalBinary failed")
+	test.CheckOk(pub.Equal(pub2), "public keys not equal", t)
+	test.CheckIsErr(t, pub2.UnmarshalBinary(b[:len(b)-1]), "UnmarshalBinary should fail")
+
+	b, err = priv.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	priv2 := new(lms.PrivateKey)
+	test.CheckNoErr(t, priv2.UnmarshalBinary(b), "UnmarshalBinary failed")
+	test.CheckOk(priv.Equal(priv2), "private keys not equal", t)
+	test.CheckOk(priv2.Remaining() == priv.Remaining(), "wrong counter", t)
+	test.CheckOk(pub.Equal(priv2.Public()), "public keys not equal", t)
+	test.CheckIsErr(t, priv2.UnmarshalBinary(b[:len(b)-1]), "UnmarshalBinary should fail")
+}
+
+func TestReserve(t *testing.T) {
+	level := lms.Level{LMS: lms.LMS_SHA256_M32_H5, OTS: lms.LMOTS_SHA256_N32_W8}
+	pub, priv, err := lms.NewKeyFromSeed(make([]byte, 32), [16]byte{}, level)
+	test.CheckNoErr(t, err, "NewKeyFromSeed failed")
+	test.CheckOk(priv.Remaining() == 32, "wrong number of keys", t)
+
+	r, err := priv.Reserve(10)
+	test.CheckNoErr(t, err, "Reserve failed")
+	test.CheckOk(priv.Remaining() == 22, "wrong number of keys", t)
+
+	// The persisted key must not hand out the reserved indices again.
+	b, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	restored := new(lms.PrivateKey)
+	test.CheckNoErr(t, restored.UnmarshalBinary(b), "UnmarshalBinary failed")
+
+	seen := make(map[string]bool)
+	msg := []byte("message")
+	for range 10 {
+		sig, err := r.Sign(msg)
+		test.CheckNoErr(t, err, "Sign failed")
+		test.CheckOk(lms.Verify(pub, msg, sig), "Verify failed", t)
+		seen[string(sig[4:8])] = true
+	}
+	_, err = r.Sign(msg)
+	test.CheckOk(errors.Is(err, lms.ErrExhausted), "Sign should fail", t)
+	test.CheckOk(r.Remaining() == 0, "wrong number of keys", t)
+
+	for restored.Remaining() > 0 {
+		sig, err := restored.Sign(nil, msg, nil)
+		test.CheckNoErr(t, err, "Sign failed")
+		test.CheckOk(lms.Verify(pub, msg, sig), "Verify failed", t)
+		test.CheckOk(!seen[string(sig[4:8])], "index used twice", t)
+		seen[string(sig[4:8])] = true
+	}
+	test.CheckOk(len(seen) == 32, "indices not all used", t)
+
+	_, err = restored.Reserve(1)
+	test.CheckOk(errors.Is(err, lms.ErrExhausted), "Reserve should fail", t)
+	_, err = priv.Reserve(23)
+	test.CheckOk(errors.Is(err, lms.ErrExhausted), "Reserve should fail", t)
+}
+
+func TestInvalidLevels(t *testing.T) {
+	for _, levels := range [][]lms.Level{
+		nil,
+		{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N24_W8}},
+		{{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHAKE_N32_W8}},
+		{{lms.LMS_SHA256_M32_H5, 0}},
+		{
+			{lms.LMS_SHA256_M32_H5, lms.LMOTS_SHA256_N32_W8},
+			{lms.LMS_SHAKE_M32_H5, lms.LMOTS_SHAKE_N32_W8},
+		},
+		make([]lms.Level, lms.MaxLevels+1),
+	} {
+		_, _, err := lms.GenerateKey(rand.Reader, levels...)
+		test.CheckIsErr(t, err, "GenerateKey should fail")
+	}
+}
+
+func BenchmarkLMS(b *testing.B) {
+	level := lms.Level{LMS: lms.LMS_SHA256_M32_H10, OTS: lms.LMOTS_SHA256_N32_W4}
+	msg := []byte("Alice and Bob")
+
+	b.Run("GenerateKey", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = lms.GenerateKey(rand.Reader, level)
+		}
+	})
+
+	pub, priv, _ := lms.GenerateKey(rand.Reader, level, level)
+	r, _ := priv.Reserve(priv.Remaining())
+	sig, _ := r.Sign(msg)
+
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = r.Sign(msg)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = lms.Verify(pub, msg, sig)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package lms
+
+import (
+	"encoding/binary"
+)
+
+// See RFC 8554 -- Section 4
+// LM-OTS One-Time Signatures
+
+// coef returns the i-th Winternitz coefficient of s.
+func (p *otsParams) coef(s []byte, i int) int {
+	perByte := 8 / p.w
+	shift := 8 - p.w*(i%perByte+1)
+	return int(s[i/perByte]>>shift) & (1<<p.w - 1)
+}
+
+// digits returns the coefficients of Q || Cksm(Q).
+func (p *otsParams) digits(q []byte) []int {
+	u := 8 * p.n / p.w
+	sum := 0
+	for i := range u {
+		sum += 1<<p.w - 1 - p.coef(q, i)
+	}
+
+	s := make([]byte, p.n+2)
+	copy(s, q)
+	binary.BigEndian.PutUint16(s[p.n:], uint16(sum<<p.ls))
+
+	a := make([]int, p.p)
+	for i := range a {
+		a[i] = p.coef(s, i)
+	}
+	return a
+}
+
+// chain iterates the hash chain i from step start to step end, exclusive,
+// on tmp in place.
+func (p *otsParams) chain(id *identifier, q uint32, i, start, end int, tmp []byte) {
+	in := prefix(id, q, uint16(i))
+	in = append(in, 0)
+	in = append(in, tmp...)
+	for j := start; j < end; j++ {
+		in[idSize+6] = byte(j)
+		p.sum(in[idSize+7:], in)
+	}
+	copy(tmp, in[idSize+7:])
+}
+
+// publicKey returns K, the hash of the ends of the chains of the q-th key.
+func (p *otsParams) publicKey(seed []byte, id *identifier, q uint32) []byte {
+	in := prefix(id, q, dPblc)
+	tmp := make([]byte, p.n)
+	for i := range p.p {
+		copy(tmp, p.deriveValue(seed, id, q, uint16(i)))
+		p.chain(id, q, i, 0, 1<<p.w-1, tmp)
+		in = append(in, tmp...)
+	}
+
+	k := make([]byte, p.n)
+	p.sum(k, in)
+	return k
+}
+
+// messageHash returns Q = H(I || u32str(q) || u16str(D_MESG) || C || message).
+func (p *otsParams) messageHash(id *identifier, q uint32, c, message []byte) []byte {
+	in := prefix(id, q, dMesg)
+	in = append(in, c...)
+	in = append(in, message...)
+	out := make([]byte, p.n)
+	p.sum(out, in)
+	return out
+}
+
+func (t OTSType) signatureSize() int {
+	p := t.params()
+	return 4 + p.n*(p.p+1)
+}
+
+// sign returns the LM-OTS signature of message with the q-th key.
+// The randomizer C is derived from the seed.
+func (t OTSType) sign(seed []byte, id *identifier, q uint32, message []byte) []byte {
+	p := t.params()
+	c := p.deriveValue(seed, id, q, dRand)
+	a := p.digits(p.messageHash(id, q, c, message))
+
+	sig := make([]byte, 4, t.signatureSize())
+	binary.BigEndian.PutUint32(sig, uint32(t))
+	sig = append(sig, c...)
+	for i := range p.p {
+		tmp := p.deriveValue(seed, id, q, uint16(i))
+		p.chain(id, q, i, 0, a[i], tmp)
+		sig = append(sig, tmp...)
+	}
+	return sig
+}
+
+// candidateKey returns the public key candidate Kc computed from an LM-OTS
+// signature, or nil if the signature is malformed.
+func (t OTSType) candidateKey(id *identifier, q uint32, message, sig []byte) []byte {
+	if len(sig) != t.signatureSize() || binary.BigEndian.Uint32(sig) != uint32(t) {
+		return nil
+	}
+
+	p := t.params()
+	c, y := sig[4:4+p.n], sig[4+p.n:]
+	a := p.digits(p.messageHash(id, q, c, message))
+
+	in := prefix(id, q, dPblc)
+	tmp := make([]byte, p.n)
+	for i := range p.p {
+		copy(tmp, y[i*p.n:])
+		p.chain(id, q, i, a[i], 1<<p.w-1, tmp)
+		in = append(in, tmp...)
+	}
+
+	k := make([]byte, p.n)
+	p.sum(k, in)
+	return k
+}
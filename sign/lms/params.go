@@ -0,0 +1,183 @@
+package lms
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// [LMSType] identifies the parameters of an LMS tree.
+// See RFC 8554 and NIST SP 800-208.
+type LMSType uint32
+
+//nolint:stylecheck
+const (
+	LMS_SHA256_M32_H5  LMSType = 0x05
+	LMS_SHA256_M32_H10 LMSType = 0x06
+	LMS_SHA256_M32_H15 LMSType = 0x07
+	LMS_SHA256_M32_H20 LMSType = 0x08
+	LMS_SHA256_M32_H25 LMSType = 0x09
+	LMS_SHA256_M24_H5  LMSType = 0x0a
+	LMS_SHA256_M24_H10 LMSType = 0x0b
+	LMS_SHA256_M24_H15 LMSType = 0x0c
+	LMS_SHA256_M24_H20 LMSType = 0x0d
+	LMS_SHA256_M24_H25 LMSType = 0x0e
+	LMS_SHAKE_M32_H5   LMSType = 0x0f
+	LMS_SHAKE_M32_H10  LMSType = 0x10
+	LMS_SHAKE_M32_H15  LMSType = 0x11
+	LMS_SHAKE_M32_H20  LMSType = 0x12
+	LMS_SHAKE_M32_H25  LMSType = 0x13
+	LMS_SHAKE_M24_H5   LMSType = 0x14
+	LMS_SHAKE_M24_H10  LMSType = 0x15
+	LMS_SHAKE_M24_H15  LMSType = 0x16
+	LMS_SHAKE_M24_H20  LMSType = 0x17
+	LMS_SHAKE_M24_H25  LMSType = 0x18
+)
+
+// [OTSType] identifies the parameters of an LM-OTS one-time signature.
+// See RFC 8554 and NIST SP 800-208.
+type OTSType uint32
+
+//nolint:stylecheck
+const (
+	LMOTS_SHA256_N32_W1 OTSType = 0x01
+	LMOTS_SHA256_N32_W2 OTSType = 0x02
+	LMOTS_SHA256_N32_W4 OTSType = 0x03
+	LMOTS_SHA256_N32_W8 OTSType = 0x04
+	LMOTS_SHA256_N24_W1 OTSType = 0x05
+	LMOTS_SHA256_N24_W2 OTSType = 0x06
+	LMOTS_SHA256_N24_W4 OTSType = 0x07
+	LMOTS_SHA256_N24_W8 OTSType = 0x08
+	LMOTS_SHAKE_N32_W1  OTSType = 0x09
+	LMOTS_SHAKE_N32_W2  OTSType = 0x0a
+	LMOTS_SHAKE_N32_W4  OTSType = 0x0b
+	LMOTS_SHAKE_N32_W8  OTSType = 0x0c
+	LMOTS_SHAKE_N24_W1  OTSType = 0x0d
+	LMOTS_SHAKE_N24_W2  OTSType = 0x0e
+	LMOTS_SHAKE_N24_W4  OTSType = 0x0f
+	LMOTS_SHAKE_N24_W8  OTSType = 0x10
+)
+
+// [Level] specifies the parameters of one level of an HSS key.
+type Level struct {
+	LMS LMSType
+	OTS OTSType
+}
+
+// IsValid returns true if both types are supported and use the same hash
+// function with the same output length.
+func (l Level) IsValid() bool {
+	return l.LMS.IsValid() && l.OTS.IsValid() &&
+		l.LMS.params().hashFn == l.OTS.params().hashFn
+}
+
+func (t LMSType) IsValid() bool { return LMS_SHA256_M32_H5 <= t && t <= LMS_SHAKE_M24_H25 }
+func (t OTSType) IsValid() bool { return LMOTS_SHA256_N32_W1 <= t && t <= LMOTS_SHAKE_N24_W8 }
+
+// hashFn identifies a hash function together with its output length.
+type hashFn struct {
+	shake bool
+	n     int
+}
+
+var (
+	sha256n32 = hashFn{false, 32}
+	sha256n24 = hashFn{false, 24}
+	shakeN32  = hashFn{true, 32}
+	shakeN24  = hashFn{true, 24}
+)
+
+// sum writes to out the hash of in, truncated to the output length.
+func (h hashFn) sum(out, in []byte) {
+	if h.shake {
+		sha3.ShakeSum256(out[:h.n], in)
+	} else {
+		s := sha256.Sum256(in)
+		copy(out[:h.n], s[:])
+	}
+}
+
+// lmsParams contains the constants of an LMS tree.
+type lmsParams struct {
+	hashFn
+	h int // Height of the tree.
+}
+
+func (t LMSType) params() *lmsParams {
+	if !t.IsValid() {
+		panic(ErrParam)
+	}
+
+	i := int(t - LMS_SHA256_M32_H5)
+	hashes := [...]hashFn{sha256n32, sha256n24, shakeN32, shakeN24}
+	return &lmsParams{hashes[i/5], 5 * (i%5 + 1)}
+}
+
+// otsParams contains the constants of an LM-OTS one-time signature.
+type otsParams struct {
+	hashFn
+	w  int // Width in bits of the Winternitz coefficients.
+	p  int // Number of chains.
+	ls int // Left shift of the checksum.
+}
+
+func (t OTSType) params() *otsParams {
+	if !t.IsValid() {
+		panic(ErrParam)
+	}
+
+	i := int(t - LMOTS_SHA256_N32_W1)
+	hashes := [...]hashFn{sha256n32, sha256n24, shakeN32, shakeN24}
+	p := &otsParams{hashFn: hashes[i/4], w: 1 << (i % 4)}
+
+	// See RFC 8554 -- Appendix B
+	u := (8*p.n + p.w - 1) / p.w
+	maxSum := u * (1<<p.w - 1)
+	bits := 0
+	for ; maxSum > 0; maxSum >>= 1 {
+		bits++
+	}
+	v := (bits + p.w - 1) / p.w
+	p.p = u + v
+	p.ls = 16 - v*p.w
+	return p
+}
+
+// Domain separators, see RFC 8554 -- Section 7.1.
+const (
+	dPblc = 0x8080
+	dMesg = 0x8181
+	dLeaf = 0x8282
+	dIntr = 0x8383
+
+	// Domain separators used to derive values from a seed, which are not
+	// specified by RFC 8554.
+	dRand    = 0xfffd
+	dChildSd = 0xfffe
+	dChildID = 0xffff
+)
+
+const idSize = 16
+
+// Tree identifier.
+type identifier = [idSize]byte
+
+// prefix returns I || u32str(q) || u16str(d).
+func prefix(id *identifier, q uint32, d uint16) []byte {
+	b := make([]byte, idSize+6)
+	copy(b, id[:])
+	binary.BigEndian.PutUint32(b[idSize:], q)
+	binary.BigEndian.PutUint16(b[idSize+4:], d)
+	return b
+}
+
+// deriveValue returns H(I || u32str(q) || u16str(d) || u8str(0xff) || SEED),
+// as in RFC 8554 -- Appendix A.
+func (h hashFn) deriveValue(seed []byte, id *identifier, q uint32, d uint16) []byte {
+	in := append(prefix(id, q, d), 0xff)
+	in = append(in, seed...)
+	out := make([]byte, h.n)
+	h.sum(out, in)
+	return out
+}
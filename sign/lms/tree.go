@@ -0,0 +1,215 @@
+package lms
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// See RFC 8554 -- Section 5
+// Leighton-Micali Signatures
+
+// maxCachedHeight is the height of the top part of a tree that is kept in
+// memory. The authentication path of a leaf below it is obtained by
+// recomputing the subtree containing the leaf.
+var maxCachedHeight = 15
+
+// tree is an LMS private key, which caches its nodes to speed up signing.
+type tree struct {
+	Level
+	lms  *lmsParams
+	ots  *otsParams
+	seed []byte
+	id   identifier
+
+	// The nodes T[r] for 1 <= r < 2^(h-cut+1), from the root down to
+	// height cut, indexed by r.
+	cut   int
+	nodes [][]byte
+
+	// The last subtree of height cut computed, rooted at node subRoot.
+	subRoot uint32
+	subtree [][]byte
+}
+
+func newTree(level Level, seed []byte, id *identifier) *tree {
+	t := &tree{
+		Level: level,
+		lms:   level.LMS.params(),
+		ots:   level.OTS.params(),
+		seed:  seed,
+		id:    *id,
+	}
+	t.cut = max(0, t.lms.h-maxCachedHeight)
+
+	top := 1 << (t.lms.h - t.cut)
+	t.nodes = make([][]byte, 2*top)
+	for j := range top {
+		r := uint32(top + j)
+		t.nodes[r] = t.computeSubtree(r)[1]
+	}
+	for r := top - 1; r > 0; r-- {
+		t.nodes[r] = t.interior(uint32(r), t.nodes[2*r], t.nodes[2*r+1])
+	}
+	return t
+}
+
+func (t *tree) leaf(r uint32, k []byte) []byte {
+	in := append(prefix(&t.id, r, dLeaf), k...)
+	out := make([]byte, t.lms.n)
+	t.lms.sum(out, in)
+	return out
+}
+
+func (t *tree) interior(r uint32, left, right []byte) []byte {
+	in := prefix(&t.id, r, dIntr)
+	in = append(in, left...)
+	in = append(in, right...)
+	out := make([]byte, t.lms.n)
+	t.lms.sum(out, in)
+	return out
+}
+
+// computeSubtree returns the nodes of the subtree of height cut rooted at
+// node r, where node i of the subtree, for 1 <= i < 2^(cut+1), is the node
+// (r << d) + i - 2^d of the tree, d being the depth of node i.
+func (t *tree) computeSubtree(r uint32) [][]byte {
+	size := 1 << t.cut
+	nodes := make([][]byte, 2*size)
+	first := r << t.cut
+	for i := range size {
+		q := first + uint32(i) - 1<<t.lms.h
+		nodes[size+i] = t.leaf(first+uint32(i), t.ots.publicKey(t.seed, &t.id, q))
+	}
+	for d := t.cut - 1; d >= 0; d-- {
+		for i := 1 << d; i < 2<<d; i++ {
+			global := r<<d + uint32(i) - 1<<d
+			nodes[i] = t.interior(global, nodes[2*i], nodes[2*i+1])
+		}
+	}
+	return nodes
+}
+
+// node returns the node T[r], which must be at height below cut only if
+// it belongs to the subtree containing leaf q.
+func (t *tree) node(r uint32, height int, q uint32) []byte {
+	if height >= t.cut {
+		return t.nodes[r]
+	}
+
+	root := (1<<t.lms.h + q) >> t.cut
+	if t.subtree == nil || t.subRoot != root {
+		t.subRoot, t.subtree = root, t.computeSubtree(root)
+	}
+
+	d := t.cut - height
+	return t.subtree[1<<d+int(r-root<<d)]
+}
+
+func (t *tree) root() []byte { return t.nodes[1] }
+
+func (t *tree) publicKeySize() int { return 8 + idSize + t.lms.n }
+
+// publicKey returns u32str(type) || u32str(otstype) || I || T[1].
+func (t *tree) publicKey() []byte {
+	pub := make([]byte, 8, t.publicKeySize())
+	binary.BigEndian.PutUint32(pub, uint32(t.LMS))
+	binary.BigEndian.PutUint32(pub[4:], uint32(t.OTS))
+	pub = append(pub, t.id[:]...)
+	return append(pub, t.root()...)
+}
+
+func (l Level) signatureSize() int {
+	return 8 + l.OTS.signatureSize() + l.LMS.params().h*l.LMS.params().n
+}
+
+// sign returns the LMS signature of message with the q-th leaf.
+func (t *tree) sign(q uint32, message []byte) []byte {
+	sig := make([]byte, 4, t.Level.signatureSize())
+	binary.BigEndian.PutUint32(sig, q)
+	sig = append(sig, t.OTS.sign(t.seed, &t.id, q, message)...)
+	sig = binary.BigEndian.AppendUint32(sig, uint32(t.LMS))
+
+	r := uint32(1)<<t.lms.h + q
+	for i := range t.lms.h {
+		sig = append(sig, t.node((r>>i)^1, i, q)...)
+	}
+	return sig
+}
+
+// parseLMSPublicKey splits an LMS public key into its level, identifier
+// and root. It returns false if the public key is malformed.
+func parseLMSPublicKey(pub []byte) (level Level, id *identifier, root []byte, ok bool) {
+	if len(pub) < 8 {
+		return
+	}
+
+	level.LMS = LMSType(binary.BigEndian.Uint32(pub))
+	level.OTS = OTSType(binary.BigEndian.Uint32(pub[4:]))
+	if !level.IsValid() || len(pub) != 8+idSize+level.LMS.params().n {
+		return
+	}
+
+	return level, (*identifier)(pub[8:]), pub[8+idSize:], true
+}
+
+// lmsSignatureLength returns the length of the LMS signature at the start
+// of sig, or zero if the signature is malformed.
+func lmsSignatureLength(sig []byte) int {
+	if len(sig) < 8 {
+		return 0
+	}
+
+	ots := OTSType(binary.BigEndian.Uint32(sig[4:]))
+	if !ots.IsValid() {
+		return 0
+	}
+
+	n := 4 + ots.signatureSize()
+	if len(sig) < n+4 {
+		return 0
+	}
+
+	typ := LMSType(binary.BigEndian.Uint32(sig[n:]))
+	if !typ.IsValid() {
+		return 0
+	}
+
+	return n + 4 + typ.params().h*typ.params().n
+}
+
+// verifyLMS returns true if sig is a valid LMS signature of message under
+// the LMS public key pub.
+func verifyLMS(pub, message, sig []byte) bool {
+	level, id, root, ok := parseLMSPublicKey(pub)
+	if !ok || len(sig) != level.signatureSize() {
+		return false
+	}
+
+	p := level.LMS.params()
+	q := binary.BigEndian.Uint32(sig)
+	otsSig := sig[4 : 4+level.OTS.signatureSize()]
+	rest := sig[4+len(otsSig):]
+	if binary.BigEndian.Uint32(rest) != uint32(level.LMS) || q >= 1<<p.h {
+		return false
+	}
+
+	k := level.OTS.candidateKey(id, q, message, otsSig)
+	if k == nil {
+		return false
+	}
+
+	t := tree{Level: level, lms: p, id: *id}
+	r := uint32(1)<<p.h + q
+	tmp := t.leaf(r, k)
+	path := rest[4:]
+	for i := 0; r > 1; i, r = i+1, r>>1 {
+		sibling := path[i*p.n : (i+1)*p.n]
+		if r&1 == 1 {
+			tmp = t.interior(r>>1, sibling, tmp)
+		} else {
+			tmp = t.interior(r>>1, tmp, sibling)
+		}
+	}
+
+	return bytes.Equal(tmp, root)
+}
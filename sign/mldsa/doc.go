@@ -5,6 +5,11 @@
 //
 //	github.com/cloudflare/circl/sign/mldsa/mldsa44
 //
+// Besides pure ML-DSA, the subpackages implement the pre-hash variant
+// HashML-DSA, see SignPreHashTo and VerifyPreHash, and signing of an
+// externally computed message representative μ, see PublicKey.ComputeMu
+// and PrivateKey.SignMuTo.
+//
 // If your choice for mode is fixed compile-time, use the subpackages.
 // To choose a scheme at runtime, use the generic signatures API under
 //
@@ -9,19 +9,25 @@
 //	Dilithium
 //	ML-DSA
 //	SLH-DSA
+//	FN-DSA (Falcon)
+//	Composite ML-DSA
+//	ECDSA
 package schemes
 
 import (
 	"strings"
 
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/composite"
 	dilithium2 "github.com/cloudflare/circl/sign/dilithium/mode2"
 	dilithium3 "github.com/cloudflare/circl/sign/dilithium/mode3"
 	dilithium5 "github.com/cloudflare/circl/sign/dilithium/mode5"
+	"github.com/cloudflare/circl/sign/ecdsa"
 	"github.com/cloudflare/circl/sign/ed25519"
 	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/cloudflare/circl/sign/eddilithium2"
 	"github.com/cloudflare/circl/sign/eddilithium3"
+	"github.com/cloudflare/circl/sign/fndsa"
 	"github.com/cloudflare/circl/sign/mldsa/mldsa44"
 	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
 	"github.com/cloudflare/circl/sign/mldsa/mldsa87"
@@ -51,6 +57,27 @@ var allSchemes = [...]sign.Scheme{
 	slhdsa.SHAKE_256s.Scheme(),
 	slhdsa.SHA2_256f.Scheme(),
 	slhdsa.SHAKE_256f.Scheme(),
+	fndsa.Falcon512.Scheme(),
+	fndsa.Falcon1024.Scheme(),
+	composite.MLDSA44_RSA2048_PSS_SHA256.Scheme(),
+	composite.MLDSA44_RSA2048_PKCS15_SHA256.Scheme(),
+	composite.MLDSA44_Ed25519_SHA512.Scheme(),
+	composite.MLDSA44_ECDSA_P256_SHA256.Scheme(),
+	composite.MLDSA65_RSA3072_PSS_SHA512.Scheme(),
+	composite.MLDSA65_RSA3072_PKCS15_SHA512.Scheme(),
+	composite.MLDSA65_RSA4096_PSS_SHA512.Scheme(),
+	composite.MLDSA65_RSA4096_PKCS15_SHA512.Scheme(),
+	composite.MLDSA65_ECDSA_P256_SHA512.Scheme(),
+	composite.MLDSA65_ECDSA_P384_SHA512.Scheme(),
+	composite.MLDSA65_Ed25519_SHA512.Scheme(),
+	composite.MLDSA87_ECDSA_P384_SHA512.Scheme(),
+	composite.MLDSA87_Ed448_SHAKE256.Scheme(),
+	composite.MLDSA87_RSA3072_PSS_SHA512.Scheme(),
+	composite.MLDSA87_RSA4096_PSS_SHA512.Scheme(),
+	composite.MLDSA87_ECDSA_P521_SHA512.Scheme(),
+	ecdsa.P256_SHA256.Scheme(),
+	ecdsa.P384_SHA384.Scheme(),
+	ecdsa.P521_SHA512.Scheme(),
 }
 
 var allSchemeNames map[string]sign.Scheme
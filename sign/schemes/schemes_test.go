@@ -1,11 +1,17 @@
 package schemes_test
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/ed25519"
 	"github.com/cloudflare/circl/sign/schemes"
+	"github.com/cloudflare/circl/xof"
 )
 
 func TestCaseSensitivity(t *testing.T) {
@@ -41,7 +47,7 @@ func TestApi(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if len(packedSk) != scheme.PrivateKeySize() {
+			if !checkSize(scheme, len(packedSk), scheme.PrivateKeySize()) {
 				t.Fatal()
 			}
 
@@ -70,7 +76,7 @@ func TestApi(t *testing.T) {
 			}
 			sig := scheme.Sign(sk, msg, opts)
 
-			if scheme.SignatureSize() != len(sig) {
+			if !checkSize(scheme, len(sig), scheme.SignatureSize()) {
 				t.Fatal()
 			}
 
@@ -107,6 +113,219 @@ func TestApi(t *testing.T) {
 	}
 }
 
+// checkSize returns whether size is the one given by the scheme. Composite
+// schemes with RSA or ECDSA, and ECDSA itself, have private keys or
+// signatures of variable size, so the scheme gives their maximum size.
+func checkSize(scheme sign.Scheme, size, want int) bool {
+	name := scheme.Name()
+	if strings.Contains(name, "-RSA") || strings.Contains(name, "ECDSA") {
+		return size <= want
+	}
+	return size == want
+}
+
+func TestPreHash(t *testing.T) {
+	for _, name := range []string{
+		"ML-DSA-44",
+		"ML-DSA-65",
+		"ML-DSA-87",
+		"SLH-DSA-SHA2-128f",
+		"SLH-DSA-SHAKE-128f",
+	} {
+		scheme := schemes.ByName(name)
+		t.Run(name, func(t *testing.T) {
+			pk, sk, err := scheme.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := []byte(fmt.Sprintf("Signing with %s", scheme.Name()))
+			for _, ph := range []sign.PreHash{
+				{Hash: crypto.SHA256},
+				{Hash: crypto.SHA3_512},
+				{Xof: xof.SHAKE128},
+			} {
+				opts := &sign.SignatureOpts{Context: "A context", PreHash: ph}
+				sig := scheme.Sign(sk, msg, opts)
+				if !scheme.Verify(pk, msg, sig, opts) {
+					t.Fatal()
+				}
+
+				if scheme.Verify(pk, msg, sig, &sign.SignatureOpts{
+					Context: "A context",
+				}) {
+					t.Fatal("pre-hash signature verified as pure")
+				}
+
+				if scheme.Verify(pk, msg, sig, &sign.SignatureOpts{
+					Context: "A context",
+					PreHash: sign.PreHash{Xof: xof.SHAKE256},
+				}) {
+					t.Fatal("verified with wrong pre-hash function")
+				}
+			}
+
+			if strings.HasPrefix(name, "ML-DSA") {
+				// HashML-DSA through crypto.Signer
+				digest := sha256.Sum256(msg)
+				sig, err := sk.Sign(nil, digest[:], crypto.SHA256)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !scheme.Verify(pk, msg, sig, &sign.SignatureOpts{
+					PreHash: sign.PreHash{Hash: crypto.SHA256},
+				}) {
+					t.Fatal()
+				}
+
+				_, err = sk.Sign(nil, digest[:], crypto.SHA512)
+				if err == nil {
+					t.Fatal("expected error for wrong digest size")
+				}
+			}
+
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatal("expected panic")
+					}
+				}()
+				scheme.Sign(sk, msg, &sign.SignatureOpts{
+					PreHash: sign.PreHash{Hash: crypto.MD5},
+				})
+			}()
+		})
+	}
+}
+
+func TestEd25519ph(t *testing.T) {
+	scheme := schemes.ByName("Ed25519")
+	pk, sk, err := scheme.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("Signing with Ed25519ph")
+	opts := &sign.SignatureOpts{PreHash: sign.PreHash{Hash: crypto.SHA512}}
+	sig := scheme.Sign(sk, msg, opts)
+	want := ed25519.SignPh(sk.(ed25519.PrivateKey), msg, "")
+	if !bytes.Equal(sig, want) {
+		t.Fatal("Sign with SHA-512 pre-hash is not Ed25519ph")
+	}
+	if !ed25519.VerifyPh(pk.(ed25519.PublicKey), msg, sig, "") {
+		t.Fatal("VerifyPh failed")
+	}
+	if !scheme.Verify(pk, msg, sig, opts) {
+		t.Fatal("Verify failed")
+	}
+	if scheme.Verify(pk, msg, sig, nil) {
+		t.Fatal("Ed25519ph signature verified as Ed25519")
+	}
+}
+
+func TestPreHashNotSupported(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ph   sign.PreHash
+	}{
+		{"Ed25519", sign.PreHash{Hash: crypto.SHA256}},
+		{"Ed448", sign.PreHash{Hash: crypto.SHA512}},
+		{"Ed25519-Dilithium2", sign.PreHash{Hash: crypto.SHA512}},
+		{"Dilithium2", sign.PreHash{Hash: crypto.SHA512}},
+		{"Falcon-512", sign.PreHash{Hash: crypto.SHA512}},
+	} {
+		scheme := schemes.ByName(tc.name)
+		t.Run(tc.name, func(t *testing.T) {
+			_, sk, err := scheme.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				if recover() != sign.ErrPreHashNotSupported {
+					t.Fatal("expected ErrPreHashNotSupported")
+				}
+			}()
+			scheme.Sign(sk, []byte("message"), &sign.SignatureOpts{
+				PreHash: tc.ph,
+			})
+		})
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	for _, scheme := range schemes.All() {
+		ss, ok := scheme.(sign.StreamingScheme)
+		if !ok {
+			continue
+		}
+		name := scheme.Name()
+		if strings.HasPrefix(name, "SLH-DSA") && strings.HasSuffix(name, "s") {
+			continue
+		}
+
+		var ph sign.PreHash
+		switch {
+		case name == "Ed25519":
+			ph = sign.PreHash{Hash: crypto.SHA512}
+		case name == "Ed448":
+			ph = sign.PreHash{Xof: xof.SHAKE256}
+		default:
+			ph = sign.PreHash{Hash: crypto.SHA256}
+		}
+
+		t.Run(name, func(t *testing.T) {
+			pk, sk, err := scheme.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := make([]byte, 1000)
+			for i := range msg {
+				msg[i] = byte(i)
+			}
+
+			for _, opts := range []*sign.SignatureOpts{
+				nil,
+				{PreHash: ph},
+			} {
+				if scheme.SupportsContext() {
+					if opts == nil {
+						opts = &sign.SignatureOpts{}
+					}
+					opts.Context = "A context"
+				}
+
+				signer := ss.NewStreamSigner(sk, opts)
+				for i := 0; i < len(msg); i += 99 {
+					_, _ = signer.Write(msg[i:min(i+99, len(msg))])
+				}
+				sig := signer.Sign()
+				if len(sig) != scheme.SignatureSize() {
+					t.Fatal()
+				}
+
+				if !scheme.Verify(pk, msg, sig, opts) {
+					t.Fatal("streamed signature does not verify")
+				}
+
+				verifier := ss.NewStreamVerifier(pk, opts)
+				_, _ = verifier.Write(msg[:500])
+				_, _ = verifier.Write(msg[500:])
+				if !verifier.Verify(scheme.Sign(sk, msg, opts)) {
+					t.Fatal("signature does not verify streamed")
+				}
+
+				verifier = ss.NewStreamVerifier(pk, opts)
+				_, _ = verifier.Write(msg[1:])
+				if verifier.Verify(sig) {
+					t.Fatal("signature verifies on wrong message")
+				}
+			}
+		})
+	}
+}
+
 func Example() {
 	for _, sch := range schemes.All() {
 		fmt.Println(sch.Name())
@@ -134,6 +353,27 @@ func Example() {
 	// SLH-DSA-SHAKE-256s
 	// SLH-DSA-SHA2-256f
 	// SLH-DSA-SHAKE-256f
+	// Falcon-512
+	// Falcon-1024
+	// MLDSA44-RSA2048-PSS-SHA256
+	// MLDSA44-RSA2048-PKCS15-SHA256
+	// MLDSA44-Ed25519-SHA512
+	// MLDSA44-ECDSA-P256-SHA256
+	// MLDSA65-RSA3072-PSS-SHA512
+	// MLDSA65-RSA3072-PKCS15-SHA512
+	// MLDSA65-RSA4096-PSS-SHA512
+	// MLDSA65-RSA4096-PKCS15-SHA512
+	// MLDSA65-ECDSA-P256-SHA512
+	// MLDSA65-ECDSA-P384-SHA512
+	// MLDSA65-Ed25519-SHA512
+	// MLDSA87-ECDSA-P384-SHA512
+	// MLDSA87-Ed448-SHAKE256
+	// MLDSA87-RSA3072-PSS-SHA512
+	// MLDSA87-RSA4096-PSS-SHA512
+	// MLDSA87-ECDSA-P521-SHA512
+	// ECDSA-P256-SHA256
+	// ECDSA-P384-SHA384
+	// ECDSA-P521-SHA512
 }
 
 func BenchmarkGenerateKeyPair(b *testing.B) {
@@ -9,14 +9,35 @@ import (
 	"crypto"
 	"encoding"
 	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/xof"
 )
 
 type SignatureOpts struct {
 	// If non-empty, includes the given context in the signature if supported
 	// and will cause an error during signing otherwise.
 	Context string
+
+	// If non-zero, the message is hashed with the given function and the
+	// pre-hash variant of the scheme, such as HashML-DSA, is used if
+	// supported. It will cause an error during signing otherwise.
+	PreHash PreHash
+}
+
+// PreHash identifies the function used to hash a message before signing
+// in pre-hash signature variants, such as HashML-DSA and HashSLH-DSA.
+//
+// At most one of Hash and Xof is set. The zero value means that the
+// message is signed directly.
+type PreHash struct {
+	Hash crypto.Hash
+	Xof  xof.ID
 }
 
+// IsZero returns whether ph does not select any pre-hash function.
+func (ph PreHash) IsZero() bool { return ph.Hash == 0 && ph.Xof == 0 }
+
 // A public key is used to verify a signature set by the corresponding private
 // key.
 type PublicKey interface {
@@ -55,14 +76,16 @@ type Scheme interface {
 	// Creates a signature using the PrivateKey on the given message and
 	// returns the signature. opts are additional options which can be nil.
 	//
-	// Panics if key is nil or wrong type or opts context is not supported.
+	// Panics if key is nil or wrong type or opts context or pre-hash is
+	// not supported.
 	Sign(sk PrivateKey, message []byte, opts *SignatureOpts) []byte
 
 	// Checks whether the given signature is a valid signature set by
 	// the private key corresponding to the given public key on the
 	// given message. opts are additional options which can be nil.
 	//
-	// Panics if key is nil or wrong type or opts context is not supported.
+	// Panics if key is nil or wrong type or opts context or pre-hash is
+	// not supported.
 	Verify(pk PublicKey, message []byte, signature []byte, opts *SignatureOpts) bool
 
 	// Deterministically derives a keypair from a seed. If you're unsure,
@@ -93,6 +116,51 @@ type Scheme interface {
 	SupportsContext() bool
 }
 
+// A StreamingScheme is a Scheme that can also sign and verify messages that
+// are written to it in pieces, so that they need not be held in memory at
+// once.
+//
+// Some schemes, such as SLH-DSA and Ed25519, process the message more than
+// once in their pure variant. Those buffer the message in memory, unless a
+// pre-hash function is selected in the options.
+type StreamingScheme interface {
+	Scheme
+
+	// Returns a StreamSigner that creates a signature using the
+	// PrivateKey on the message written to it. opts are additional options
+	// which can be nil.
+	//
+	// Panics if key is nil or wrong type or opts context or pre-hash is
+	// not supported.
+	NewStreamSigner(sk PrivateKey, opts *SignatureOpts) StreamSigner
+
+	// Returns a StreamVerifier that checks signatures set by the private
+	// key corresponding to the given public key on the message written
+	// to it. opts are additional options which can be nil.
+	//
+	// Panics if key is nil or wrong type or opts context or pre-hash is
+	// not supported.
+	NewStreamVerifier(pk PublicKey, opts *SignatureOpts) StreamVerifier
+}
+
+// A StreamSigner creates a signature on the message written to it.
+type StreamSigner interface {
+	io.Writer
+
+	// Returns the signature on the message written so far. The
+	// StreamSigner must not be used afterwards.
+	Sign() []byte
+}
+
+// A StreamVerifier checks a signature on the message written to it.
+type StreamVerifier interface {
+	io.Writer
+
+	// Checks whether the given signature is valid on the message written
+	// so far. The StreamVerifier must not be used afterwards.
+	Verify(signature []byte) bool
+}
+
 var (
 	// ErrTypeMismatch is the error used if types of, for instance, private
 	// and public keys don't match.
@@ -116,4 +184,8 @@ var (
 
 	// ErrContextTooLong is the error used if the context string is too long.
 	ErrContextTooLong = errors.New("context string too long")
+
+	// ErrPreHashNotSupported is the error used if the requested pre-hash
+	// function is not supported.
+	ErrPreHashNotSupported = errors.New("pre-hash not supported")
 )
@@ -40,11 +40,12 @@ func NewPreHashWithHash(h crypto.Hash) (*PreHash, error) {
 		crypto.SHA3_512:   10,
 	}
 
-	oid := hash2oid[h]
-	if oid == 0 {
+	if int(h) >= len(hash2oid) || hash2oid[h] == 0 || !h.Available() {
 		return nil, ErrPreHash
 	}
 
+	oid := hash2oid[h]
+
 	return &PreHash{h.New(), h.Size(), oid}, nil
 }
 
@@ -2,11 +2,16 @@ package slhdsa
 
 import (
 	"crypto/rand"
+	"encoding/asn1"
 
 	"github.com/cloudflare/circl/internal/sha3"
 	"github.com/cloudflare/circl/sign"
+	"github.com/cloudflare/circl/sign/internal/stream"
 )
 
+// Scheme returns a generic signature interface for the parameter set.
+//
+// The scheme also implements [sign.StreamingScheme].
 func (id ID) Scheme() sign.Scheme { return scheme{id.params()} }
 
 type scheme struct{ *params }
@@ -15,18 +20,33 @@ func (s scheme) Name() string          { return s.name }
 func (s scheme) SeedSize() int         { return s.PrivateKeySize() }
 func (s scheme) SupportsContext() bool { return true }
 
+// Oid returns the object identifier of pure SLH-DSA with the parameter set.
+func (s scheme) Oid() asn1.ObjectIdentifier {
+	// Identifiers 20 to 25 are assigned to SHA2, and 26 to 31 to SHAKE,
+	// in increasing security level, small before fast.
+	i := int(s.ID - 1)
+	last := 20 + 2*(i/4) + (i%4)/2
+	if !s.isSHA2 {
+		last += 6
+	}
+	return asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, last}
+}
+
 // GenerateKey is similar to [GenerateKey] function, except it always reads
 // random bytes from [rand.Reader].
 func (s scheme) GenerateKey() (sign.PublicKey, sign.PrivateKey, error) {
 	return GenerateKey(rand.Reader, s.ID)
 }
 
-// Sign returns a randomized pure signature of the message with the context
+// Sign returns a randomized signature of the message with the context
 // given.
 // If options is nil, an empty context is used.
+// If options selects a pre-hash function, a pre-hash signature of the
+// message is returned, otherwise, a pure signature is returned.
 // It returns an empty slice if the signature generation fails.
 //
-// Panics if the key is not a [PrivateKey] or when the [ID] mismatches.
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, or when
+// the pre-hash function is not supported.
 func (s scheme) Sign(
 	priv sign.PrivateKey, message []byte, options *sign.SignatureOpts,
 ) []byte {
@@ -40,7 +60,7 @@ func (s scheme) Sign(
 		context = []byte(options.Context)
 	}
 
-	sig, err := SignRandomized(&k, rand.Reader, NewMessage(message), context)
+	sig, err := SignRandomized(&k, rand.Reader, buildMessage(message, options), context)
 	if err != nil {
 		return nil
 	}
@@ -51,8 +71,11 @@ func (s scheme) Sign(
 // Verify returns true if the signature of the message with the specified
 // context is valid.
 // If options is nil, an empty context is used.
+// If options selects a pre-hash function, the signature is verified as a
+// pre-hash signature of the message.
 //
-// Panics if the key is not a [PublicKey] or when the [ID] mismatches.
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, or when
+// the pre-hash function is not supported.
 func (s scheme) Verify(
 	pub sign.PublicKey, message, signature []byte, options *sign.SignatureOpts,
 ) bool {
@@ -66,7 +89,134 @@ func (s scheme) Verify(
 		context = []byte(options.Context)
 	}
 
-	return Verify(&k, NewMessage(message), signature, context)
+	return Verify(&k, buildMessage(message, options), signature, context)
+}
+
+// NewStreamSigner returns a signer that creates randomized signatures with
+// the context given. If options selects a pre-hash function, the message is
+// hashed as it is written, otherwise, it is buffered in memory.
+//
+// Panics if the key is not a [PrivateKey], when the [ID] mismatches, or when
+// the pre-hash function is not supported.
+func (s scheme) NewStreamSigner(
+	priv sign.PrivateKey, options *sign.SignatureOpts,
+) sign.StreamSigner {
+	k, ok := priv.(PrivateKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	signMessage := func(msg *Message) []byte {
+		sig, err := SignRandomized(&k, rand.Reader, msg, context)
+		if err != nil {
+			return nil
+		}
+		return sig
+	}
+
+	ph := newPreHash(options)
+	if ph == nil {
+		return stream.NewBufferedSigner(func(message []byte) []byte {
+			return signMessage(NewMessage(message))
+		})
+	}
+
+	return &stream.Signer{Writer: ph, SignFunc: func() []byte {
+		msg, err := ph.BuildMessage()
+		if err != nil {
+			return nil
+		}
+		return signMessage(msg)
+	}}
+}
+
+// NewStreamVerifier returns a verifier for signatures with the context
+// given. If options selects a pre-hash function, the message is hashed as it
+// is written, otherwise, it is buffered in memory.
+//
+// Panics if the key is not a [PublicKey], when the [ID] mismatches, or when
+// the pre-hash function is not supported.
+func (s scheme) NewStreamVerifier(
+	pub sign.PublicKey, options *sign.SignatureOpts,
+) sign.StreamVerifier {
+	k, ok := pub.(PublicKey)
+	if !ok || s.ID != k.ID {
+		panic(sign.ErrTypeMismatch)
+	}
+
+	var context []byte
+	if options != nil {
+		context = []byte(options.Context)
+	}
+
+	ph := newPreHash(options)
+	if ph == nil {
+		return stream.NewBufferedVerifier(func(message, signature []byte) bool {
+			return Verify(&k, NewMessage(message), signature, context)
+		})
+	}
+
+	return &stream.Verifier{Writer: ph, VerifyFunc: func(signature []byte) bool {
+		msg, err := ph.BuildMessage()
+		if err != nil {
+			return false
+		}
+		return Verify(&k, msg, signature, context)
+	}}
+}
+
+// buildMessage returns the [Message] to be signed, which is pre-hashed
+// if requested in options.
+//
+// Panics if the pre-hash function is not supported.
+func buildMessage(message []byte, options *sign.SignatureOpts) *Message {
+	ph := newPreHash(options)
+	if ph == nil {
+		return NewMessage(message)
+	}
+
+	_, err := ph.Write(message)
+	if err != nil {
+		panic(err)
+	}
+
+	msg, err := ph.BuildMessage()
+	if err != nil {
+		panic(err)
+	}
+
+	return msg
+}
+
+// newPreHash returns the [PreHash] requested in options, or nil if no
+// pre-hash function is requested.
+//
+// Panics if the pre-hash function is not supported.
+func newPreHash(options *sign.SignatureOpts) *PreHash {
+	if options == nil || options.PreHash.IsZero() {
+		return nil
+	}
+
+	var ph *PreHash
+	var err error
+	switch {
+	case options.PreHash.Hash != 0 && options.PreHash.Xof != 0:
+		err = ErrPreHash
+	case options.PreHash.Xof != 0:
+		ph, err = NewPreHashWithXof(options.PreHash.Xof)
+	default:
+		ph, err = NewPreHashWithHash(options.PreHash.Hash)
+	}
+	if err != nil {
+		panic(sign.ErrPreHashNotSupported)
+	}
+
+	return ph
 }
 
 // DeriveKey deterministically generates a pair of keys from a seed.
@@ -3,6 +3,7 @@ package slhdsa_test
 import (
 	"crypto"
 	"crypto/rand"
+	"encoding/asn1"
 	"io"
 	"testing"
 
@@ -146,3 +147,23 @@ func benchmarkSlhdsa(b *testing.B, sigIDs []slhdsa.ID) {
 		})
 	}
 }
+
+func TestOid(t *testing.T) {
+	for _, v := range []struct {
+		id   slhdsa.ID
+		want string
+	}{
+		{slhdsa.SHA2_128s, "2.16.840.1.101.3.4.3.20"},
+		{slhdsa.SHA2_128f, "2.16.840.1.101.3.4.3.21"},
+		{slhdsa.SHA2_256f, "2.16.840.1.101.3.4.3.25"},
+		{slhdsa.SHAKE_128s, "2.16.840.1.101.3.4.3.26"},
+		{slhdsa.SHAKE_192f, "2.16.840.1.101.3.4.3.29"},
+		{slhdsa.SHAKE_256f, "2.16.840.1.101.3.4.3.31"},
+	} {
+		scheme := v.id.Scheme().(interface{ Oid() asn1.ObjectIdentifier })
+		got := scheme.Oid().String()
+		if got != v.want {
+			test.ReportError(t, got, v.want, v.id)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package xmss
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+)
+
+func TestParams(t *testing.T) {
+	// See RFC 8391 -- Sections 5.3 and 5.4.
+	for _, v := range []struct {
+		p           *params
+		name        string
+		sig, pubKey int
+	}{
+		{XMSS_SHA2_10_256.params(), "XMSS-SHA2_10_256", 2500, 68},
+		{XMSS_SHA2_10_512.params(), "XMSS-SHA2_10_512", 9092, 132},
+		{XMSS_SHAKE256_16_192.params(), "XMSS-SHAKE256_16_192", 1636, 52},
+		{XMSSMT_SHA2_20_2_256.params(), "XMSSMT-SHA2_20/2_256", 4963, 68},
+		{XMSSMT_SHA2_60_12_256.params(), "XMSSMT-SHA2_60/12_256", 27688, 68},
+		{XMSSMT_SHAKE_40_8_512.params(), "XMSSMT-SHAKE_40/8_512", 69701, 132},
+		{XMSSMT_SHAKE256_60_3_192.params(), "XMSSMT-SHAKE256_60/3_192", 5144, 52},
+	} {
+		test.CheckOk(v.p.name == v.name, "wrong name "+v.p.name, t)
+		if got := v.p.SignatureSize(); got != v.sig {
+			test.ReportError(t, got, v.sig, v.name)
+		}
+		if got := v.p.PublicKeySize(); got != v.pubKey {
+			test.ReportError(t, got, v.pubKey, v.name)
+		}
+	}
+}
+
+func TestBaseW(t *testing.T) {
+	c := ctx{params: XMSS_SHA2_10_256.params()}
+	msg := make([]byte, 32)
+	msg[0] = 0x12
+	d := c.baseW(msg)
+	test.CheckOk(len(d) == 67 && d[0] == 1 && d[1] == 2 && d[2] == 0, "wrong digits", t)
+
+	// The checksum is 15*64 - 3 = 957 = 0x3bd.
+	test.CheckOk(d[64] == 0x3 && d[65] == 0xb && d[66] == 0xd, "wrong checksum", t)
+}
+
+func TestCachedHeight(t *testing.T) {
+	defer func(h int) { maxCachedHeight = h }(maxCachedHeight)
+
+	p := XMSSMT_SHA2_20_4_256.params()
+	c := &ctx{params: p, skSeed: make([]byte, p.n), pubSeed: make([]byte, p.n)}
+	msg := make([]byte, p.n)
+
+	maxCachedHeight = 2
+	tr := newTree(c, 1, 0)
+	test.CheckOk(tr.cut == 3, "wrong cut", t)
+	for _, q := range []uint32{0, 1, 17, 31} {
+		sig := tr.sign(q, msg)
+		root := c.rootFromSig(1, 0, q, sig, msg)
+		if !bytes.Equal(root, tr.root()) {
+			test.ReportError(t, root, tr.root(), q)
+		}
+	}
+}
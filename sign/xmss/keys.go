@@ -0,0 +1,244 @@
+package xmss
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/cloudflare/circl/sign/internal/stateful"
+)
+
+// [PublicKey] stores an XMSS or XMSS^MT public key.
+// It implements the [crypto.PublicKey] interface.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PublicKey struct {
+	// MultiTree is true for XMSS^MT public keys. As the OIDs of XMSS and
+	// XMSS^MT overlap, the caller must set it before unmarshaling an
+	// XMSS^MT public key.
+	MultiTree bool
+
+	params  *params
+	root    []byte
+	pubSeed []byte
+}
+
+// MarshalBinary returns OID || root || SEED, as in RFC 8391 -- Section 4.1.7.
+func (k *PublicKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, k.params.oid)
+	b = append(b, k.root...)
+	return append(b, k.pubSeed...), nil
+}
+
+// UnmarshalBinary recovers a [PublicKey] from a slice of bytes.
+// Example:
+//
+//	key := PublicKey{MultiTree: true}
+//	key.UnmarshalBinary(bytes) // returns nil for an XMSS^MT key
+func (k *PublicKey) UnmarshalBinary(b []byte) error {
+	p := oidParams(b, k.MultiTree)
+	if p == nil || len(b) != p.PublicKeySize() {
+		return errors.New("sign/xmss: invalid public key")
+	}
+
+	k.params = p
+	k.root = bytes.Clone(b[4 : 4+p.n])
+	k.pubSeed = bytes.Clone(b[4+p.n:])
+	return nil
+}
+
+// oidParams returns the parameters of the OID at the start of b, or nil
+// if the OID is not supported.
+func oidParams(b []byte, mt bool) *params {
+	if len(b) < 4 {
+		return nil
+	}
+
+	oid := binary.BigEndian.Uint32(b)
+	switch {
+	case mt && MTID(oid).IsValid():
+		return MTID(oid).params()
+	case !mt && ID(oid).IsValid():
+		return ID(oid).params()
+	default:
+		return nil
+	}
+}
+
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && k.params.name == other.params.name &&
+		bytes.Equal(k.root, other.root) && bytes.Equal(k.pubSeed, other.pubSeed)
+}
+
+// SignatureSize returns the size of signatures.
+func (k *PublicKey) SignatureSize() int { return k.params.SignatureSize() }
+
+// [PrivateKey] stores an XMSS or XMSS^MT private key, including its counter
+// of one-time keys. It is safe for concurrent use.
+// It implements the [crypto.Signer] and [crypto.PrivateKey] interfaces.
+// For serialization, it also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type PrivateKey struct {
+	// MultiTree is true for XMSS^MT private keys. The caller must set it
+	// before unmarshaling an XMSS^MT private key.
+	MultiTree bool
+
+	params  *params
+	seed    []byte // SK_SEED || SK_PRF || SEED.
+	counter stateful.Counter
+
+	mu    sync.Mutex
+	ctx   *ctx
+	trees []*tree // The current tree of each layer.
+}
+
+// init sets the parameters and the seed of the private key, computes its
+// top-level tree, and sets the counter to next.
+func (k *PrivateKey) init(p *params, seed []byte, next uint64) {
+	k.MultiTree = p.mt
+	k.params = p
+	k.seed = seed
+	k.ctx = &ctx{params: p, skSeed: seed[:p.n], pubSeed: seed[2*p.n:]}
+	k.trees = make([]*tree, p.d)
+	k.trees[p.d-1] = newTree(k.ctx, uint32(p.d-1), 0)
+	k.counter.Init(next, 1<<p.h)
+}
+
+func (k *PrivateKey) root() []byte { return k.trees[k.params.d-1].root() }
+
+// sign returns the signature of message with the idx-th one-time key, as
+// in RFC 8391 -- Algorithms 12 and 16.
+func (k *PrivateKey) sign(idx uint64, message []byte) []byte {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	p := k.params
+	r := make([]byte, p.n)
+	p.hash(r, padPRF, k.seed[p.n:2*p.n], toByte(idx, 32))
+	msg := k.ctx.messageHash(r, k.root(), idx, message)
+
+	sig := append(toByte(idx, p.idxLen), r...)
+	hp := p.treeHeight()
+	for j := range p.d {
+		leaf := uint32(idx & (1<<hp - 1))
+		idx >>= hp
+		if k.trees[j] == nil || k.trees[j].index != idx {
+			k.trees[j] = newTree(k.ctx, uint32(j), idx)
+		}
+		sig = append(sig, k.trees[j].sign(leaf, msg)...)
+		msg = k.trees[j].root()
+	}
+	return sig
+}
+
+// [PrivateKey.Reserve] hands out the next n one-time keys, advancing the
+// counter of the private key. The private key must be persisted before
+// releasing signatures made with the [Reservation].
+// It returns [ErrExhausted] if fewer than n one-time keys are left.
+func (k *PrivateKey) Reserve(n uint64) (*Reservation, error) {
+	r, err := k.counter.Reserve(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reservation{k, r}, nil
+}
+
+// Remaining returns the number of one-time keys that were not handed out.
+func (k *PrivateKey) Remaining() uint64 { return k.counter.Remaining() }
+
+// [PrivateKey.Sign] returns a signature of the message with the next
+// one-time key. The private key must be persisted before releasing the
+// signature. Pre-hashed messages are not supported, so opts.HashFunc()
+// must be zero. The random source is not used.
+// It returns [ErrExhausted] if no one-time key is left.
+func (k *PrivateKey) Sign(
+	random io.Reader, message []byte, opts crypto.SignerOpts,
+) (signature []byte, err error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, ErrPreHash
+	}
+
+	r, err := k.Reserve(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Sign(message)
+}
+
+func (k *PrivateKey) Public() crypto.PublicKey { return k.PublicKey() }
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{k.MultiTree, k.params, k.root(), k.ctx.pubSeed}
+}
+
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+
+	a, _ := k.MarshalBinary()
+	b, _ := other.MarshalBinary()
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// MarshalBinary returns the OID, the counter and the seeds of the private
+// key. The format is specific to this package:
+//
+//	OID || toByte(counter, 8) || SK_SEED || SK_PRF || SEED
+func (k *PrivateKey) MarshalBinary() ([]byte, error) {
+	b := binary.BigEndian.AppendUint32(nil, k.params.oid)
+	b = binary.BigEndian.AppendUint64(b, k.counter.Next())
+	return append(b, k.seed...), nil
+}
+
+// UnmarshalBinary recovers a [PrivateKey] from a slice of bytes, including
+// its counter of one-time keys.
+// As it recomputes the top-level tree, it is as slow as key generation.
+// Example:
+//
+//	key := PrivateKey{MultiTree: true}
+//	key.UnmarshalBinary(bytes) // returns nil for an XMSS^MT key
+func (k *PrivateKey) UnmarshalBinary(b []byte) error {
+	p := oidParams(b, k.MultiTree)
+	if p == nil || len(b) != 12+3*p.n {
+		return errors.New("sign/xmss: invalid private key")
+	}
+
+	next := binary.BigEndian.Uint64(b[4:])
+	if next > 1<<p.h {
+		return errors.New("sign/xmss: invalid private key")
+	}
+
+	k.init(p, bytes.Clone(b[12:]), next)
+	return nil
+}
+
+// [Reservation] is a set of one-time keys handed out by a [PrivateKey].
+// It is safe for concurrent use.
+type Reservation struct {
+	key     *PrivateKey
+	indices *stateful.Range
+}
+
+// [Reservation.Sign] returns a signature of the message with the next
+// one-time key of the reservation.
+// It returns [ErrExhausted] if all the one-time keys of the reservation
+// were used.
+func (r *Reservation) Sign(message []byte) ([]byte, error) {
+	idx, err := r.indices.Take()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.key.sign(idx, message), nil
+}
+
+// Remaining returns the number of one-time keys left in the reservation.
+func (r *Reservation) Remaining() uint64 { return r.indices.Remaining() }
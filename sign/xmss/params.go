@@ -0,0 +1,302 @@
+package xmss
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cloudflare/circl/internal/sha3"
+)
+
+// [ID] identifies the parameters of XMSS, by the OID of RFC 8391 and NIST
+// SP 800-208.
+type ID uint32
+
+//nolint:stylecheck
+const (
+	XMSS_SHA2_10_256     ID = 0x01
+	XMSS_SHA2_16_256     ID = 0x02
+	XMSS_SHA2_20_256     ID = 0x03
+	XMSS_SHA2_10_512     ID = 0x04
+	XMSS_SHA2_16_512     ID = 0x05
+	XMSS_SHA2_20_512     ID = 0x06
+	XMSS_SHAKE_10_256    ID = 0x07
+	XMSS_SHAKE_16_256    ID = 0x08
+	XMSS_SHAKE_20_256    ID = 0x09
+	XMSS_SHAKE_10_512    ID = 0x0a
+	XMSS_SHAKE_16_512    ID = 0x0b
+	XMSS_SHAKE_20_512    ID = 0x0c
+	XMSS_SHA2_10_192     ID = 0x0d
+	XMSS_SHA2_16_192     ID = 0x0e
+	XMSS_SHA2_20_192     ID = 0x0f
+	XMSS_SHAKE256_10_256 ID = 0x10
+	XMSS_SHAKE256_16_256 ID = 0x11
+	XMSS_SHAKE256_20_256 ID = 0x12
+	XMSS_SHAKE256_10_192 ID = 0x13
+	XMSS_SHAKE256_16_192 ID = 0x14
+	XMSS_SHAKE256_20_192 ID = 0x15
+)
+
+// [MTID] identifies the parameters of XMSS^MT, by the OID of RFC 8391 and
+// NIST SP 800-208.
+type MTID uint32
+
+//nolint:stylecheck
+const (
+	XMSSMT_SHA2_20_2_256      MTID = 0x01
+	XMSSMT_SHA2_20_4_256      MTID = 0x02
+	XMSSMT_SHA2_40_2_256      MTID = 0x03
+	XMSSMT_SHA2_40_4_256      MTID = 0x04
+	XMSSMT_SHA2_40_8_256      MTID = 0x05
+	XMSSMT_SHA2_60_3_256      MTID = 0x06
+	XMSSMT_SHA2_60_6_256      MTID = 0x07
+	XMSSMT_SHA2_60_12_256     MTID = 0x08
+	XMSSMT_SHA2_20_2_512      MTID = 0x09
+	XMSSMT_SHA2_20_4_512      MTID = 0x0a
+	XMSSMT_SHA2_40_2_512      MTID = 0x0b
+	XMSSMT_SHA2_40_4_512      MTID = 0x0c
+	XMSSMT_SHA2_40_8_512      MTID = 0x0d
+	XMSSMT_SHA2_60_3_512      MTID = 0x0e
+	XMSSMT_SHA2_60_6_512      MTID = 0x0f
+	XMSSMT_SHA2_60_12_512     MTID = 0x10
+	XMSSMT_SHAKE_20_2_256     MTID = 0x11
+	XMSSMT_SHAKE_20_4_256     MTID = 0x12
+	XMSSMT_SHAKE_40_2_256     MTID = 0x13
+	XMSSMT_SHAKE_40_4_256     MTID = 0x14
+	XMSSMT_SHAKE_40_8_256     MTID = 0x15
+	XMSSMT_SHAKE_60_3_256     MTID = 0x16
+	XMSSMT_SHAKE_60_6_256     MTID = 0x17
+	XMSSMT_SHAKE_60_12_256    MTID = 0x18
+	XMSSMT_SHAKE_20_2_512     MTID = 0x19
+	XMSSMT_SHAKE_20_4_512     MTID = 0x1a
+	XMSSMT_SHAKE_40_2_512     MTID = 0x1b
+	XMSSMT_SHAKE_40_4_512     MTID = 0x1c
+	XMSSMT_SHAKE_40_8_512     MTID = 0x1d
+	XMSSMT_SHAKE_60_3_512     MTID = 0x1e
+	XMSSMT_SHAKE_60_6_512     MTID = 0x1f
+	XMSSMT_SHAKE_60_12_512    MTID = 0x20
+	XMSSMT_SHA2_20_2_192      MTID = 0x21
+	XMSSMT_SHA2_20_4_192      MTID = 0x22
+	XMSSMT_SHA2_40_2_192      MTID = 0x23
+	XMSSMT_SHA2_40_4_192      MTID = 0x24
+	XMSSMT_SHA2_40_8_192      MTID = 0x25
+	XMSSMT_SHA2_60_3_192      MTID = 0x26
+	XMSSMT_SHA2_60_6_192      MTID = 0x27
+	XMSSMT_SHA2_60_12_192     MTID = 0x28
+	XMSSMT_SHAKE256_20_2_256  MTID = 0x29
+	XMSSMT_SHAKE256_20_4_256  MTID = 0x2a
+	XMSSMT_SHAKE256_40_2_256  MTID = 0x2b
+	XMSSMT_SHAKE256_40_4_256  MTID = 0x2c
+	XMSSMT_SHAKE256_40_8_256  MTID = 0x2d
+	XMSSMT_SHAKE256_60_3_256  MTID = 0x2e
+	XMSSMT_SHAKE256_60_6_256  MTID = 0x2f
+	XMSSMT_SHAKE256_60_12_256 MTID = 0x30
+	XMSSMT_SHAKE256_20_2_192  MTID = 0x31
+	XMSSMT_SHAKE256_20_4_192  MTID = 0x32
+	XMSSMT_SHAKE256_40_2_192  MTID = 0x33
+	XMSSMT_SHAKE256_40_4_192  MTID = 0x34
+	XMSSMT_SHAKE256_40_8_192  MTID = 0x35
+	XMSSMT_SHAKE256_60_3_192  MTID = 0x36
+	XMSSMT_SHAKE256_60_6_192  MTID = 0x37
+	XMSSMT_SHAKE256_60_12_192 MTID = 0x38
+)
+
+func (id ID) IsValid() bool   { return XMSS_SHA2_10_256 <= id && id <= XMSS_SHAKE256_20_192 }
+func (id MTID) IsValid() bool { return XMSSMT_SHA2_20_2_256 <= id && id <= XMSSMT_SHAKE256_60_12_192 }
+
+func (id ID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return id.params().name
+}
+
+func (id MTID) String() string {
+	if !id.IsValid() {
+		return ErrParam.Error()
+	}
+	return id.params().name
+}
+
+// hashFn identifies the hash function used to instantiate the functions
+// F, H, H_msg, PRF and PRF_keygen.
+type hashFn byte
+
+const (
+	hashSHA256 hashFn = iota
+	hashSHA512
+	hashSHAKE128
+	hashSHAKE256
+)
+
+// family contains the hash function, output length and padding length
+// of a group of parameter sets, see RFC 8391 -- Section 5 and NIST
+// SP 800-208 -- Section 5.
+type family struct {
+	name   string
+	hash   hashFn
+	n      int
+	padLen int
+}
+
+var (
+	sha2n32     = family{"SHA2_%v_256", hashSHA256, 32, 32}
+	sha2n64     = family{"SHA2_%v_512", hashSHA512, 64, 64}
+	shakeN32    = family{"SHAKE_%v_256", hashSHAKE128, 32, 32}
+	shakeN64    = family{"SHAKE_%v_512", hashSHAKE256, 64, 64}
+	sha2n24     = family{"SHA2_%v_192", hashSHA256, 24, 4}
+	shake256n32 = family{"SHAKE256_%v_256", hashSHAKE256, 32, 32}
+	shake256n24 = family{"SHAKE256_%v_192", hashSHAKE256, 24, 4}
+)
+
+// params contains all the relevant constants of a parameter set.
+type params struct {
+	family
+	name   string
+	oid    uint32
+	mt     bool // XMSS^MT parameter set.
+	h      int  // Total height.
+	d      int  // Number of layers.
+	idxLen int  // Size of the index in signatures.
+}
+
+// treeHeight returns the height of the trees of each layer.
+func (p *params) treeHeight() int { return p.h / p.d }
+
+// WOTS+ lengths for w = 16, see RFC 8391 -- Section 3.1.1.
+func (p *params) len1() int { return 2 * p.n }
+func (p *params) len2() int { return 3 }
+func (p *params) wlen() int { return p.len1() + p.len2() }
+
+func (p *params) PublicKeySize() int { return 4 + 2*p.n }
+
+func (p *params) SignatureSize() int {
+	return p.idxLen + p.n + (p.h+p.d*p.wlen())*p.n
+}
+
+func (id ID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+
+	families := [...]family{
+		sha2n32, sha2n64, shakeN32, shakeN64, sha2n24, shake256n32, shake256n24,
+	}
+	i := int(id - XMSS_SHA2_10_256)
+	f := families[i/3]
+	h := [...]int{10, 16, 20}[i%3]
+	return &params{
+		family: f,
+		name:   "XMSS-" + fmt.Sprintf(f.name, h),
+		oid:    uint32(id),
+		h:      h,
+		d:      1,
+		idxLen: 4,
+	}
+}
+
+func (id MTID) params() *params {
+	if !id.IsValid() {
+		panic(ErrParam)
+	}
+
+	families := [...]family{
+		sha2n32, sha2n64, shakeN32, shakeN64, sha2n24, shake256n32, shake256n24,
+	}
+	heights := [...][2]int{
+		{20, 2}, {20, 4}, {40, 2}, {40, 4}, {40, 8}, {60, 3}, {60, 6}, {60, 12},
+	}
+	i := int(id - XMSSMT_SHA2_20_2_256)
+	f := families[i/8]
+	hd := heights[i%8]
+	return &params{
+		family: f,
+		name:   "XMSSMT-" + fmt.Sprintf(f.name, fmt.Sprintf("%v/%v", hd[0], hd[1])),
+		oid:    uint32(id),
+		mt:     true,
+		h:      hd[0],
+		d:      hd[1],
+		idxLen: (hd[0] + 7) / 8,
+	}
+}
+
+// Padding values of the hash functions, see RFC 8391 -- Section 5.1 and
+// NIST SP 800-208 -- Section 5.
+const (
+	padF = iota
+	padH
+	padHmsg
+	padPRF
+	padPRFKeygen
+)
+
+// hash writes to out the first n bytes of Hash(toByte(pad, padLen) || key
+// || m[0] || m[1] || ...).
+func (p *params) hash(out []byte, pad byte, key []byte, m ...[]byte) {
+	size := p.padLen + len(key)
+	for _, mi := range m {
+		size += len(mi)
+	}
+
+	in := make([]byte, p.padLen, size)
+	in[p.padLen-1] = pad
+	in = append(in, key...)
+	for _, mi := range m {
+		in = append(in, mi...)
+	}
+
+	switch p.family.hash {
+	case hashSHA256:
+		s := sha256.Sum256(in)
+		copy(out[:p.n], s[:])
+	case hashSHA512:
+		s := sha512.Sum512(in)
+		copy(out[:p.n], s[:])
+	case hashSHAKE128:
+		sha3.ShakeSum128(out[:p.n], in)
+	case hashSHAKE256:
+		sha3.ShakeSum256(out[:p.n], in)
+	}
+}
+
+// toByte returns the big-endian encoding of x in n bytes.
+func toByte(x uint64, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0 && x > 0; i-- {
+		b[i] = byte(x)
+		x >>= 8
+	}
+	return b
+}
+
+// address is the 32-byte hash address of RFC 8391 -- Section 2.5.
+type address [8]uint32
+
+const (
+	addrOTS = iota
+	addrLTree
+	addrHashTree
+)
+
+func (a *address) setLayer(l uint32) { a[0] = l }
+func (a *address) setTree(t uint64)  { a[1], a[2] = uint32(t>>32), uint32(t) }
+func (a *address) setType(t uint32) {
+	a[3] = t
+	a[4], a[5], a[6], a[7] = 0, 0, 0, 0
+}
+func (a *address) setOTS(i uint32)        { a[4] = i }
+func (a *address) setLTree(i uint32)      { a[4] = i }
+func (a *address) setChain(i uint32)      { a[5] = i }
+func (a *address) setTreeHeight(i uint32) { a[5] = i }
+func (a *address) setHash(i uint32)       { a[6] = i }
+func (a *address) setTreeIndex(i uint32)  { a[6] = i }
+func (a *address) setKeyAndMask(i uint32) { a[7] = i }
+
+func (a *address) bytes() []byte {
+	b := make([]byte, 32)
+	for i, w := range a {
+		binary.BigEndian.PutUint32(b[4*i:], w)
+	}
+	return b
+}
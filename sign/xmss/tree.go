@@ -0,0 +1,148 @@
+package xmss
+
+// See RFC 8391 -- Section 4.1
+// XMSS trees
+
+// maxCachedHeight is the height of the top part of a tree that is kept in
+// memory. The authentication path of a leaf below it is obtained by
+// recomputing the subtree containing the leaf.
+var maxCachedHeight = 15
+
+// tree is a tree of a layer of XMSS^MT, or the tree of XMSS, which caches
+// its nodes to speed up signing.
+type tree struct {
+	*ctx
+	layer uint32
+	index uint64 // Index of the tree within its layer.
+
+	// The nodes of height at least cut: nodes[j-cut][i] is the i-th node of
+	// height j.
+	cut   int
+	nodes [][][]byte
+
+	// The last subtree of height cut computed, rooted at the node of height
+	// cut with index subIndex.
+	subIndex uint32
+	subtree  [][][]byte
+}
+
+func newTree(c *ctx, layer uint32, index uint64) *tree {
+	t := &tree{ctx: c, layer: layer, index: index}
+	hp := c.treeHeight()
+	t.cut = max(0, hp-maxCachedHeight)
+
+	t.nodes = make([][][]byte, hp-t.cut+1)
+	t.nodes[0] = make([][]byte, 1<<(hp-t.cut))
+	for i := range t.nodes[0] {
+		t.nodes[0][i] = t.computeSubtree(uint32(i))[t.cut][0]
+	}
+	for j := 1; j < len(t.nodes); j++ {
+		t.nodes[j] = t.parents(t.cut+j-1, t.nodes[j-1], 0)
+	}
+	return t
+}
+
+func (t *tree) address(typ uint32) (a address) {
+	a.setLayer(t.layer)
+	a.setTree(t.index)
+	a.setType(typ)
+	return
+}
+
+// leaf returns the i-th leaf of the tree.
+func (t *tree) leaf(i uint32) []byte {
+	a := t.address(addrOTS)
+	a.setOTS(i)
+	pk := t.wotsPublicKey(a)
+
+	a = t.address(addrLTree)
+	a.setLTree(i)
+	return t.ltree(pk, a)
+}
+
+// parents returns the nodes of height j+1 from the nodes of height j, the
+// first of which has index first.
+func (t *tree) parents(j int, children [][]byte, first uint32) [][]byte {
+	a := t.address(addrHashTree)
+	a.setTreeHeight(uint32(j))
+	nodes := make([][]byte, len(children)/2)
+	for i := range nodes {
+		a.setTreeIndex(first/2 + uint32(i))
+		nodes[i] = t.randHash(children[2*i], children[2*i+1], a)
+	}
+	return nodes
+}
+
+// computeSubtree returns the nodes of the subtree of height cut, whose
+// root is the s-th node of height cut: nodes[j][i] is its i-th node of
+// height j.
+func (t *tree) computeSubtree(s uint32) [][][]byte {
+	nodes := make([][][]byte, t.cut+1)
+	nodes[0] = make([][]byte, 1<<t.cut)
+	first := s << t.cut
+	for i := range nodes[0] {
+		nodes[0][i] = t.leaf(first + uint32(i))
+	}
+	for j := 1; j <= t.cut; j++ {
+		nodes[j] = t.parents(j-1, nodes[j-1], first>>(j-1))
+	}
+	return nodes
+}
+
+// node returns the i-th node of height j, which must be at height below
+// cut only if it belongs to the subtree containing leaf q.
+func (t *tree) node(j int, i, q uint32) []byte {
+	if j >= t.cut {
+		return t.nodes[j-t.cut][i]
+	}
+
+	s := q >> t.cut
+	if t.subtree == nil || t.subIndex != s {
+		t.subIndex, t.subtree = s, t.computeSubtree(s)
+	}
+
+	return t.subtree[j][i-s<<(t.cut-j)]
+}
+
+func (t *tree) root() []byte { return t.nodes[len(t.nodes)-1][0] }
+
+// sign returns the WOTS+ signature of the n-byte msg with the q-th leaf,
+// followed by the authentication path of the leaf, as in RFC 8391 --
+// Algorithm 11.
+func (t *tree) sign(q uint32, msg []byte) []byte {
+	a := t.address(addrOTS)
+	a.setOTS(q)
+	sig := t.wotsSign(msg, a)
+	for j := range t.treeHeight() {
+		sig = append(sig, t.node(j, (q>>j)^1, q)...)
+	}
+	return sig
+}
+
+// rootFromSig returns the root of a tree computed from the signature of
+// msg with its q-th leaf, as in RFC 8391 -- Algorithm 13.
+func (c *ctx) rootFromSig(layer uint32, index uint64, q uint32, sig, msg []byte) []byte {
+	t := tree{ctx: c, layer: layer, index: index}
+	a := t.address(addrOTS)
+	a.setOTS(q)
+	size := c.wlen() * c.n
+	pk := c.wotsPublicKeyFromSig(sig[:size], msg, a)
+
+	a = t.address(addrLTree)
+	a.setLTree(q)
+	node := c.ltree(pk, a)
+
+	auth := sig[size:]
+	a = t.address(addrHashTree)
+	for k := range c.treeHeight() {
+		a.setTreeHeight(uint32(k))
+		a.setTreeIndex(q >> (k + 1))
+		sibling := auth[k*c.n : (k+1)*c.n]
+		if (q>>k)&1 == 0 {
+			node = c.randHash(node, sibling, a)
+		} else {
+			node = c.randHash(sibling, node, a)
+		}
+	}
+	return node
+}
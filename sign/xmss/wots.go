@@ -0,0 +1,142 @@
+package xmss
+
+// See RFC 8391 -- Section 3
+// WOTS+ One-Time Signatures
+
+// ctx holds the seeds used by the hash functions.
+type ctx struct {
+	*params
+	pubSeed []byte
+	skSeed  []byte // Only set for private operations.
+}
+
+const w = 16
+
+// prf returns PRF(SEED, ADRS).
+func (c *ctx) prf(out []byte, a *address) {
+	c.hash(out, padPRF, c.pubSeed, a.bytes())
+}
+
+// chain iterates the chain function on x in place, as in RFC 8391 --
+// Algorithm 2.
+func (c *ctx) chain(x []byte, start, steps int, a address) {
+	key := make([]byte, c.n)
+	bm := make([]byte, c.n)
+	for i := start; i < start+steps; i++ {
+		a.setHash(uint32(i))
+		a.setKeyAndMask(0)
+		c.prf(key, &a)
+		a.setKeyAndMask(1)
+		c.prf(bm, &a)
+		for j := range bm {
+			bm[j] ^= x[j]
+		}
+		c.hash(x, padF, key, bm)
+	}
+}
+
+// wotsSecret returns the secret value of the i-th chain, derived as in
+// NIST SP 800-208 -- Section 7.2.1.
+func (c *ctx) wotsSecret(i int, a address) []byte {
+	a.setChain(uint32(i))
+	a.setHash(0)
+	a.setKeyAndMask(0)
+	sk := make([]byte, c.n)
+	c.hash(sk, padPRFKeygen, c.skSeed, c.pubSeed, a.bytes())
+	return sk
+}
+
+// baseW returns the base-w digits of msg followed by those of its checksum,
+// as in RFC 8391 -- Algorithm 5.
+func (c *ctx) baseW(msg []byte) []int {
+	d := make([]int, c.wlen())
+	csum := 0
+	for i := range c.len1() {
+		d[i] = int(msg[i/2]>>(4*(1-i%2))) & (w - 1)
+		csum += w - 1 - d[i]
+	}
+
+	// The checksum is shifted left by 4 bits and encoded in 2 bytes, so its
+	// three digits are its three most significant nibbles.
+	csum <<= 4
+	for i := range c.len2() {
+		d[c.len1()+i] = (csum >> (12 - 4*i)) & (w - 1)
+	}
+	return d
+}
+
+// wotsPublicKey returns the ends of the chains of a one-time key.
+func (c *ctx) wotsPublicKey(a address) [][]byte {
+	pk := make([][]byte, c.wlen())
+	for i := range pk {
+		pk[i] = c.wotsSecret(i, a)
+		a.setChain(uint32(i))
+		c.chain(pk[i], 0, w-1, a)
+	}
+	return pk
+}
+
+// wotsSign returns the signature of the n-byte msg, as in RFC 8391 --
+// Algorithm 5.
+func (c *ctx) wotsSign(msg []byte, a address) []byte {
+	sig := make([]byte, 0, c.wlen()*c.n)
+	for i, d := range c.baseW(msg) {
+		sk := c.wotsSecret(i, a)
+		a.setChain(uint32(i))
+		c.chain(sk, 0, d, a)
+		sig = append(sig, sk...)
+	}
+	return sig
+}
+
+// wotsPublicKeyFromSig computes a public key candidate, as in RFC 8391 --
+// Algorithm 6.
+func (c *ctx) wotsPublicKeyFromSig(sig, msg []byte, a address) [][]byte {
+	pk := make([][]byte, c.wlen())
+	for i, d := range c.baseW(msg) {
+		pk[i] = append([]byte{}, sig[i*c.n:(i+1)*c.n]...)
+		a.setChain(uint32(i))
+		c.chain(pk[i], d, w-1-d, a)
+	}
+	return pk
+}
+
+// randHash returns RAND_HASH(left, right, SEED, ADRS), see RFC 8391 --
+// Algorithm 7.
+func (c *ctx) randHash(left, right []byte, a address) []byte {
+	key := make([]byte, c.n)
+	bm := make([]byte, 2*c.n)
+	a.setKeyAndMask(0)
+	c.prf(key, &a)
+	a.setKeyAndMask(1)
+	c.prf(bm[:c.n], &a)
+	a.setKeyAndMask(2)
+	c.prf(bm[c.n:], &a)
+	for j := range c.n {
+		bm[j] ^= left[j]
+		bm[c.n+j] ^= right[j]
+	}
+
+	out := make([]byte, c.n)
+	c.hash(out, padH, key, bm)
+	return out
+}
+
+// ltree compresses a WOTS+ public key into a leaf, as in RFC 8391 --
+// Algorithm 8. It overwrites pk.
+func (c *ctx) ltree(pk [][]byte, a address) []byte {
+	l := len(pk)
+	a.setTreeHeight(0)
+	for height := uint32(0); l > 1; height++ {
+		a.setTreeHeight(height)
+		for i := range l / 2 {
+			a.setTreeIndex(uint32(i))
+			pk[i] = c.randHash(pk[2*i], pk[2*i+1], a)
+		}
+		if l%2 == 1 {
+			pk[l/2] = pk[l-1]
+		}
+		l = (l + 1) / 2
+	}
+	return pk[0]
+}
@@ -0,0 +1,131 @@
+// Package xmss provides the eXtended Merkle Signature Scheme XMSS and its
+// multi-tree variant XMSS^MT, which are stateful hash-based signature
+// schemes specified in RFC 8391 and NIST SP 800-208.
+//
+// Each private key contains a finite number of one-time keys, indexed by a
+// counter, and an index must never be used twice. As the counter is part
+// of the private key, the private key must be persisted after handing out
+// indices and before releasing the signatures made with them:
+//
+//	r, err := priv.Reserve(100)      // Advances the counter by 100.
+//	b, err := priv.MarshalBinary()   // Persists the new counter,
+//	err = store(b)                   // before signing.
+//	sig, err := r.Sign(message)      // Up to 100 signatures.
+//
+// Indices handed out by a [Reservation] that are not used are lost.
+// Signing with [PrivateKey.Sign] reserves a single index, so the private
+// key must be persisted before the signature is released.
+//
+// The WOTS+ secret keys are derived from a seed as in NIST SP 800-208.
+// The encoding of private keys is specific to this package, while public
+// keys and signatures follow RFC 8391.
+package xmss
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/cloudflare/circl/sign/internal/stateful"
+)
+
+var (
+	ErrParam   = errors.New("sign/xmss: invalid parameters")
+	ErrPreHash = errors.New("sign/xmss: pre-hashed messages are not supported")
+
+	// ErrExhausted is the error returned when no one-time key is left.
+	ErrExhausted = stateful.ErrExhausted
+)
+
+// [GenerateKey] returns a pair of XMSS keys with the parameters specified.
+// It returns an error if it fails reading from the random source.
+func GenerateKey(random io.Reader, id ID) (*PublicKey, *PrivateKey, error) {
+	return generateKey(random, id.params())
+}
+
+// [GenerateKeyMT] returns a pair of XMSS^MT keys with the parameters
+// specified. It returns an error if it fails reading from the random
+// source.
+func GenerateKeyMT(random io.Reader, id MTID) (*PublicKey, *PrivateKey, error) {
+	return generateKey(random, id.params())
+}
+
+func generateKey(random io.Reader, p *params) (*PublicKey, *PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	seed := make([]byte, 3*p.n)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, nil, err
+	}
+
+	priv := new(PrivateKey)
+	priv.init(p, seed, 0)
+	return priv.PublicKey(), priv, nil
+}
+
+// [NewKeyFromSeed] deterministically derives a pair of XMSS keys from a
+// seed, which is the concatenation of SK_SEED, SK_PRF and SEED, each of n
+// bytes.
+func NewKeyFromSeed(id ID, seed []byte) (*PublicKey, *PrivateKey, error) {
+	return newKeyFromSeed(id.params(), seed)
+}
+
+// [NewKeyFromSeedMT] deterministically derives a pair of XMSS^MT keys from
+// a seed, which is the concatenation of SK_SEED, SK_PRF and SEED, each of n
+// bytes.
+func NewKeyFromSeedMT(id MTID, seed []byte) (*PublicKey, *PrivateKey, error) {
+	return newKeyFromSeed(id.params(), seed)
+}
+
+func newKeyFromSeed(p *params, seed []byte) (*PublicKey, *PrivateKey, error) {
+	if len(seed) != 3*p.n {
+		return nil, nil, ErrParam
+	}
+
+	priv := new(PrivateKey)
+	priv.init(p, bytes.Clone(seed), 0)
+	return priv.PublicKey(), priv, nil
+}
+
+// [Verify] returns true if the signature of the message is valid, as in
+// RFC 8391 -- Algorithms 14 and 17.
+func Verify(pub *PublicKey, message, signature []byte) bool {
+	p := pub.params
+	if p == nil || len(signature) != p.SignatureSize() {
+		return false
+	}
+
+	var idx uint64
+	for _, b := range signature[:p.idxLen] {
+		idx = idx<<8 | uint64(b)
+	}
+	if idx>>p.h != 0 {
+		return false
+	}
+
+	r := signature[p.idxLen : p.idxLen+p.n]
+	c := &ctx{params: p, pubSeed: pub.pubSeed}
+	node := c.messageHash(r, pub.root, idx, message)
+
+	hp := p.treeHeight()
+	size := (c.wlen() + hp) * c.n
+	sig := signature[p.idxLen+p.n:]
+	for j := range p.d {
+		leaf := uint32(idx & (1<<hp - 1))
+		idx >>= hp
+		node = c.rootFromSig(uint32(j), idx, leaf, sig[:size], node)
+		sig = sig[size:]
+	}
+
+	return bytes.Equal(node, pub.root)
+}
+
+// messageHash returns H_msg(r || root || toByte(idx, n), message).
+func (c *ctx) messageHash(r, root []byte, idx uint64, message []byte) []byte {
+	out := make([]byte, c.n)
+	c.hash(out, padHmsg, r, root, toByte(idx, c.n), message)
+	return out
+}
@@ -0,0 +1,134 @@
+package xmss_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/circl/internal/test"
+	"github.com/cloudflare/circl/sign/xmss"
+)
+
+func TestXMSS(t *testing.T) {
+	for _, id := range []xmss.ID{xmss.XMSS_SHA2_10_256} {
+		t.Run(id.String(), func(t *testing.T) {
+			pub, priv, err := xmss.GenerateKey(rand.Reader, id)
+			test.CheckNoErr(t, err, "GenerateKey failed")
+			testSign(t, pub, priv, 3)
+			testMarshal(t, pub, priv)
+		})
+	}
+}
+
+func TestXMSSMT(t *testing.T) {
+	for _, id := range []xmss.MTID{
+		xmss.XMSSMT_SHA2_20_4_256,
+		xmss.XMSSMT_SHAKE_20_4_256,
+		xmss.XMSSMT_SHAKE256_20_4_192,
+	} {
+		t.Run(id.String(), func(t *testing.T) {
+			pub, priv, err := xmss.GenerateKeyMT(rand.Reader, id)
+			test.CheckNoErr(t, err, "GenerateKey failed")
+			testSign(t, pub, priv, 40)
+			testMarshal(t, pub, priv)
+		})
+	}
+}
+
+func testSign(t *testing.T, pub *xmss.PublicKey, priv *xmss.PrivateKey, n int) {
+	msg := []byte("Alice and Bob")
+	for range n {
+		sig, err := priv.Sign(nil, msg, crypto.Hash(0))
+		test.CheckNoErr(t, err, "Sign failed")
+		test.CheckOk(len(sig) == pub.SignatureSize(), "wrong signature size", t)
+		test.CheckOk(xmss.Verify(pub, msg, sig), "Verify failed", t)
+		test.CheckOk(!xmss.Verify(pub, msg[1:], sig), "Verify should fail", t)
+
+		for _, i := range []int{0, 7, len(sig) / 2, len(sig) - 1} {
+			sig[i] ^= 1
+			test.CheckOk(!xmss.Verify(pub, msg, sig), "Verify should fail", t)
+			sig[i] ^= 1
+		}
+		test.CheckOk(!xmss.Verify(pub, msg, sig[:len(sig)-1]), "Verify should fail", t)
+	}
+
+	_, err := priv.Sign(nil, msg, crypto.SHA256)
+	test.CheckIsErr(t, err, "Sign should fail with pre-hash")
+}
+
+func testMarshal(t *testing.T, pub *xmss.PublicKey, priv *xmss.PrivateKey) {
+	b, err := pub.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	pub2 := &xmss.PublicKey{MultiTree: pub.MultiTree}
+	test.CheckNoErr(t, pub2.UnmarshalBinary(b), "UnmarshalBinary failed")
+	test.CheckOk(pub.Equal(pub2), "public keys not equal", t)
+	test.CheckIsErr(t, pub2.UnmarshalBinary(b[:len(b)-1]), "UnmarshalBinary should fail")
+
+	b, err = priv.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	priv2 := &xmss.PrivateKey{MultiTree: priv.MultiTree}
+	test.CheckNoErr(t, priv2.UnmarshalBinary(b), "UnmarshalBinary failed")
+	test.CheckOk(priv.Equal(priv2), "private keys not equal", t)
+	test.CheckOk(priv2.Remaining() == priv.Remaining(), "wrong counter", t)
+	test.CheckOk(pub.Equal(priv2.Public()), "public keys not equal", t)
+	test.CheckIsErr(t, priv2.UnmarshalBinary(b[:len(b)-1]), "UnmarshalBinary should fail")
+}
+
+func TestReserve(t *testing.T) {
+	id := xmss.XMSSMT_SHA2_20_4_256
+	seed := make([]byte, 96)
+	pub, priv, err := xmss.NewKeyFromSeedMT(id, seed)
+	test.CheckNoErr(t, err, "NewKeyFromSeedMT failed")
+	test.CheckOk(priv.Remaining() == 1<<20, "wrong number of keys", t)
+
+	r, err := priv.Reserve(10)
+	test.CheckNoErr(t, err, "Reserve failed")
+
+	// The persisted key must not hand out the reserved indices again.
+	b, err := priv.MarshalBinary()
+	test.CheckNoErr(t, err, "MarshalBinary failed")
+	restored := &xmss.PrivateKey{MultiTree: true}
+	test.CheckNoErr(t, restored.UnmarshalBinary(b), "UnmarshalBinary failed")
+
+	msg := []byte("message")
+	seen := make(map[string]bool)
+	for range 10 {
+		sig, err := r.Sign(msg)
+		test.CheckNoErr(t, err, "Sign failed")
+		test.CheckOk(xmss.Verify(pub, msg, sig), "Verify failed", t)
+		seen[string(sig[:3])] = true
+	}
+	_, err = r.Sign(msg)
+	test.CheckOk(errors.Is(err, xmss.ErrExhausted), "Sign should fail", t)
+
+	sig, err := restored.Sign(nil, msg, nil)
+	test.CheckNoErr(t, err, "Sign failed")
+	test.CheckOk(xmss.Verify(pub, msg, sig), "Verify failed", t)
+	test.CheckOk(!seen[string(sig[:3])], "index used twice", t)
+
+	_, err = restored.Reserve(restored.Remaining() + 1)
+	test.CheckOk(errors.Is(err, xmss.ErrExhausted), "Reserve should fail", t)
+	_, err = restored.Reserve(restored.Remaining())
+	test.CheckNoErr(t, err, "Reserve failed")
+	_, err = restored.Sign(nil, msg, nil)
+	test.CheckOk(errors.Is(err, xmss.ErrExhausted), "Sign should fail", t)
+}
+
+func BenchmarkXMSS(b *testing.B) {
+	msg := []byte("Alice and Bob")
+	pub, priv, _ := xmss.GenerateKeyMT(rand.Reader, xmss.XMSSMT_SHA2_20_2_256)
+	r, _ := priv.Reserve(priv.Remaining())
+	sig, _ := r.Sign(msg)
+
+	b.Run("Sign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = r.Sign(msg)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = xmss.Verify(pub, msg, sig)
+		}
+	})
+}
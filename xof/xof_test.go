@@ -59,6 +59,18 @@ var allVectors = []vector{
 		out:    "b4f249b4f77c58df170aa4d1723db1127d82f1d98d25ddda561ada459cd11a48",
 		outLen: 32,
 	},
+	{
+		id:     xof.TURBOSHAKE128,
+		in:     "",
+		out:    "1e415f1c5983aff2169217277d17bb538cd945a397ddec541f1ce41af2c1b74c",
+		outLen: 32,
+	},
+	{
+		id:     xof.TURBOSHAKE256,
+		in:     "",
+		out:    "367a329dafea871c7802ec67f905ae13c57695dc2c6663c61035f59a18f8e7db11edc0e12e91ea60eb6b32df06dd7f002fbafabb6e13ec1cc20d995547600db0",
+		outLen: 64,
+	},
 }
 
 func TestXof(t *testing.T) {